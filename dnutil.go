@@ -6,11 +6,21 @@ dnutil is a library for easy handling of distinguished name.
 package dnutil
 
 import (
+	"bytes"
+	"crypto/sha256"
 	"encoding/asn1"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"math"
+	"sort"
 	"strconv"
 	"strings"
+	"sync"
+	"unicode"
+	"unicode/utf16"
 	"unicode/utf8"
 )
 
@@ -55,31 +65,42 @@ type AttributeValue struct {
 //	GenerationQualifier (2.5.4.44)
 //	ElectronicMailAddress (1.2.840.113549.1.9.1)
 //	DomainComponent (0.9.2342.19200300.100.1.25)
+//	UnstructuredName (1.2.840.113549.1.9.2)
+//	UnstructuredAddress (1.2.840.113549.1.9.8)
+//	StreetAddress (2.5.4.9)
+//	PostalCode (2.5.4.17)
+//	UserID (0.9.2342.19200300.100.1.1)
 //	Generic (Any OBJECT IDENTIFIER)
 //
 // Any object identifier can be specified by setting Generic to Type and object identifier to Oid.
 // If Type is Generic, Oid must be specified.
+// Other object identifiers can be registered as AttributeTypes with RegisterAttributeType.
 //
 // Currently, the following combinations of OBJECT IDENTIFIER for AttributeType
 // and Encoding for AttributeValue are supported:
 //
 //	CountryName (2.5.4.6) : PrintableString
-//	OrganizationName (2.5.4.10) : PrintableString or UTF8String
-//	OrganizationalUnit (2.5.4.11) : PrintableString or UTF8String
+//	OrganizationName (2.5.4.10) : PrintableString or UTF8String or BMPString or TeletexString
+//	OrganizationalUnit (2.5.4.11) : PrintableString or UTF8String or BMPString or TeletexString
 //	DnQualifier (2.5.4.46) : PrintableString
-//	StateOrProvinceName (2.5.4.8) : PrintableString or UTF8String
-//	CommonName (2.5.4.3) : PrintableString or UTF8String
+//	StateOrProvinceName (2.5.4.8) : PrintableString or UTF8String or BMPString or TeletexString
+//	CommonName (2.5.4.3) : PrintableString or UTF8String or BMPString or TeletexString
 //	SerialNumber (2.5.4.5) : PrintableString
-//	LocalityName (2.5.4.7) : PrintableString or UTF8String
-//	Title (2.5.4.12) : PrintableString or UTF8String
-//	Surname (2.5.4.4) : PrintableString or UTF8String
-//	GivenName (2.5.4.42) : PrintableString or UTF8String
-//	Initials (2.5.4.43) : PrintableString or UTF8String
-//	Pseudonym (2.5.4.65) : PrintableString or UTF8String
-//	GenerationQualifier (2.5.4.44) : PrintableString or UTF8String
+//	LocalityName (2.5.4.7) : PrintableString or UTF8String or BMPString or TeletexString
+//	Title (2.5.4.12) : PrintableString or UTF8String or BMPString or TeletexString
+//	Surname (2.5.4.4) : PrintableString or UTF8String or BMPString or TeletexString
+//	GivenName (2.5.4.42) : PrintableString or UTF8String or BMPString or TeletexString
+//	Initials (2.5.4.43) : PrintableString or UTF8String or BMPString or TeletexString
+//	Pseudonym (2.5.4.65) : PrintableString or UTF8String or BMPString or TeletexString
+//	GenerationQualifier (2.5.4.44) : PrintableString or UTF8String or BMPString or TeletexString
 //	ElectronicMailAddress (1.2.840.113549.1.9.1) : IA5String
 //	DomainComponent (0.9.2342.19200300.100.1.25) : IA5String
-//	Generic (Any OBJECT IDENTIFIER other than those already listed) : PrintableString or UTF8String or IA5String
+//	UnstructuredName (1.2.840.113549.1.9.2) : IA5String or UTF8String
+//	UnstructuredAddress (1.2.840.113549.1.9.8) : PrintableString or UTF8String or BMPString or TeletexString
+//	StreetAddress (2.5.4.9) : PrintableString or UTF8String or BMPString or TeletexString
+//	PostalCode (2.5.4.17) : PrintableString or UTF8String or BMPString or TeletexString
+//	UserID (0.9.2342.19200300.100.1.1) : IA5String or PrintableString or UTF8String or BMPString or TeletexString
+//	Generic (Any OBJECT IDENTIFIER other than those already listed) : PrintableString or UTF8String or IA5String or OIDValue or BMPString or TeletexString
 //
 // https://datatracker.ietf.org/doc/html/rfc5280#section-4.1.2.4
 type AttributeTypeAndValue struct {
@@ -118,11 +139,29 @@ const (
 	ElectronicMailAddress
 	DomainComponent
 	Generic
+	UnstructuredName
+	UnstructuredAddress
+	StreetAddress
+	PostalCode
+	UserID
 )
 
 var oidTable = make(map[AttributeType]asn1.ObjectIdentifier)
 var attributeTypeTable = make(map[string]AttributeType)
 var countryCodeTable = make(map[string]string)
+var descriptorTable = make(map[string]AttributeType)
+var nonEmptyValueTable = make(map[AttributeType]bool)
+
+// lengthBoundTable holds the bounds of the number of characters of AttributeValues of AttributeTypes.
+// AttributeTypes which are not keys have no bounds.
+var lengthBoundTable = make(map[AttributeType]LengthBound)
+
+// oidStringTable holds the dotted-decimal oid of each named AttributeType.
+// It is built once in init so that matching does not allocate by ObjectIdentifier.String().
+var oidStringTable = make(map[AttributeType]string)
+
+// attributeTypeNameTable maps the String() names of AttributeTypes, e.g. "CommonName", to the AttributeTypes.
+var attributeTypeNameTable = make(map[string]AttributeType)
 
 func init() {
 	oidTable[CountryName] = []int{2, 5, 4, 6}
@@ -141,6 +180,17 @@ func init() {
 	oidTable[GenerationQualifier] = []int{2, 5, 4, 44}
 	oidTable[ElectronicMailAddress] = []int{1, 2, 840, 113549, 1, 9, 1}
 	oidTable[DomainComponent] = []int{0, 9, 2342, 19200300, 100, 1, 25}
+	oidTable[UnstructuredName] = []int{1, 2, 840, 113549, 1, 9, 2}
+	oidTable[UnstructuredAddress] = []int{1, 2, 840, 113549, 1, 9, 8}
+	oidTable[StreetAddress] = []int{2, 5, 4, 9}
+	oidTable[PostalCode] = []int{2, 5, 4, 17}
+	oidTable[UserID] = []int{0, 9, 2342, 19200300, 100, 1, 1}
+
+	for at, oid := range oidTable {
+		oidStringTable[at] = oid.String()
+		attributeTypeNameTable[at.String()] = at
+	}
+	attributeTypeNameTable[Generic.String()] = Generic
 
 	attributeTypeTable[asn1.ObjectIdentifier{2, 5, 4, 6}.String()] = CountryName
 	attributeTypeTable[asn1.ObjectIdentifier{2, 5, 4, 10}.String()] = OrganizationName
@@ -158,6 +208,79 @@ func init() {
 	attributeTypeTable[asn1.ObjectIdentifier{2, 5, 4, 44}.String()] = GenerationQualifier
 	attributeTypeTable[asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 9, 1}.String()] = ElectronicMailAddress
 	attributeTypeTable[asn1.ObjectIdentifier{0, 9, 2342, 19200300, 100, 1, 25}.String()] = DomainComponent
+	attributeTypeTable[asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 9, 2}.String()] = UnstructuredName
+	attributeTypeTable[asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 9, 8}.String()] = UnstructuredAddress
+	attributeTypeTable[asn1.ObjectIdentifier{2, 5, 4, 9}.String()] = StreetAddress
+	attributeTypeTable[asn1.ObjectIdentifier{2, 5, 4, 17}.String()] = PostalCode
+	attributeTypeTable[asn1.ObjectIdentifier{0, 9, 2342, 19200300, 100, 1, 1}.String()] = UserID
+
+	//Short names and long names of descriptors are case insensitive, so keys are lowercase.
+	//https://www.iana.org/assignments/ldap-parameters/ldap-parameters.xhtml
+	descriptorTable["c"] = CountryName
+	descriptorTable["countryname"] = CountryName
+	descriptorTable["o"] = OrganizationName
+	descriptorTable["organizationname"] = OrganizationName
+	descriptorTable["ou"] = OrganizationalUnit
+	descriptorTable["organizationalunitname"] = OrganizationalUnit
+	descriptorTable["dnqualifier"] = DnQualifier
+	descriptorTable["st"] = StateOrProvinceName
+	descriptorTable["stateorprovincename"] = StateOrProvinceName
+	descriptorTable["cn"] = CommonName
+	descriptorTable["commonname"] = CommonName
+	descriptorTable["serialnumber"] = SerialNumber
+	descriptorTable["l"] = LocalityName
+	descriptorTable["localityname"] = LocalityName
+	descriptorTable["title"] = Title
+	descriptorTable["sn"] = Surname
+	descriptorTable["surname"] = Surname
+	descriptorTable["givenname"] = GivenName
+	descriptorTable["gn"] = GivenName
+	descriptorTable["initials"] = Initials
+	descriptorTable["pseudonym"] = Pseudonym
+	descriptorTable["generationqualifier"] = GenerationQualifier
+	descriptorTable["email"] = ElectronicMailAddress
+	descriptorTable["emailaddress"] = ElectronicMailAddress
+	//"E" is not registered, but it is emitted by Microsoft tools.
+	descriptorTable["e"] = ElectronicMailAddress
+	descriptorTable["dc"] = DomainComponent
+	descriptorTable["domaincomponent"] = DomainComponent
+	descriptorTable["unstructuredname"] = UnstructuredName
+	descriptorTable["unstructuredaddress"] = UnstructuredAddress
+	descriptorTable["street"] = StreetAddress
+	descriptorTable["streetaddress"] = StreetAddress
+	descriptorTable["postalcode"] = PostalCode
+	descriptorTable["uid"] = UserID
+	descriptorTable["userid"] = UserID
+
+	//DirectoryString of SIZE (1..ub) in https://datatracker.ietf.org/doc/html/rfc5280#appendix-A.1
+	nonEmptyValueTable[OrganizationName] = true
+	nonEmptyValueTable[OrganizationalUnit] = true
+	nonEmptyValueTable[StateOrProvinceName] = true
+	nonEmptyValueTable[CommonName] = true
+	nonEmptyValueTable[LocalityName] = true
+	nonEmptyValueTable[Title] = true
+	nonEmptyValueTable[Surname] = true
+	nonEmptyValueTable[GivenName] = true
+	nonEmptyValueTable[Initials] = true
+	nonEmptyValueTable[Pseudonym] = true
+	nonEmptyValueTable[GenerationQualifier] = true
+
+	//Upper bounds in https://datatracker.ietf.org/doc/html/rfc5280#appendix-A.1
+	//Lower bounds of DirectoryString are checked by nonEmptyValueTable, so only CountryName has a lower bound.
+	lengthBoundTable[CountryName] = LengthBound{2, 2}             //ub-country-name-alpha-length
+	lengthBoundTable[OrganizationName] = LengthBound{0, 64}       //ub-organization-name
+	lengthBoundTable[OrganizationalUnit] = LengthBound{0, 64}     //ub-organizational-unit-name
+	lengthBoundTable[StateOrProvinceName] = LengthBound{0, 128}   //ub-state-name
+	lengthBoundTable[CommonName] = LengthBound{0, 64}             //ub-common-name
+	lengthBoundTable[SerialNumber] = LengthBound{0, 64}           //ub-serial-number
+	lengthBoundTable[LocalityName] = LengthBound{0, 128}          //ub-locality-name
+	lengthBoundTable[Title] = LengthBound{0, 64}                  //ub-title
+	lengthBoundTable[Surname] = LengthBound{0, 32768}             //ub-name
+	lengthBoundTable[GivenName] = LengthBound{0, 32768}           //ub-name
+	lengthBoundTable[Initials] = LengthBound{0, 32768}            //ub-name
+	lengthBoundTable[Pseudonym] = LengthBound{0, 128}             //ub-pseudonym
+	lengthBoundTable[GenerationQualifier] = LengthBound{0, 32768} //ub-name
+	lengthBoundTable[ElectronicMailAddress] = LengthBound{0, 255} //ub-emailaddress-length
 
 	//ISO-3166-Alpha2-code
 	//https://www.iso.org/iso-3166-country-codes.html
@@ -446,15 +569,32 @@ func (a AttributeType) String() string {
 		return "ElectronicMailAddress"
 	case DomainComponent:
 		return "DomainComponent"
+	case UnstructuredName:
+		return "UnstructuredName"
+	case UnstructuredAddress:
+		return "UnstructuredAddress"
+	case StreetAddress:
+		return "StreetAddress"
+	case PostalCode:
+		return "PostalCode"
+	case UserID:
+		return "UserID"
 	case Generic:
 		return "Generic"
 	default:
+		if name, ok := registeredShortName(a); ok {
+			return name
+		}
 		return "UnKnown"
 	}
 }
 
 // String returns a string representation of this DN.
-// All string representations of RDN in the DN are concatenated with ",".
+// All string representations of RDN in the DN are concatenated with "," in DN order,
+// that is, the most significant RDN first, e.g. "C=JP,O=Example,CN=Mike".
+// String has no option of the direction. Use d.ReverseDnOrder().String() for the least significant RDN first.
+// Values are not escaped, so different DNs may have the same string representation.
+// Do not use it as a map key; use CanonicalString or DedupKey instead.
 func (d DN) String() string {
 	if d.CountRDN() == 0 {
 		return ""
@@ -475,17 +615,373 @@ func (d DN) ToRFC4514FormatString() string {
 		return ""
 	}
 
+	var sb strings.Builder
+	d.writeRFC4514FormatString(&sb, RFC4514Options{})
+	return sb.String()
+}
+
+// DescriptorCase represents how the short name (descriptor) of an AttributeType is cased in string output.
+type DescriptorCase int
+
+const (
+	// UpperCaseDescriptor outputs descriptors in upper case. (e.g. "CN", "DC")
+	UpperCaseDescriptor DescriptorCase = iota
+	// LowerCaseDescriptor outputs descriptors in lower case. (e.g. "cn", "dc")
+	LowerCaseDescriptor
+	// MixedCaseDescriptor outputs descriptors as registered. (e.g. "cn", "givenName")
+	MixedCaseDescriptor
+)
+
+// RFC4514Options represents options for RFC4514 Format string output.
+// The zero value produces the same output as ToRFC4514FormatString.
+type RFC4514Options struct {
+	//DescriptorCase specifies how the short names of AttributeTypes are cased.
+	DescriptorCase DescriptorCase
+	//SpaceAroundPlus outputs " + " instead of "+" between AttributeTypeAndValues of a multi-valued RDN.
+	SpaceAroundPlus bool
+	//SpaceAfterComma outputs ", " instead of "," between RDNs.
+	//The spaced output does not conform to RFC4514, and is intended only for consumers which expect it.
+	SpaceAfterComma bool
+	//MostSignificantFirst outputs RDNs in DN order, starting with the most significant RDN (e.g. "C=JP,O=Example,CN=Mike"),
+	//instead of the RFC4514 order starting with the least significant RDN (e.g. "CN=Mike,O=Example,C=JP"),
+	//to match tools which print DNs in that direction. The output does not conform to RFC4514.
+	//Only RFC4514 Format strings have this option. The direction of DN.String and DN.ToOpenSSLOnelineString is fixed
+	//by their formats, and either can be reversed by DN.ReverseDnOrder.
+	MostSignificantFirst bool
+	//HexValue outputs all values in the "#" hex form of their DER encodings (e.g. "CN=#0c044d696b65"),
+	//for LDAP servers which reject some escaped characters. See AttributeTypeAndValue.ToRFC4514FormatStringHexValue.
+	HexValue bool
+}
+
+// ToRFC4514FormatStringWithOptions returns an RFC4514 Format string of this DN formatted according to o.
+func (d DN) ToRFC4514FormatStringWithOptions(o RFC4514Options) string {
+	if d.CountRDN() == 0 {
+		return ""
+	}
+	var sb strings.Builder
+	d.writeRFC4514FormatString(&sb, o)
+	return sb.String()
+}
+
+// writeRFC4514FormatString writes an RFC4514 Format string of this DN formatted according to o to sb.
+// The whole DN is written to the single sb to avoid building intermediate strings of RDNs and AttributeTypeAndValues.
+func (d DN) writeRFC4514FormatString(sb *strings.Builder, o RFC4514Options) {
 	//the output consists of the string encodings of each RelativeDistinguishedName
 	//in the RDNSequence (according to Section 2.2),
 	//starting with the last element of the sequence and moving backwards toward the first.
-	out := d.ReverseDnOrder()
+	for n := 0; n < d.CountRDN(); n++ {
+		if n != 0 {
+			//The encodings of adjoining RelativeDistinguishedNames are separated by a comma (',' U+002C) character.
+			if o.SpaceAfterComma {
+				sb.WriteString(", ")
+			} else {
+				sb.WriteByte(',')
+			}
+		}
+		i := d.CountRDN() - 1 - n
+		if o.MostSignificantFirst {
+			i = n
+		}
+		d[i].writeRFC4514FormatString(sb, o)
+	}
+}
 
-	var rdns []string
-	for _, rdn := range out {
-		rdns = append(rdns, rdn.ToRFC4514FormatString())
+// ToLDAPString returns an LDAPv3 string representation of this DN.
+// The output is in RFC4514 order, starting with the most specific RDN (e.g. "cn=admin,ou=people,dc=example,dc=com"),
+// and short names are output in lower case because some LDAP servers reject upper case descriptors.
+// Use ToRFC4514FormatStringWithOptions with MixedCaseDescriptor to output descriptors as registered,
+// or with LowerCaseDescriptor and MostSignificantFirst to output the most significant RDN first.
+func (d DN) ToLDAPString() string {
+	return d.ToRFC4514FormatStringWithOptions(RFC4514Options{DescriptorCase: LowerCaseDescriptor})
+}
+
+// ToOpenSSLOnelineString returns the OpenSSL oneline string representation of this DN,
+// as printed by "openssl x509 -subject", e.g. "/C=JP/O=Example/CN=Mike".
+// Each RDN is preceded by "/" in DN order, that is, the most significant RDN first,
+// and AttributeTypeAndValues of a multi-valued RDN are separated by "+".
+// Short names are output in upper case. "/", "+" and "\" in values are escaped with "\",
+// so that the output can be parsed back by ParseOpenSSLOnelineDN.
+// The direction is fixed by the format. Use d.ReverseDnOrder().ToOpenSSLOnelineString() for the least significant RDN first.
+// If the DN has no RDN, returns blank string.
+func (d DN) ToOpenSSLOnelineString() string {
+	var sb strings.Builder
+	for _, rdn := range d {
+		sb.WriteByte('/')
+		for i, atv := range rdn {
+			if i != 0 {
+				sb.WriteByte('+')
+			}
+			sb.WriteString(atv.casedShortName(UpperCaseDescriptor))
+			sb.WriteByte('=')
+			writeOpenSSLOnelineEscapedValue(&sb, atv.Value.Value)
+		}
 	}
-	//The encodings of adjoining RelativeDistinguishedNames are separated by a comma (',' U+002C) character.
-	return strings.Join(rdns, ",")
+	return sb.String()
+}
+
+// writeOpenSSLOnelineEscapedValue writes s to sb, escaping "/", "+" and "\" with "\".
+func writeOpenSSLOnelineEscapedValue(sb *strings.Builder, s string) {
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '/', '+', '\\':
+			sb.WriteByte('\\')
+		}
+		sb.WriteByte(s[i])
+	}
+}
+
+// ToLDIFDN returns the "dn:" line of an LDIF file for this DN, without the line separator.
+// The DN is output by ToLDAPString. If it is not a SAFE-STRING of LDIF, e.g. it contains non-ASCII characters,
+// it is base64 encoded and "dn::" is used.
+//
+// https://www.rfc-editor.org/rfc/rfc2849
+func (d DN) ToLDIFDN() string {
+	s := d.ToLDAPString()
+	if isLDIFSafeString(s) {
+		return "dn: " + s
+	}
+	return "dn:: " + base64.StdEncoding.EncodeToString([]byte(s))
+}
+
+// isLDIFSafeString reports whether s is a SAFE-STRING of LDIF.
+func isLDIFSafeString(s string) bool {
+	//https://www.rfc-editor.org/rfc/rfc2849
+	//SAFE-CHAR = %x01-09 / %x0B-0C / %x0E-7F
+	//SAFE-INIT-CHAR = %x01-09 / %x0B-0C / %x0E-1F / %x21-39 / %x3B / %x3D-7F
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if c == 0x00 || c == '\n' || c == '\r' || c > 0x7f {
+			return false
+		}
+		if i == 0 && (c == ' ' || c == ':' || c == '<') {
+			return false
+		}
+	}
+	return true
+}
+
+// ToURLComponent returns the RFC4514 Format string of this DN percent-encoded for use in a URL path segment or query component,
+// e.g. "CN%3DMike%2CO%3DExample%20Inc%2CC%3DJP" for "CN=Mike,O=Example Inc,C=JP".
+// Every byte except the unreserved characters of RFC 3986 is percent-encoded, including "/", "+", "=" and ",",
+// so the result can be placed in a URL as it is and is decoded by url.PathUnescape or url.QueryUnescape.
+//
+// https://www.rfc-editor.org/rfc/rfc3986#section-2.3
+func (d DN) ToURLComponent() string {
+	return percentEncode(d.ToRFC4514FormatString())
+}
+
+// percentEncode returns s with every byte except the unreserved characters of RFC 3986 percent-encoded.
+func percentEncode(s string) string {
+	//unreserved = ALPHA / DIGIT / "-" / "." / "_" / "~"
+	const upperhex = "0123456789ABCDEF"
+	var sb strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if isAlpha(c) || ('0' <= c && c <= '9') || c == '-' || c == '.' || c == '_' || c == '~' {
+			sb.WriteByte(c)
+			continue
+		}
+		sb.WriteByte('%')
+		sb.WriteByte(upperhex[c>>4])
+		sb.WriteByte(upperhex[c&0x0f])
+	}
+	return sb.String()
+}
+
+// ToEnvPairs returns the AttributeTypeAndValues of this DN as "NAME=value" environment variable entries,
+// e.g. "CERT_CN=Mike" for prefix "CERT", to pass the subject to a subprocess.
+// Entries are returned in DN order. NAME is the prefix and the upper case short name joined by "_",
+// and a dotted-decimal oid is output as "OID_" followed by its arcs joined by "_", e.g. "CERT_OID_1_2_3".
+// If prefix is blank, NAME is the upper case short name only.
+// Types which appear more than once in the DN, including in multi-valued RDNs, get numeric suffixes
+// in DN order starting with 1, e.g. "CERT_OU_1" and "CERT_OU_2".
+// Values are quoted for a POSIX shell by single quotes unless they consist only of characters safe in a shell word,
+// so each entry can be used in a shell "export" statement or an env file read by a shell.
+func (d DN) ToEnvPairs(prefix string) []string {
+	var names []string
+	counts := map[string]int{}
+	for _, rdn := range d {
+		for _, atv := range rdn {
+			name := atv.envName()
+			if prefix != "" {
+				name = prefix + "_" + name
+			}
+			names = append(names, name)
+			counts[name]++
+		}
+	}
+	pairs := []string{}
+	seen := map[string]int{}
+	i := 0
+	for _, rdn := range d {
+		for _, atv := range rdn {
+			name := names[i]
+			i++
+			if counts[name] > 1 {
+				seen[name]++
+				name += "_" + strconv.Itoa(seen[name])
+			}
+			pairs = append(pairs, name+"="+shellQuote(atv.Value.Value))
+		}
+	}
+	return pairs
+}
+
+// envName returns the upper case short name of atv for an environment variable name. See DN.ToEnvPairs.
+func (atv AttributeTypeAndValue) envName() string {
+	name := strings.ToUpper(atv.toShortName())
+	if name != "" && name[0] >= '0' && name[0] <= '9' {
+		return "OID_" + strings.ReplaceAll(name, ".", "_")
+	}
+	return name
+}
+
+// shellQuote returns s quoted for a POSIX shell by single quotes.
+// If s is not blank and consists only of characters safe in a shell word, returns s as it is.
+func shellQuote(s string) string {
+	safe := s != ""
+	for i := 0; i < len(s) && safe; i++ {
+		c := s[i]
+		safe = isAlpha(c) || ('0' <= c && c <= '9') || strings.IndexByte("@%+=:,./_-", c) >= 0
+	}
+	if safe {
+		return s
+	}
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// TLVDump returns a nested tag-length-value dump of the ASN.1 DER form of this DN for debugging,
+// similar to "openssl asn1parse". Each line is an element indented by its depth, e.g.
+//
+//	SEQUENCE (len=14)
+//	  SET (len=12)
+//	    SEQUENCE (len=10)
+//	      OBJECT IDENTIFIER (len=3) 2.5.4.3 (CommonName)
+//	      UTF8String (len=3) "abc"
+//
+// If the DN can not be marshaled by MarshalDN, returns blank string.
+func (d DN) TLVDump() string {
+	b, err := MarshalDN(d)
+	if err != nil {
+		return ""
+	}
+	var sb strings.Builder
+	if err := dumpTLV(&sb, b, 0); err != nil {
+		return ""
+	}
+	return sb.String()
+}
+
+// dumpTLV writes the dump of ASN.1 elements in b at depth to sb. See DN.TLVDump.
+func dumpTLV(sb *strings.Builder, b []byte, depth int) error {
+	for len(b) > 0 {
+		var r asn1.RawValue
+		rest, err := asn1.Unmarshal(b, &r)
+		if err != nil {
+			return err
+		}
+		sb.WriteString(strings.Repeat("  ", depth))
+		sb.WriteString(fmt.Sprintf("%s (len=%d)", tagName(r), len(r.Bytes)))
+		if r.IsCompound {
+			sb.WriteString("\n")
+			if err := dumpTLV(sb, r.Bytes, depth+1); err != nil {
+				return err
+			}
+		} else {
+			sb.WriteString(" " + primitiveValueString(r) + "\n")
+		}
+		b = rest
+	}
+	return nil
+}
+
+// tagName returns a name of the tag of r for TLVDump.
+func tagName(r asn1.RawValue) string {
+	if r.Class != asn1.ClassUniversal {
+		return fmt.Sprintf("[class %d, tag %d]", r.Class, r.Tag)
+	}
+	switch r.Tag {
+	case asn1.TagSequence:
+		return "SEQUENCE"
+	case asn1.TagSet:
+		return "SET"
+	case asn1.TagOID:
+		return "OBJECT IDENTIFIER"
+	case asn1.TagPrintableString:
+		return PrintableString.String()
+	case asn1.TagUTF8String:
+		return UTF8String.String()
+	case asn1.TagIA5String:
+		return IA5String.String()
+	case asn1.TagT61String:
+		return "TeletexString"
+	case asn1.TagBMPString:
+		return "BMPString"
+	default:
+		return fmt.Sprintf("[universal %d]", r.Tag)
+	}
+}
+
+// primitiveValueString returns a string representation of the contents of primitive r for TLVDump.
+// Object identifiers are shown with their AttributeType names if known,
+// strings are quoted, and the others are hex encoded.
+func primitiveValueString(r asn1.RawValue) string {
+	if r.Class == asn1.ClassUniversal {
+		switch r.Tag {
+		case asn1.TagOID:
+			var o asn1.ObjectIdentifier
+			if _, err := asn1.Unmarshal(r.FullBytes, &o); err != nil {
+				break
+			}
+			if at, err := ReferAttributeTypeName(o); err == nil {
+				return o.String() + " (" + at.String() + ")"
+			}
+			return o.String()
+		case asn1.TagPrintableString, asn1.TagUTF8String, asn1.TagIA5String:
+			return strconv.Quote(string(r.Bytes))
+		}
+	}
+	return hex.EncodeToString(r.Bytes)
+}
+
+// NewDN returns a new DN whose RDNs are rdns in DN order.
+// RDNs are not validated. See NewDNChecked.
+func NewDN(rdns ...RDN) DN {
+	d := DN{}
+	for _, rdn := range rdns {
+		d = d.AppendRDN(rdn)
+	}
+	return d
+}
+
+// NewDNChecked is like NewDN but returns error if any of rdns is rejected by AppendRDNChecked.
+func NewDNChecked(rdns ...RDN) (DN, error) {
+	d := DN{}
+	for index, rdn := range rdns {
+		var err error
+		if d, err = d.AppendRDNChecked(rdn); err != nil {
+			return DN{}, fmt.Errorf("%d th RDN element error: %w", index, err)
+		}
+	}
+	return d, nil
+}
+
+// AppendRDN returns a new DN with rdn appended to this DN. This DN is not modified.
+// rdn is not validated. See AppendRDNChecked.
+func (d DN) AppendRDN(rdn RDN) DN {
+	n := make(DN, 0, len(d)+1)
+	n = append(n, d...)
+	return append(n, append(RDN{}, rdn...))
+}
+
+// AppendRDNChecked is like AppendRDN but returns error if rdn has no AttributeTypeAndValue,
+// or has an invalid AttributeTypeAndValue, e.g. an invalid combination of AttributeType and Encoding,
+// which MarshalDN would reject.
+func (d DN) AppendRDNChecked(rdn RDN) (DN, error) {
+	if _, err := isValidRDN(rdn, true); err != nil {
+		return DN{}, fmt.Errorf("RDN appending error: %w", err)
+	}
+	return d.AppendRDN(rdn), nil
 }
 
 // ReverseDnOrder returns a new reverse order DN.
@@ -498,6 +994,86 @@ func (d DN) ReverseDnOrder() DN {
 	return revDn
 }
 
+// MapRDNs returns a new DN whose RDNs are the results of applying fn to each RDN of this DN in DN order.
+// fn receives a copy of each RDN, so modifying it does not change this DN.
+func (d DN) MapRDNs(fn func(RDN) RDN) DN {
+	m := DN{}
+	for _, rdn := range d {
+		m = append(m, fn(append(RDN{}, rdn...)))
+	}
+	return m
+}
+
+// IsFlat reports whether every RDN of this DN has exactly one AttributeTypeAndValue.
+// An empty DN is flat.
+func (d DN) IsFlat() bool {
+	for _, rdn := range d {
+		if rdn.CountAttributeTypeAndValue() != 1 {
+			return false
+		}
+	}
+	return true
+}
+
+// Flatten returns a new DN in which each multi-valued RDN of this DN is expanded into single-valued RDNs.
+// The expanded RDNs are placed at the position of the original RDN in the order of its AttributeTypeAndValues.
+// Note that the result is a different distinguished name from this DN.
+func (d DN) Flatten() DN {
+	f := DN{}
+	for _, rdn := range d {
+		for _, atv := range rdn {
+			f = append(f, RDN{atv})
+		}
+	}
+	return f
+}
+
+// SortedForDisplay returns a copy of this DN whose RDNs are reordered for a consistent display.
+//
+// WARNING: the order of RDNs is significant in a DN, so the result is a different distinguished name from this DN.
+// It must be used only for display, and must not be marshaled, compared or used as a DN of a certificate.
+//
+// RDNs are ordered by the following priority of AttributeTypes, and RDNs of the same priority keep their order:
+//
+//	CountryName, StateOrProvinceName, LocalityName, OrganizationName, OrganizationalUnit, CommonName, the others
+//
+// The priority of a multi-valued RDN is the highest priority of its AttributeTypes.
+func (d DN) SortedForDisplay() DN {
+	sorted := append(DN{}, d...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return displayRank(sorted[i]) < displayRank(sorted[j])
+	})
+	return sorted
+}
+
+// displayRank returns the priority of r for SortedForDisplay. Smaller is higher.
+func displayRank(r RDN) int {
+	rank := 6
+	for _, atv := range r {
+		var ar int
+		switch atv.resolvedType() {
+		case CountryName:
+			ar = 0
+		case StateOrProvinceName:
+			ar = 1
+		case LocalityName:
+			ar = 2
+		case OrganizationName:
+			ar = 3
+		case OrganizationalUnit:
+			ar = 4
+		case CommonName:
+			ar = 5
+		default:
+			ar = 6
+		}
+		if ar < rank {
+			rank = ar
+		}
+	}
+	return rank
+}
+
 // String returns a string representation of this RDN.
 // All string representations of AttributeTypeAndValues in the RDN are concatenated with "+".
 func (r RDN) String() string {
@@ -511,19 +1087,34 @@ func (r RDN) String() string {
 // ToRFC4514FormatString returns an RFC4514 Format string of this RDN.
 func (r RDN) ToRFC4514FormatString() string {
 	//https://www.rfc-editor.org/rfc/rfc4514#section-2.2
-	var atvs []string
-	for _, atv := range r {
+	var sb strings.Builder
+	r.writeRFC4514FormatString(&sb, RFC4514Options{})
+	return sb.String()
+}
+
+// writeRFC4514FormatString writes an RFC4514 Format string of this RDN formatted according to o to sb.
+func (r RDN) writeRFC4514FormatString(sb *strings.Builder, o RFC4514Options) {
+	for i, atv := range r {
+		if i != 0 {
+			//Where there is a multi-valued RDN, the outputs from adjoining AttributeTypeAndValues are separated
+			//by a plus sign ('+' U+002B) character.
+			if o.SpaceAroundPlus {
+				sb.WriteString(" + ")
+			} else {
+				sb.WriteByte('+')
+			}
+		}
 		//the output consists of the string encodings of
 		//each AttributeTypeAndValue (according to Section 2.3), in any order.
-		atvs = append(atvs, atv.ToRFC4514FormatString())
+		atv.writeRFC4514FormatString(sb, o)
 	}
-	//Where there is a multi-valued RDN, the outputs from adjoining AttributeTypeAndValues are separated
-	//by a plus sign ('+' U+002B) character.
-	return strings.Join(atvs, "+")
 }
 
 // String returns a string representation of this AttributeTypeAndValue.
 // The attribute type is uppercase, and the attribute type and value are concatenated by "=".
+// The attribute type of Generic is its short name if Oid is a known AttributeType oid,
+// otherwise its dotted-decimal Oid, e.g. "1.2.3.4=foo", rather than "GENERIC".
+// If Oid is not a valid oid, the attribute type is "UNKNOWN".
 func (atv AttributeTypeAndValue) String() string {
 	return strings.ToUpper(atv.toShortName()) + "=" + atv.Value.String()
 }
@@ -589,7 +1180,7 @@ func toDefinedShortName(a AttributeType) string {
 	case SerialNumber:
 		return "serialNumber"
 	case LocalityName:
-		return "L"
+		return "l"
 	case Title:
 		return "title"
 	case Surname:
@@ -606,9 +1197,22 @@ func toDefinedShortName(a AttributeType) string {
 		return "email"
 	case DomainComponent:
 		return "DC"
+	case UnstructuredName:
+		return "unstructuredName"
+	case UnstructuredAddress:
+		return "unstructuredAddress"
+	case StreetAddress:
+		return "street"
+	case PostalCode:
+		return "postalCode"
+	case UserID:
+		return "uid"
 	case Generic:
 		return "Generic"
 	default:
+		if name, ok := registeredShortName(a); ok {
+			return name
+		}
 		return "UnKnown"
 	}
 }
@@ -617,7 +1221,61 @@ func toDefinedShortName(a AttributeType) string {
 // The attribute type is uppercase
 func (atv AttributeTypeAndValue) ToRFC4514FormatString() string {
 	//https://www.rfc-editor.org/rfc/rfc4514#section-2.3
-	return strings.ToUpper(atv.toShortName()) + "=" + atv.Value.ToRFC4514FormatString()
+	var sb strings.Builder
+	atv.writeRFC4514FormatString(&sb, RFC4514Options{})
+	return sb.String()
+}
+
+// writeRFC4514FormatString writes an RFC4514 Format string of this AttributeTypeAndValue formatted according to o to sb.
+func (atv AttributeTypeAndValue) writeRFC4514FormatString(sb *strings.Builder, o RFC4514Options) {
+	sb.WriteString(atv.casedShortName(o.DescriptorCase))
+	sb.WriteByte('=')
+	if o.HexValue {
+		if h, err := atv.Value.derHex(); err == nil {
+			sb.WriteByte('#')
+			sb.WriteString(h)
+			return
+		}
+	}
+	writeEscapedAttributeValue(sb, atv.Value.Value)
+}
+
+// ToRFC4514FormatStringHexValue returns an RFC4514 Format string of this AttributeTypeAndValue
+// whose value is output in the "#" hex form of its DER encoding, e.g. "CN=#0c044d696b65" for CommonName "Mike" in UTF8String.
+// The form is needed for values of non-string syntaxes, and it is accepted by ParseRFC4514DN.
+// If the value can not be encoded in its Encoding, the value is output as ToRFC4514FormatString does.
+// The attribute type is uppercase.
+//
+// https://www.rfc-editor.org/rfc/rfc4514#section-2.4
+func (atv AttributeTypeAndValue) ToRFC4514FormatStringHexValue() string {
+	var sb strings.Builder
+	atv.writeRFC4514FormatString(&sb, RFC4514Options{HexValue: true})
+	return sb.String()
+}
+
+// derHex returns the hex string of the DER encoding of av.
+func (av AttributeValue) derHex() (string, error) {
+	r, err := newRawValue(av.Encoding, av.Value)
+	if err != nil {
+		return "", err
+	}
+	b, err := asn1.Marshal(r)
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// casedShortName returns the short name of atv cased according to c.
+func (atv AttributeTypeAndValue) casedShortName(c DescriptorCase) string {
+	switch c {
+	case LowerCaseDescriptor:
+		return strings.ToLower(atv.toShortName())
+	case MixedCaseDescriptor:
+		return atv.toShortName()
+	default:
+		return strings.ToUpper(atv.toShortName())
+	}
 }
 
 // String returns a string representation of this AttributeValue.
@@ -625,6 +1283,25 @@ func (av AttributeValue) String() string {
 	return av.Value
 }
 
+// TitleCase returns a copy of this AttributeValue whose Value is title-cased for display, e.g. "JOHN o'neil-SMITH" to "John O'neil-Smith".
+// The first letter of each word is converted to title case and the other letters to lower case by Unicode rules.
+// Words are separated by white spaces and "-". Letters without case, e.g. CJK characters, are kept as they are.
+// Encoding is kept, so the result may not be valid for the Encoding, e.g. a title case letter in PrintableString.
+// It is intended for display, not for matching. Use Normalize or Equal for matching.
+func (av AttributeValue) TitleCase() AttributeValue {
+	var sb strings.Builder
+	wordStart := true
+	for _, r := range av.Value {
+		if wordStart {
+			sb.WriteRune(unicode.ToTitle(r))
+		} else {
+			sb.WriteRune(unicode.ToLower(r))
+		}
+		wordStart = unicode.IsSpace(r) || r == '-'
+	}
+	return AttributeValue{Encoding: av.Encoding, Value: sb.String()}
+}
+
 // ToRFC4514FormatString returns an RFC4514 Format string of this AttributeValue.
 func (av AttributeValue) ToRFC4514FormatString() string {
 	//https://www.rfc-editor.org/rfc/rfc4514#section-2.4
@@ -642,15 +1319,26 @@ func needEscaping(r rune) bool {
 	return false
 }
 
+// escapeAttributeValue escapes s according to RFC 4514.
+// Only the first and the last spaces are escaped, so a value of only spaces keeps its interior spaces unescaped,
+// e.g. "   " (3 spaces) is escaped to "\  \ ", and " " (1 space) is escaped to "\ ".
 func escapeAttributeValue(s string) string {
+	var sb strings.Builder
+	writeEscapedAttributeValue(&sb, s)
+	return sb.String()
+}
+
+// writeEscapedAttributeValue writes s escaped according to RFC 4514 to sb. See escapeAttributeValue.
+func writeEscapedAttributeValue(sb *strings.Builder, s string) {
 	cnt := 0
 	lastIndex := utf8.RuneCountInString(s) - 1
-	var out string
+	sb.Grow(len(s))
 	for _, r := range s {
 		if cnt == 0 && r == ' ' || r == '#' {
 			//https://www.rfc-editor.org/rfc/rfc4514#section-2.4
 			//- a space (' ' U+0020) or number sign ('#' U+0023) occurring at the beginning of the string;
-			out = out + escape(string(r))
+			sb.WriteByte('\\')
+			sb.WriteRune(r)
 			cnt++
 			continue
 		}
@@ -658,39 +1346,54 @@ func escapeAttributeValue(s string) string {
 		if cnt == lastIndex && r == ' ' {
 			//https://www.rfc-editor.org/rfc/rfc4514#section-2.4
 			//- a space (' ' U+0020) character occurring at the end of the string;
-			out = out + escape(string(r))
+			sb.WriteByte('\\')
+			sb.WriteRune(r)
 			cnt++
 			continue
 		}
 
 		if needEscaping(r) {
 			//https://www.rfc-editor.org/rfc/rfc4514#section-2.4
-			out = out + escape(string(r))
+			sb.WriteByte('\\')
+			sb.WriteRune(r)
 			cnt++
 			continue
 		}
 
-		out = out + string(r)
+		sb.WriteRune(r)
 		cnt++
 	}
-	return out
-}
-
-func escape(c string) string {
-	return "\\" + c
 }
 
+// Encoding represents an ASN.1 type of AttributeValue.
+// OIDValue represents an OBJECT IDENTIFIER value, and the Value of the AttributeValue is its dotted-decimal form.
+// BMPString is encoded in UCS-2 (big-endian), and the Value of the AttributeValue is its UTF-8 form.
+// It can only hold characters in the Basic Multilingual Plane (U+0000 to U+FFFF).
+// TeletexString (T61String) is decoded and encoded on a best-effort basis as ISO 8859-1 (Latin-1),
+// and the Value of the AttributeValue is its UTF-8 form.
 type Encoding int
 
 const (
 	PrintableString Encoding = iota + 1
 	UTF8String
 	IA5String
+	OIDValue
+	BMPString
+	TeletexString
 )
 
 func convertToAttributeValue(r asn1.RawValue) (av AttributeValue, err error) {
 	var p string
 	var st string
+	if r.Tag == asn1.TagOID {
+		return convertToOIDAttributeValue(r)
+	}
+	if r.Tag == asn1.TagBMPString {
+		return convertToDecodedAttributeValue(r, BMPString, decodeBMPString)
+	}
+	if r.Tag == asn1.TagT61String {
+		return convertToDecodedAttributeValue(r, TeletexString, decodeLatin1)
+	}
 	switch r.Tag {
 	case asn1.TagPrintableString:
 		av.Encoding = PrintableString
@@ -717,6 +1420,72 @@ func convertToAttributeValue(r asn1.RawValue) (av AttributeValue, err error) {
 	return av, nil
 }
 
+func convertToOIDAttributeValue(r asn1.RawValue) (av AttributeValue, err error) {
+	var o asn1.ObjectIdentifier
+	rest, err := asn1.Unmarshal(r.FullBytes, &o)
+	if err != nil {
+		err := fmt.Errorf("AttributeValue parsing error: %w", err)
+		return AttributeValue{}, err
+	} else if len(rest) != 0 {
+		err := fmt.Errorf("AttributeValue parsing error: trailing data after AttributeValue")
+		return AttributeValue{}, err
+	}
+	return AttributeValue{Encoding: OIDValue, Value: o.String()}, nil
+}
+
+// convertToDecodedAttributeValue converts r to AttributeValue of e whose Value is the contents octets of r decoded by decode.
+func convertToDecodedAttributeValue(r asn1.RawValue, e Encoding, decode func(b []byte) (string, error)) (av AttributeValue, err error) {
+	var b asn1.RawValue
+	rest, err := asn1.Unmarshal(r.FullBytes, &b)
+	if err != nil {
+		err := fmt.Errorf("AttributeValue parsing error: %w", err)
+		return AttributeValue{}, err
+	} else if len(rest) != 0 {
+		err := fmt.Errorf("AttributeValue parsing error: trailing data after AttributeValue")
+		return AttributeValue{}, err
+	}
+	st, err := decode(b.Bytes)
+	if err != nil {
+		err := fmt.Errorf("AttributeValue parsing error: %w", err)
+		return AttributeValue{}, err
+	}
+	return AttributeValue{Encoding: e, Value: st}, nil
+}
+
+// byteOrderMark is the Unicode byte order mark (U+FEFF).
+// A leading byte order mark of BMPString is stripped when decoding,
+// so that values with and without it are matched as the same string.
+// As a result, MarshalDN does not reproduce the byte order mark. See RoundTripSafe.
+const byteOrderMark = '\uFEFF'
+
+// decodeBMPString decodes the contents octets of ASN.1 BMPString (big-endian UCS-2) to string.
+// A leading byte order mark is stripped.
+// A surrogate pair, which is not UCS-2 but is found in values encoded as UTF-16, is decoded leniently,
+// but an unpaired surrogate is an error.
+func decodeBMPString(b []byte) (string, error) {
+	if len(b)%2 != 0 {
+		return "", errors.New("BMPString parsing error: odd length")
+	}
+	s := make([]uint16, 0, len(b)/2)
+	for i := 0; i < len(b); i += 2 {
+		s = append(s, uint16(b[i])<<8|uint16(b[i+1]))
+	}
+	if len(s) > 0 && s[0] == byteOrderMark {
+		s = s[1:]
+	}
+	for i := 0; i < len(s); i++ {
+		if !utf16.IsSurrogate(rune(s[i])) {
+			continue
+		}
+		if i+1 < len(s) && utf16.DecodeRune(rune(s[i]), rune(s[i+1])) != utf8.RuneError {
+			i++
+			continue
+		}
+		return "", fmt.Errorf("BMPString parsing error: unpaired surrogate %#04x", s[i])
+	}
+	return string(utf16.Decode(s)), nil
+}
+
 func convertToAttributeTypeAndValue(iatv innerAttributeTypeAndValue) (AttributeTypeAndValue, error) {
 	av, err := convertToAttributeValue(iatv.Value)
 	if err != nil {
@@ -746,12 +1515,16 @@ func convertToRdn(irdn innerRDNSET) (RDN, error) {
 	return atvs, nil
 }
 
+// convertToDn converts idn to DN. If idn has no RDN, returns non-nil empty DN.
 func convertToDn(idn innerDN) (DN, error) {
-	var rdns []RDN
-	if len(rdns) == 0 {
-		rdns = DN{}
-	}
+	rdns := make(DN, 0, len(idn))
 	for index, irdn := range idn {
+		if len(irdn) == 0 {
+			//https://www.itu.int/rec/T-REC-X.501
+			//RelativeDistinguishedName ::= SET SIZE (1..MAX) OF AttributeTypeAndValue
+			err := fmt.Errorf("RDN at index %d contains no AttributeTypeAndValue", index)
+			return DN{}, err
+		}
 		rdn, err := convertToRdn(irdn)
 		if err != nil {
 			err := fmt.Errorf("%d th RDN element parsing error: %w", index, err)
@@ -764,57 +1537,428 @@ func convertToDn(idn innerDN) (DN, error) {
 
 // ParseDERDN parses a distinguished name, ASN.1 DER form and returns DN.
 // RelativeDistinguishedName of the distinguished name should have at least one AttributeTypeAndValue.
-// AttributeValue currently supports the following ASN.1 string encodings:
+// AttributeValue currently supports the following ASN.1 string encodings and OBJECT IDENTIFIER:
 //
 //	PrintableString
 //	UTF8String
 //	IA5String
+//	BMPString
+//	TeletexString (T61String, as ISO 8859-1)
+//	OIDValue (OBJECT IDENTIFIER)
 //
 // Currently, the following combinations of OBJECT IDENTIFIER for AttributeType
 // and Encoding for AttributeValue are supported:
 //
 //	2.5.4.6 (CountryName) : PrintableString
-//	2.5.4.10 (OrganizationName) : PrintableString or UTF8String
-//	2.5.4.11 (OrganizationalUnit) : PrintableString or UTF8String
+//	2.5.4.10 (OrganizationName) : PrintableString or UTF8String or BMPString or TeletexString
+//	2.5.4.11 (OrganizationalUnit) : PrintableString or UTF8String or BMPString or TeletexString
 //	2.5.4.46 (DnQualifier) : PrintableString
-//	2.5.4.8 (StateOrProvinceName) : PrintableString or UTF8String
-//	2.5.4.3 (CommonName) : PrintableString or UTF8String
+//	2.5.4.8 (StateOrProvinceName) : PrintableString or UTF8String or BMPString or TeletexString
+//	2.5.4.3 (CommonName) : PrintableString or UTF8String or BMPString or TeletexString
 //	2.5.4.5 (SerialNumber) : PrintableString
-//	2.5.4.7 (LocalityName) : PrintableString or UTF8String
-//	2.5.4.12 (Title) : PrintableString or UTF8String
-//	2.5.4.4 (Surname) : PrintableString or UTF8String
-//	2.5.4.42 (GivenName) : PrintableString or UTF8String
-//	2.5.4.43 (Initials) : PrintableString or UTF8String
-//	2.5.4.65 (Pseudonym) : PrintableString or UTF8String
-//	2.5.4.44 (GenerationQualifier) : PrintableString or UTF8String
+//	2.5.4.7 (LocalityName) : PrintableString or UTF8String or BMPString or TeletexString
+//	2.5.4.12 (Title) : PrintableString or UTF8String or BMPString or TeletexString
+//	2.5.4.4 (Surname) : PrintableString or UTF8String or BMPString or TeletexString
+//	2.5.4.42 (GivenName) : PrintableString or UTF8String or BMPString or TeletexString
+//	2.5.4.43 (Initials) : PrintableString or UTF8String or BMPString or TeletexString
+//	2.5.4.65 (Pseudonym) : PrintableString or UTF8String or BMPString or TeletexString
+//	2.5.4.44 (GenerationQualifier) : PrintableString or UTF8String or BMPString or TeletexString
 //	1.2.840.113549.1.9.1 (ElectronicMailAddress) : IA5String
 //	0.9.2342.19200300.100.1.25 (DomainComponent) : IA5String
-//	Any OBJECT IDENTIFIER other than those already listed (Generic) : PrintableString or UTF8String or IA5String
+//	1.2.840.113549.1.9.2 (UnstructuredName) : IA5String or UTF8String
+//	1.2.840.113549.1.9.8 (UnstructuredAddress) : PrintableString or UTF8String or BMPString or TeletexString
+//	2.5.4.9 (StreetAddress) : PrintableString or UTF8String or BMPString or TeletexString
+//	2.5.4.17 (PostalCode) : PrintableString or UTF8String or BMPString or TeletexString
+//	0.9.2342.19200300.100.1.1 (UserID) : IA5String or PrintableString or UTF8String or BMPString or TeletexString
+//	Any OBJECT IDENTIFIER other than those already listed (Generic) : PrintableString or UTF8String or IA5String or OIDValue or BMPString or TeletexString
+//
+// The number of characters of AttributeValue is not checked against the upper bounds (ub-*) of RFC 5280,
+// e.g. 64 for CommonName, because existing certificates often exceed them, e.g. with long legal entity names.
 //
 // https://datatracker.ietf.org/doc/html/rfc5280#section-4.1.2.4
 // https://datatracker.ietf.org/doc/html/rfc5280#appendix-A.1
 func ParseDERDN(dnBytes []byte) (dn DN, err error) {
+	return ParseDERDNWithMode(dnBytes, Strict)
+}
+
+// MustParseDERDN is like ParseDERDN but panics if dnBytes can not be parsed.
+// It is intended for use in tests and in initialization of global variables,
+// where a parse failure is a programming error.
+func MustParseDERDN(dnBytes []byte) DN {
+	dn, err := ParseDERDN(dnBytes)
+	if err != nil {
+		panic("dnutil: MustParseDERDN: " + err.Error())
+	}
+	return dn
+}
+
+// ParseMode represents the behavior of ParseDERDNWithMode.
+type ParseMode int
+
+const (
+	// Strict parses a distinguished name in the same way as ParseDERDN.
+	Strict ParseMode = 0
+	// Compatible tolerates the following non-conformant encodings found in the wild:
+	//
+	//	AttributeValue wrapped in an extra SET with a single element
+	//	AttributeValue in an Encoding not allowed for its AttributeType, e.g. CountryName in UTF8String
+	//
+	// DN.Lint reports the latter as mismatched_encoding. MarshalDN returns an error for such a DN,
+	// so it can not be marshaled as it is.
+	//
+	// Compatible also reports AttributeTypeAndValue missing its AttributeValue with its position,
+	// instead of a generic unmarshal error.
+	// Members of a multi-valued RDN which are not in DER SET OF order, as found in BER encodings,
+	// are not rejected and are returned in the order they appear in dnBytes.
+	Compatible ParseMode = 1 << 0
+	// RejectUnknownOIDs makes parsing fail with the offending oid if the distinguished name contains
+	// an AttributeType oid which would otherwise be parsed as Generic.
+	// It can be combined with Compatible, e.g. Compatible | RejectUnknownOIDs.
+	RejectUnknownOIDs ParseMode = 1 << 1
+)
+
+// ParseDERDNWithMode parses a distinguished name, ASN.1 DER form according to mode and returns DN.
+// See ParseDERDN for the supported AttributeTypes and Encodings.
+// In every mode, AttributeTypeAndValues of a multi-valued RDN are returned in the order they appear in dnBytes,
+// which is the DER SET OF order for DER input. They are never reordered.
+func ParseDERDNWithMode(dnBytes []byte, mode ParseMode) (dn DN, err error) {
+	return parseDERDN(dnBytes, parseConfig{mode: mode})
+}
+
+// ParseOption represents an option of ParseDERDNOpts.
+type ParseOption func(*parseConfig)
+
+type parseConfig struct {
+	mode           ParseMode
+	teletexDecoder func(b []byte) (string, error)
+}
+
+// WithParseMode makes ParseDERDNOpts parse according to mode, as ParseDERDNWithMode does.
+func WithParseMode(mode ParseMode) ParseOption {
+	return func(c *parseConfig) {
+		c.mode = mode
+	}
+}
+
+// WithTeletexDecoder makes ParseDERDNOpts decode the contents octets of TeletexString (T61String) AttributeValues
+// with decode instead of the default Latin-1 decoding, e.g. to interpret legacy values as Windows-1252.
+// decode must return a valid UTF-8 string.
+// Decoded values are returned as UTF8String AttributeValues, because they can not be re-encoded as TeletexString
+// in the original charset. So MarshalDN re-encodes them as UTF8String.
+func WithTeletexDecoder(decode func(b []byte) (string, error)) ParseOption {
+	return func(c *parseConfig) {
+		c.teletexDecoder = decode
+	}
+}
+
+// ParseDERDNOpts parses a distinguished name, ASN.1 DER form according to opts and returns DN.
+// Without opts, ParseDERDNOpts is the same as ParseDERDN.
+//
+// By default, the contents octets of TeletexString (T61String) AttributeValues are decoded on a best-effort basis
+// as ISO 8859-1 (Latin-1), that is, each octet is the code point of the same value,
+// because TeletexString values in the wild rarely follow T.61 and are mostly Latin-1.
+// They are returned as TeletexString AttributeValues, so MarshalDN re-encodes them with the same tag and octets.
+// Values which are actually in another charset, e.g. Windows-1252, need a decoder given by WithTeletexDecoder.
+func ParseDERDNOpts(dnBytes []byte, opts ...ParseOption) (dn DN, err error) {
+	c := parseConfig{}
+	for _, opt := range opts {
+		opt(&c)
+	}
+	return parseDERDN(dnBytes, c)
+}
+
+// decodeLatin1 decodes b as ISO 8859-1 (Latin-1), in which each octet is the code point of the same value.
+func decodeLatin1(b []byte) (string, error) {
+	var sb strings.Builder
+	for _, c := range b {
+		sb.WriteRune(rune(c))
+	}
+	return sb.String(), nil
+}
+
+// parseDERDN parses a distinguished name, ASN.1 DER form according to c and returns DN.
+// If c has teletexDecoder, TeletexString AttributeValues are decoded by it to UTF8String AttributeValues.
+func parseDERDN(dnBytes []byte, c parseConfig) (dn DN, err error) {
+	mode := c.mode
 	var idn innerDN
 	err = idn.unmarshal(dnBytes)
 	if err != nil {
+		if mode&Compatible != 0 {
+			if merr := findMissingAttributeValue(dnBytes); merr != nil {
+				err = merr
+			}
+		}
 		err := fmt.Errorf("unable to parse der DN: %w", err)
 		return nil, err
 	}
+
+	if mode&Compatible != 0 {
+		idn.unwrapSingleElementSETValues()
+	}
+	if c.teletexDecoder != nil {
+		if err := idn.decodeTeletexStringValues(c.teletexDecoder); err != nil {
+			err := fmt.Errorf("unable to parse der DN: %w", err)
+			return nil, err
+		}
+	}
 	dn, err = convertToDn(idn)
 	if err != nil {
 		err := fmt.Errorf("unable to parse der DN: %w", err)
 		return nil, err
 	}
 
-	if isValid, err := isValidDN(dn); isValid == false {
-		err := fmt.Errorf("unable to parse der DN: %w", err)
-		return nil, err
-	}
+	if mode&RejectUnknownOIDs != 0 {
+		for i, rdn := range dn {
+			for j, atv := range rdn {
+				if atv.Type == Generic {
+					err := fmt.Errorf("unable to parse der DN: %d th RDN %d th AttributeTypeAndValue: %s is not supported AttributeType oid", i, j, atv.Oid)
+					return nil, err
+				}
+			}
+		}
+	}
+
+	validate := isValidDN
+	if mode&Compatible != 0 {
+		validate = isValidDNIgnoringEncoding
+	}
+	if isValid, err := validate(dn); isValid == false {
+		err := fmt.Errorf("unable to parse der DN: %w", err)
+		return nil, err
+	}
+
+	return dn, nil
+}
+
+// findMissingAttributeValue returns an error describing the first AttributeTypeAndValue of dnBytes
+// which has its AttributeType but is missing its AttributeValue.
+// If there is no such AttributeTypeAndValue or dnBytes can not be walked, returns nil.
+func findMissingAttributeValue(dnBytes []byte) error {
+	var rdns []asn1.RawValue
+	if _, err := asn1.Unmarshal(dnBytes, &rdns); err != nil {
+		return nil
+	}
+	for i, rdn := range rdns {
+		var atvs []asn1.RawValue
+		if _, err := asn1.UnmarshalWithParams(rdn.FullBytes, &atvs, "set"); err != nil {
+			return nil
+		}
+		for j, atv := range atvs {
+			var oid asn1.ObjectIdentifier
+			if rest, err := asn1.Unmarshal(atv.Bytes, &oid); err == nil && len(rest) == 0 {
+				return fmt.Errorf("AttributeTypeAndValue at RDN %d, position %d is missing a value", i, j)
+			}
+		}
+	}
+	return nil
+}
+
+// ParseDERDNFromTagged parses a distinguished name, ASN.1 DER form wrapped in a tag of class and tag and returns DN.
+// class is one of asn1.ClassUniversal, asn1.ClassApplication, asn1.ClassContextSpecific and asn1.ClassPrivate.
+// Both explicit tagging, where the tag wraps the whole SEQUENCE of the distinguished name,
+// and implicit tagging, where the tag replaces the SEQUENCE tag, are accepted.
+// See ParseDERDN for the supported AttributeTypes and Encodings.
+func ParseDERDNFromTagged(b []byte, class int, tag int) (dn DN, err error) {
+	var r asn1.RawValue
+	rest, err := asn1.Unmarshal(b, &r)
+	if err != nil {
+		err := fmt.Errorf("unable to parse tagged der DN: %w", err)
+		return nil, err
+	} else if len(rest) != 0 {
+		err := fmt.Errorf("unable to parse tagged der DN: trailing data after tagged DN")
+		return nil, err
+	}
+	if r.Class != class || r.Tag != tag || !r.IsCompound {
+		err := fmt.Errorf("unable to parse tagged der DN: unexpected tag: class %d, tag %d", r.Class, r.Tag)
+		return nil, err
+	}
+
+	dnBytes := r.Bytes
+	if len(r.Bytes) == 0 || r.Bytes[0] != 0x30 {
+		//implicit tagging: restore the SEQUENCE tag
+		dnBytes, err = asn1.Marshal(asn1.RawValue{Class: asn1.ClassUniversal, Tag: asn1.TagSequence, IsCompound: true, Bytes: r.Bytes})
+		if err != nil {
+			err := fmt.Errorf("unable to parse tagged der DN: %w", err)
+			return nil, err
+		}
+	}
+	return ParseDERDN(dnBytes)
+}
 
+// ParseDirectoryName parses b, the directoryName of GeneralName, that is, a distinguished name, ASN.1 DER form
+// wrapped in an explicit [4] tag, as found in subjectAltName and issuerAltName extensions, and returns DN.
+// Like ParseDERDNFromTagged, the DN whose SEQUENCE tag is replaced by [4], which some encoders emit, is also accepted.
+//
+// https://datatracker.ietf.org/doc/html/rfc5280#section-4.2.1.6
+func ParseDirectoryName(b []byte) (dn DN, err error) {
+	return ParseDERDNFromTagged(b, asn1.ClassContextSpecific, 4)
+}
+
+// MarshalDirectoryName converts dn to the directoryName of GeneralName,
+// that is, a distinguished name, ASN.1 DER form wrapped in an explicit [4] tag.
+func MarshalDirectoryName(dn DN) (b []byte, err error) {
+	dnBytes, err := MarshalDN(dn)
+	if err != nil {
+		return nil, err
+	}
+	b, err = asn1.Marshal(asn1.RawValue{Class: asn1.ClassContextSpecific, Tag: 4, IsCompound: true, Bytes: dnBytes})
+	if err != nil {
+		err := fmt.Errorf("unable to marshal directoryName: %w", err)
+		return nil, err
+	}
+	return b, nil
+}
+
+// OIDTriple represents an AttributeTypeAndValue by the dotted-decimal object identifier of the AttributeType,
+// the Encoding and the value of the AttributeValue.
+type OIDTriple struct {
+	Oid      string
+	Encoding Encoding
+	Value    string
+}
+
+// FromOIDTriples constructs a DN in which each OIDTriple of triples is a single-valued RDN, in the order of triples.
+// Known object identifiers are mapped to the corresponding AttributeTypes, and the others are mapped to Generic.
+// The DN is validated in the same way as MarshalDN.
+func FromOIDTriples(triples []OIDTriple) (dn DN, err error) {
+	dn = DN{}
+	for index, t := range triples {
+		oid, err := convertToObjectIdentifier(t.Oid)
+		if err != nil {
+			err := fmt.Errorf("%d th OIDTriple error: %w", index, err)
+			return nil, err
+		}
+		atv := AttributeTypeAndValue{Type: Generic, Oid: oid.String(), Value: AttributeValue{Encoding: t.Encoding, Value: constructionValue(t.Value)}}
+		if at, err := ReferAttributeTypeName(oid); err == nil {
+			atv = AttributeTypeAndValue{Type: at, Value: atv.Value}
+		}
+		dn = append(dn, RDN{atv})
+	}
+	if isValid, err := isValidDN(dn); isValid == false {
+		return nil, err
+	}
 	return dn, nil
 }
 
+// TrimValues is the construction policy on surrounding whitespace of AttributeValues.
+// If it is true, the constructors of this package, that is, C, CN, O, OU, ST, L, DC, E, AttributeFromOID and FromOIDTriples,
+// trim leading and trailing white space (as defined by Unicode) from values before validating them,
+// e.g. "  Foo  " is constructed as "Foo". Such whitespace is almost always accidental,
+// and causes RFC 4514 escaping noise and matching surprises.
+// It is false by default. Parsers, e.g. ParseDERDN, never trim values, to preserve wire fidelity.
+// It should be set once at initialization, because it is not guarded for concurrent modification.
+var TrimValues = false
+
+// constructionValue returns value after applying the construction policy TrimValues.
+func constructionValue(value string) string {
+	if TrimValues {
+		return strings.TrimSpace(value)
+	}
+	return value
+}
+
+// newAttributeTypeAndValue returns AttributeTypeAndValue of at, enc and value after validating it,
+// including the length of value. See isValidAttributeValueLength.
+// The construction policy TrimValues is applied to value.
+func newAttributeTypeAndValue(at AttributeType, enc Encoding, value string) (AttributeTypeAndValue, error) {
+	atv := AttributeTypeAndValue{Type: at, Value: AttributeValue{Encoding: enc, Value: constructionValue(value)}}
+	if isValid, err := isValidAttributeTypeAndValue(atv, true); isValid == false {
+		return AttributeTypeAndValue{}, err
+	}
+	if isValid, err := isValidAttributeValueLength(at, atv.Value); isValid == false {
+		return AttributeTypeAndValue{}, fmt.Errorf("AttributeTypeAndValue error: %w", err)
+	}
+	return atv, nil
+}
+
+// C returns CountryName AttributeTypeAndValue of value encoded in PrintableString.
+// value should be a two-letter country code, e.g. "JP".
+func C(value string) (AttributeTypeAndValue, error) {
+	value = constructionValue(value)
+	if len(value) != 2 || !isAlpha(value[0]) || !isAlpha(value[1]) {
+		return AttributeTypeAndValue{}, fmt.Errorf("CountryName should be a two-letter country code: %q", value)
+	}
+	return newAttributeTypeAndValue(CountryName, PrintableString, value)
+}
+
+// CN returns CommonName AttributeTypeAndValue of value encoded in enc.
+// enc should be PrintableString, UTF8String, BMPString or TeletexString.
+func CN(enc Encoding, value string) (AttributeTypeAndValue, error) {
+	return newAttributeTypeAndValue(CommonName, enc, value)
+}
+
+// O returns OrganizationName AttributeTypeAndValue of value encoded in enc.
+// enc should be PrintableString, UTF8String, BMPString or TeletexString.
+func O(enc Encoding, value string) (AttributeTypeAndValue, error) {
+	return newAttributeTypeAndValue(OrganizationName, enc, value)
+}
+
+// OU returns OrganizationalUnit AttributeTypeAndValue of value encoded in enc.
+// enc should be PrintableString, UTF8String, BMPString or TeletexString.
+func OU(enc Encoding, value string) (AttributeTypeAndValue, error) {
+	return newAttributeTypeAndValue(OrganizationalUnit, enc, value)
+}
+
+// ST returns StateOrProvinceName AttributeTypeAndValue of value encoded in enc.
+// enc should be PrintableString, UTF8String, BMPString or TeletexString.
+func ST(enc Encoding, value string) (AttributeTypeAndValue, error) {
+	return newAttributeTypeAndValue(StateOrProvinceName, enc, value)
+}
+
+// L returns LocalityName AttributeTypeAndValue of value encoded in enc.
+// enc should be PrintableString, UTF8String, BMPString or TeletexString.
+func L(enc Encoding, value string) (AttributeTypeAndValue, error) {
+	return newAttributeTypeAndValue(LocalityName, enc, value)
+}
+
+// DC returns DomainComponent AttributeTypeAndValue of value encoded in IA5String.
+func DC(value string) (AttributeTypeAndValue, error) {
+	return newAttributeTypeAndValue(DomainComponent, IA5String, value)
+}
+
+// E returns ElectronicMailAddress AttributeTypeAndValue of value encoded in IA5String.
+func E(value string) (AttributeTypeAndValue, error) {
+	return newAttributeTypeAndValue(ElectronicMailAddress, IA5String, value)
+}
+
+// AttributeFromOID returns AttributeTypeAndValue of the AttributeType identified by oid, a dotted-decimal object identifier,
+// and value encoded in enc after validating it.
+// If oid is a known AttributeType oid, the named AttributeType is returned, e.g. CommonName for "2.5.4.3",
+// so that it is displayed and compared in the same way as one constructed with the named AttributeType.
+// Otherwise, Generic with oid is returned.
+func AttributeFromOID(oid string, enc Encoding, value string) (AttributeTypeAndValue, error) {
+	o, err := convertToObjectIdentifier(oid)
+	if err != nil {
+		return AttributeTypeAndValue{}, err
+	}
+	if at, err := ReferAttributeTypeName(o); err == nil {
+		return newAttributeTypeAndValue(at, enc, value)
+	}
+	atv := AttributeTypeAndValue{Type: Generic, Oid: o.String(), Value: AttributeValue{Encoding: enc, Value: constructionValue(value)}}
+	if isValid, err := isValidAttributeTypeAndValue(atv, true); isValid == false {
+		return AttributeTypeAndValue{}, err
+	}
+	return atv, nil
+}
+
+// isAlpha reports whether c is an ASCII letter.
+func isAlpha(c byte) bool {
+	return ('a' <= c && c <= 'z') || ('A' <= c && c <= 'Z')
+}
+
+// MaxOIDArcs is the maximum number of arcs of a dotted-decimal object identifier accepted by this package,
+// e.g. as Oid of Generic or the value of OIDValue. It protects against abusive input.
+var MaxOIDArcs = 128
+
+// MaxOIDArcValue is the maximum value of each arc of a dotted-decimal object identifier accepted by this package.
+// It protects against abusive input.
+var MaxOIDArcValue = math.MaxInt32
+
 func convertToObjectIdentifier(o string) (oid asn1.ObjectIdentifier, err error) {
+	if n := strings.Count(o, ".") + 1; n > MaxOIDArcs {
+		err := fmt.Errorf("ObjectIdentifier convert error: %d arcs exceeds the maximum %d arcs", n, MaxOIDArcs)
+		return nil, err
+	}
 	sa := strings.Split(o, ".")
 	if len(sa) == 0 {
 		return nil, errors.New("ObjectIdentifier has no elements")
@@ -830,6 +1974,10 @@ func convertToObjectIdentifier(o string) (oid asn1.ObjectIdentifier, err error)
 			err := fmt.Errorf("ObjectIdentifier convert error: %w", err)
 			return nil, err
 		}
+		if c > MaxOIDArcValue {
+			err := fmt.Errorf("ObjectIdentifier convert error: OID value %d exceeds the maximum %d", c, MaxOIDArcValue)
+			return nil, err
+		}
 		oid = append(oid, c)
 	}
 	return oid, nil
@@ -837,7 +1985,7 @@ func convertToObjectIdentifier(o string) (oid asn1.ObjectIdentifier, err error)
 
 func convertToInnerAttributeTypeAndValue(atv AttributeTypeAndValue) (innerAttributeTypeAndValue, error) {
 	v := atv.Value
-	srv, err := newStringRawValue(v.Encoding, v.Value)
+	srv, err := newRawValue(v.Encoding, v.Value)
 	if err != nil {
 		err := fmt.Errorf("AttributeTypeAndValue marshal error: %w", err)
 		return innerAttributeTypeAndValue{}, err
@@ -893,11 +2041,14 @@ func convertToInnerDN(dn DN) (innerDN, error) {
 
 // MarshalDN converts a DN to distinguished name (DN), ASN.1 DER form.
 // RDN of the DN should have at least one AttributeTypeAndValue element.
-// AttributeValue currently supports the following ASN.1 string encodings:
+// AttributeValue currently supports the following ASN.1 string encodings and OBJECT IDENTIFIER:
 //
 //	PrintableString
 //	UTF8String
 //	IA5String
+//	BMPString
+//	TeletexString (T61String, as ISO 8859-1)
+//	OIDValue (OBJECT IDENTIFIER)
 //
 // AttributeType currently supports the following AttributeTypes:
 //
@@ -917,54 +2068,236 @@ func convertToInnerDN(dn DN) (innerDN, error) {
 //	GenerationQualifier (2.5.4.44)
 //	ElectronicMailAddress (1.2.840.113549.1.9.1)
 //	DomainComponent (0.9.2342.19200300.100.1.25)
+//	UnstructuredName (1.2.840.113549.1.9.2)
+//	UnstructuredAddress (1.2.840.113549.1.9.8)
+//	StreetAddress (2.5.4.9)
+//	PostalCode (2.5.4.17)
+//	UserID (0.9.2342.19200300.100.1.1)
 //	Generic (Any OBJECT IDENTIFIER)
 //
 // Any object identifier can be specified by setting Generic to Type and object identifier to Oid.
 // If Type is Generic, Oid must be specified.
+// Other object identifiers can be registered as AttributeTypes with RegisterAttributeType.
 //
 // Currently, the following combinations of OBJECT IDENTIFIER for AttributeType
 // and Encoding for AttributeValue are supported:
 //
 //	CountryName (2.5.4.6) : PrintableString
-//	OrganizationName (2.5.4.10) : PrintableString or UTF8String
-//	OrganizationalUnit (2.5.4.11) : PrintableString or UTF8String
+//	OrganizationName (2.5.4.10) : PrintableString or UTF8String or BMPString or TeletexString
+//	OrganizationalUnit (2.5.4.11) : PrintableString or UTF8String or BMPString or TeletexString
 //	DnQualifier (2.5.4.46) : PrintableString
-//	StateOrProvinceName (2.5.4.8) : PrintableString or UTF8String
-//	CommonName (2.5.4.3) : PrintableString or UTF8String
+//	StateOrProvinceName (2.5.4.8) : PrintableString or UTF8String or BMPString or TeletexString
+//	CommonName (2.5.4.3) : PrintableString or UTF8String or BMPString or TeletexString
 //	SerialNumber (2.5.4.5) : PrintableString
-//	LocalityName (2.5.4.7) : PrintableString or UTF8String
-//	Title (2.5.4.12) : PrintableString or UTF8String
-//	Surname (2.5.4.4) : PrintableString or UTF8String
-//	GivenName (2.5.4.42) : PrintableString or UTF8String
-//	Initials (2.5.4.43) : PrintableString or UTF8String
-//	Pseudonym (2.5.4.65) : PrintableString or UTF8String
-//	GenerationQualifier (2.5.4.44) : PrintableString or UTF8String
+//	LocalityName (2.5.4.7) : PrintableString or UTF8String or BMPString or TeletexString
+//	Title (2.5.4.12) : PrintableString or UTF8String or BMPString or TeletexString
+//	Surname (2.5.4.4) : PrintableString or UTF8String or BMPString or TeletexString
+//	GivenName (2.5.4.42) : PrintableString or UTF8String or BMPString or TeletexString
+//	Initials (2.5.4.43) : PrintableString or UTF8String or BMPString or TeletexString
+//	Pseudonym (2.5.4.65) : PrintableString or UTF8String or BMPString or TeletexString
+//	GenerationQualifier (2.5.4.44) : PrintableString or UTF8String or BMPString or TeletexString
 //	ElectronicMailAddress (1.2.840.113549.1.9.1) : IA5String
 //	DomainComponent (0.9.2342.19200300.100.1.25) : IA5String
-//	Generic (Any OBJECT IDENTIFIER other than those already listed) : PrintableString or UTF8String or IA5String
+//	UnstructuredName (1.2.840.113549.1.9.2) : IA5String or UTF8String
+//	UnstructuredAddress (1.2.840.113549.1.9.8) : PrintableString or UTF8String or BMPString or TeletexString
+//	StreetAddress (2.5.4.9) : PrintableString or UTF8String or BMPString or TeletexString
+//	PostalCode (2.5.4.17) : PrintableString or UTF8String or BMPString or TeletexString
+//	UserID (0.9.2342.19200300.100.1.1) : IA5String or PrintableString or UTF8String or BMPString or TeletexString
+//	Generic (Any OBJECT IDENTIFIER other than those already listed) : PrintableString or UTF8String or IA5String or OIDValue or BMPString or TeletexString
+//
+// The number of characters of AttributeValue is checked against the upper bounds (ub-*) of RFC 5280,
+// e.g. 64 for CommonName, only by MarshalDNOpts with WithStrictValidation, so that parsed DNs can be marshaled back.
 //
 // https://datatracker.ietf.org/doc/html/rfc5280#section-4.1.2.4
 // https://datatracker.ietf.org/doc/html/rfc5280#appendix-A.1
 func MarshalDN(dn DN) (dnBytes []byte, err error) {
+	return MarshalDNOpts(dn)
+}
+
+// MustMarshalDN is like MarshalDN but panics if dn can not be marshaled.
+// It is intended for use in tests and in initialization of global variables,
+// where a marshal failure is a programming error.
+func MustMarshalDN(dn DN) []byte {
+	dnBytes, err := MarshalDN(dn)
+	if err != nil {
+		panic("dnutil: MustMarshalDN: " + err.Error())
+	}
+	return dnBytes
+}
+
+// MarshalOption represents an option of MarshalDNOpts.
+type MarshalOption func(*marshalConfig)
+
+type marshalConfig struct {
+	maxSize           int
+	strict            bool
+	preserveOrder     bool
+	emptyValueAllowed map[AttributeType]bool
+}
+
+// WithMaxSize makes MarshalDNOpts fail if the length of the DER form exceeds n bytes.
+func WithMaxSize(n int) MarshalOption {
+	return func(c *marshalConfig) {
+		c.maxSize = n
+	}
+}
+
+// WithStrictValidation makes MarshalDNOpts validate the DN more strictly than MarshalDN.
+// In addition to the validation of MarshalDN, the following are validated:
+//
+//	CountryName is an ISO 3166 alpha-2 code
+//	AttributeValues are within the upper bounds (ub-*) of RFC 5280, e.g. 64 characters for CommonName
+//	AttributeValues of DirectoryString types of RFC 5280 are not empty (see WithEmptyValueAllowed)
+//	AttributeValues other than OIDValue have no C0 or C1 control characters (U+0000 to U+001F, U+007F to U+009F)
+//	AttributeValues pass the validators registered by RegisterValueValidator
+//
+// The DirectoryString types which must not be empty are OrganizationName, OrganizationalUnit, StateOrProvinceName,
+// CommonName, LocalityName, Title, Surname, GivenName, Initials, Pseudonym and GenerationQualifier.
+//
+// Parsing is relaxed: ParseDERDN accepts control characters and values exceeding the upper bounds
+// so that existing certificates can be read.
+func WithStrictValidation() MarshalOption {
+	return func(c *marshalConfig) {
+		c.strict = true
+	}
+}
+
+// WithEmptyValueAllowed makes MarshalDNOpts with WithStrictValidation accept empty AttributeValues of ats,
+// for a policy which permits them against RFC 5280.
+func WithEmptyValueAllowed(ats ...AttributeType) MarshalOption {
+	return func(c *marshalConfig) {
+		if c.emptyValueAllowed == nil {
+			c.emptyValueAllowed = make(map[AttributeType]bool)
+		}
+		for _, at := range ats {
+			c.emptyValueAllowed[at] = true
+		}
+	}
+}
+
+// WithPreserveOrder makes MarshalDNOpts keep the order of AttributeTypeAndValues in each RDN
+// instead of sorting them in DER order.
+// Note that the output is not DER if AttributeTypeAndValues of an RDN are not in DER order.
+func WithPreserveOrder() MarshalOption {
+	return func(c *marshalConfig) {
+		c.preserveOrder = true
+	}
+}
+
+// MarshalDNOpts converts a DN to distinguished name (DN), ASN.1 DER form according to opts.
+// Without opts, MarshalDNOpts is the same as MarshalDN.
+func MarshalDNOpts(dn DN, opts ...MarshalOption) (dnBytes []byte, err error) {
+	var c marshalConfig
+	for _, opt := range opts {
+		opt(&c)
+	}
+
 	if isValid, err := isValidDN(dn); isValid == false {
 		err := fmt.Errorf("unable to marshal DN: %w", err)
 		return nil, err
 	}
 
+	if c.strict {
+		if err := validateStrict(dn, c); err != nil {
+			err := fmt.Errorf("unable to marshal DN: %w", err)
+			return nil, err
+		}
+	}
+
 	idn, err := convertToInnerDN(dn)
 	if err != nil {
 		err := fmt.Errorf("unable to marshal DN: %w", err)
 		return nil, err
 	}
 
-	b, err := idn.marshal()
+	var b []byte
+	if c.preserveOrder {
+		b, err = idn.marshalPreservingOrder()
+	} else {
+		b, err = idn.marshal()
+	}
 	if err != nil {
 		err := fmt.Errorf("unable to marshal DN: %w", err)
 		return nil, err
 	}
+
+	if c.maxSize > 0 && len(b) > c.maxSize {
+		err := fmt.Errorf("unable to marshal DN: %d bytes exceeds the maximum size %d bytes", len(b), c.maxSize)
+		return nil, err
+	}
 	return b, nil
 }
 
+// validateStrict validates d with the rules of WithStrictValidation according to c.
+func validateStrict(d DN, c marshalConfig) (err error) {
+	for i, rdn := range d {
+		for j, atv := range rdn {
+			if at := atv.resolvedType(); atv.Value.Value == "" && nonEmptyValueTable[at] && !c.emptyValueAllowed[at] {
+				return fmt.Errorf("%d th RDN element %d th AttributeTypeAndValue element strict validating error: %s must not be empty", i, j, at)
+			}
+			if atv.resolvedType() == CountryName {
+				if _, err := ValidateCountryCode(atv.Value.Value); err != nil {
+					return fmt.Errorf("%d th RDN element %d th AttributeTypeAndValue element strict validating error: %w", i, j, err)
+				}
+			}
+			if isValid, err := isValidAttributeValueLength(atv.resolvedType(), atv.Value); isValid == false {
+				return fmt.Errorf("%d th RDN element %d th AttributeTypeAndValue element strict validating error: %w", i, j, err)
+			}
+			if r, ok := findControlCharacter(atv.Value); ok {
+				return fmt.Errorf("%d th RDN element %d th AttributeTypeAndValue element strict validating error: control character U+%04X is not allowed", i, j, r)
+			}
+			if fn := valueValidator(atv.resolvedType()); fn != nil {
+				if err := fn(atv.Value.Value); err != nil {
+					return fmt.Errorf("%d th RDN element %d th AttributeTypeAndValue element strict validating error: %w", i, j, err)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// findControlCharacter returns the first C0 or C1 control character in the value of av.
+// OIDValue is not checked. If there is no control character, returns false.
+func findControlCharacter(av AttributeValue) (rune, bool) {
+	if av.Encoding == OIDValue {
+		return 0, false
+	}
+	for _, r := range av.Value {
+		if unicode.IsControl(r) {
+			return r, true
+		}
+	}
+	return 0, false
+}
+
+var valueValidators = struct {
+	sync.RWMutex
+	m map[AttributeType]func(string) error
+}{m: make(map[AttributeType]func(string) error)}
+
+// RegisterValueValidator registers fn as the validator of values of at, which is invoked by MarshalDNOpts
+// with WithStrictValidation, e.g. to validate the format of SerialNumber of device certificates.
+// fn returns an error if the value is not valid. Generic whose Oid is a known AttributeType oid is validated
+// by the validator of the known AttributeType.
+// Only one validator can be registered per AttributeType; registering replaces the previous one,
+// and registering nil removes it. RegisterValueValidator is safe for concurrent use.
+func RegisterValueValidator(at AttributeType, fn func(string) error) {
+	valueValidators.Lock()
+	defer valueValidators.Unlock()
+	if fn == nil {
+		delete(valueValidators.m, at)
+		return
+	}
+	valueValidators.m[at] = fn
+}
+
+// valueValidator returns the validator registered for at, or nil.
+func valueValidator(at AttributeType) func(string) error {
+	valueValidators.RLock()
+	defer valueValidators.RUnlock()
+	return valueValidators.m[at]
+}
+
 func (e Encoding) String() string {
 	switch e {
 	case PrintableString:
@@ -973,11 +2306,128 @@ func (e Encoding) String() string {
 		return "UTF8String"
 	case IA5String:
 		return "IA5String"
+	case OIDValue:
+		return "OIDValue"
+	case BMPString:
+		return "BMPString"
+	case TeletexString:
+		return "TeletexString"
 	default:
 		return "Not Supported Encoding"
 	}
 }
 
+// encodingFromString returns the Encoding whose String() is name.
+func encodingFromString(name string) (Encoding, error) {
+	switch name {
+	case PrintableString.String():
+		return PrintableString, nil
+	case UTF8String.String():
+		return UTF8String, nil
+	case IA5String.String():
+		return IA5String, nil
+	case OIDValue.String():
+		return OIDValue, nil
+	case BMPString.String():
+		return BMPString, nil
+	case TeletexString.String():
+		return TeletexString, nil
+	default:
+		return 0, fmt.Errorf("%s is not supported Encoding", name)
+	}
+}
+
+// attributeTypeFromString returns the AttributeType whose String() is name.
+func attributeTypeFromString(name string) (AttributeType, error) {
+	if at, ok := attributeTypeNameTable[name]; ok {
+		return at, nil
+	}
+	if at, ok := registeredAttributeTypeByShortName(name); ok {
+		return at, nil
+	}
+	return 0, fmt.Errorf("%s is not supported AttributeType", name)
+}
+
+// MarshalJSON implements json.Marshaler.
+// The AttributeType is represented by its String() name, e.g. "CommonName", rather than its integer value,
+// so that the representation does not depend on the order of the constants.
+// Returns error if a is not supported AttributeType.
+func (a AttributeType) MarshalJSON() ([]byte, error) {
+	if _, err := isValidAttributeType(a); err != nil {
+		return nil, fmt.Errorf("%d is not supported AttributeType", int(a))
+	}
+	return json.Marshal(a.String())
+}
+
+// UnmarshalJSON implements json.Unmarshaler. See AttributeType.MarshalJSON.
+// Returns error if the name is not supported.
+func (a *AttributeType) UnmarshalJSON(b []byte) error {
+	var name string
+	if err := json.Unmarshal(b, &name); err != nil {
+		return err
+	}
+	at, err := attributeTypeFromString(name)
+	if err != nil {
+		return fmt.Errorf("AttributeType unmarshal error: %w", err)
+	}
+	*a = at
+	return nil
+}
+
+// attributeValueJSON is the JSON representation of AttributeValue.
+type attributeValueJSON struct {
+	Encoding string
+	Value    string
+}
+
+// MarshalJSON implements json.Marshaler.
+// The Encoding is represented by its name, e.g. {"Encoding":"UTF8String","Value":"abc"}.
+func (av AttributeValue) MarshalJSON() ([]byte, error) {
+	return json.Marshal(attributeValueJSON{Encoding: av.Encoding.String(), Value: av.Value})
+}
+
+// UnmarshalJSON implements json.Unmarshaler. See AttributeValue.MarshalJSON.
+// Returns error if the Encoding name is not supported.
+func (av *AttributeValue) UnmarshalJSON(b []byte) error {
+	var j attributeValueJSON
+	if err := json.Unmarshal(b, &j); err != nil {
+		return err
+	}
+	enc, err := encodingFromString(j.Encoding)
+	if err != nil {
+		return fmt.Errorf("AttributeValue unmarshal error: %w", err)
+	}
+	*av = AttributeValue{Encoding: enc, Value: j.Value}
+	return nil
+}
+
+// attributeTypeAndValueJSON is the JSON representation of AttributeTypeAndValue.
+type attributeTypeAndValueJSON struct {
+	Type  AttributeType
+	Value AttributeValue
+	Oid   string `json:",omitempty"`
+}
+
+// MarshalJSON implements json.Marshaler.
+// The AttributeType is represented by its name (see AttributeType.MarshalJSON), and Oid is output only if it is specified,
+// e.g. {"Type":"CommonName","Value":{"Encoding":"UTF8String","Value":"abc"}}
+// or {"Type":"Generic","Value":{"Encoding":"UTF8String","Value":"abc"},"Oid":"1.2.3.4"}.
+// DN and RDN are represented as arrays of them.
+func (atv AttributeTypeAndValue) MarshalJSON() ([]byte, error) {
+	return json.Marshal(attributeTypeAndValueJSON{Type: atv.Type, Value: atv.Value, Oid: atv.Oid})
+}
+
+// UnmarshalJSON implements json.Unmarshaler. See AttributeTypeAndValue.MarshalJSON.
+// Returns error if the AttributeType name or the Encoding name is not supported.
+func (atv *AttributeTypeAndValue) UnmarshalJSON(b []byte) error {
+	var j attributeTypeAndValueJSON
+	if err := json.Unmarshal(b, &j); err != nil {
+		return fmt.Errorf("AttributeTypeAndValue unmarshal error: %w", err)
+	}
+	*atv = AttributeTypeAndValue{Type: j.Type, Value: j.Value, Oid: j.Oid}
+	return nil
+}
+
 // marshal returns the DER-encoded ASN.1 data dnAsn1Bytes of id.
 func (id *innerDN) marshal() (dnAsn1Bytes []byte, err error) {
 	b, err := asn1.Marshal(*id)
@@ -988,6 +2438,29 @@ func (id *innerDN) marshal() (dnAsn1Bytes []byte, err error) {
 	return b, nil
 }
 
+// marshalPreservingOrder returns the ASN.1 data dnAsn1Bytes of id without sorting the elements of each SET.
+func (id *innerDN) marshalPreservingOrder() (dnAsn1Bytes []byte, err error) {
+	rdns := []asn1.RawValue{}
+	for _, irdn := range *id {
+		var content []byte
+		for _, iatv := range irdn {
+			b, err := asn1.Marshal(iatv)
+			if err != nil {
+				err := fmt.Errorf("marshal error: %w", err)
+				return nil, err
+			}
+			content = append(content, b...)
+		}
+		rdns = append(rdns, asn1.RawValue{Class: asn1.ClassUniversal, Tag: asn1.TagSet, IsCompound: true, Bytes: content})
+	}
+	b, err := asn1.Marshal(rdns)
+	if err != nil {
+		err := fmt.Errorf("marshal error: %w", err)
+		return nil, err
+	}
+	return b, nil
+}
+
 // unmarshal parses the DER-encoded ASN.1 data dnAsn1Bytes and fills in id.
 func (id *innerDN) unmarshal(dnAsn1Bytes []byte) (err error) {
 	if rest, err := asn1.Unmarshal(dnAsn1Bytes, id); err != nil {
@@ -1000,20 +2473,91 @@ func (id *innerDN) unmarshal(dnAsn1Bytes []byte) (err error) {
 	return err
 }
 
+// unwrapSingleElementSETValues replaces every AttributeValue of id that is wrapped in an extra SET
+// with a single element by the element itself.
+func (id *innerDN) unwrapSingleElementSETValues() {
+	for i := range *id {
+		for j := range (*id)[i] {
+			v := (*id)[i][j].Value
+			if v.Class != asn1.ClassUniversal || v.Tag != asn1.TagSet || !v.IsCompound {
+				continue
+			}
+			var inner asn1.RawValue
+			if rest, err := asn1.Unmarshal(v.Bytes, &inner); err != nil || len(rest) != 0 {
+				//not a single element
+				continue
+			}
+			(*id)[i][j].Value = inner
+		}
+	}
+}
+
+// decodeTeletexStringValues replaces each TeletexString AttributeValue in id with UTF8String AttributeValue
+// of its contents octets decoded by decode.
+func (id *innerDN) decodeTeletexStringValues(decode func(b []byte) (string, error)) error {
+	for i := range *id {
+		for j := range (*id)[i] {
+			v := (*id)[i][j].Value
+			if v.Class != asn1.ClassUniversal || v.Tag != asn1.TagT61String || v.IsCompound {
+				continue
+			}
+			st, err := decode(v.Bytes)
+			if err != nil {
+				return fmt.Errorf("%d th RDN %d th AttributeTypeAndValue: TeletexString decoding error: %w", i, j, err)
+			}
+			r, err := newStringRawValue(UTF8String, st)
+			if err != nil {
+				return fmt.Errorf("%d th RDN %d th AttributeTypeAndValue: TeletexString decoding error: %w", i, j, err)
+			}
+			(*id)[i][j].Value = r
+		}
+	}
+	return nil
+}
+
+// newRawValue constructs new RawValue instance of v encoded with specified e.
+// If e is OIDValue, v must be a dotted-decimal object identifier. Otherwise, see newStringRawValue.
+func newRawValue(e Encoding, v string) (r asn1.RawValue, err error) {
+	if e != OIDValue {
+		return newStringRawValue(e, v)
+	}
+	o, err := convertToObjectIdentifier(v)
+	if err != nil {
+		err = fmt.Errorf("AttributeValue creating error: %w", err)
+		return asn1.RawValue{}, err
+	}
+	b, err := asn1.Marshal(o)
+	if err != nil {
+		err = fmt.Errorf("AttributeValue creating error: %w", err)
+		return asn1.RawValue{}, err
+	}
+	return asn1.RawValue{Tag: asn1.TagOID, FullBytes: b}, nil
+}
+
 // newStringRawValue constructs new RawValue instance of st encoded with specified e.
-// e can specify PrintableString, UTF8string, IA5String encoding only.
-// TeletexString, UniversalString, BMPString are not supported.
+// e can specify PrintableString, UTF8string, IA5String, BMPString, TeletexString encoding only.
+// UniversalString is not supported.
 func newStringRawValue(e Encoding, st string) (r asn1.RawValue, err error) {
 	var b []byte
 	var p string
 	var t int
 	switch e {
+	case BMPString:
+		return newEncodedStringRawValue(asn1.TagBMPString, st, encodeBMPString)
+	case TeletexString:
+		return newEncodedStringRawValue(asn1.TagT61String, st, encodeLatin1)
+	}
+	switch e {
 	case PrintableString:
 		p = "printable"
 		t = asn1.TagPrintableString
 	case UTF8String:
 		p = "utf8"
 		t = asn1.TagUTF8String
+		if sr, i, ok := findSurrogate(st); ok {
+			err = fmt.Errorf("AttributeValue creating error: UTF8String must not contain surrogate code point U+%04X at byte %d", sr, i)
+			return asn1.RawValue{}, err
+		}
 	case IA5String:
 		p = "ia5"
 		t = asn1.TagIA5String
@@ -1033,28 +2577,99 @@ func newStringRawValue(e Encoding, st string) (r asn1.RawValue, err error) {
 	return r, nil
 }
 
-// ReferOid returns corresponding ObjectIdentifier of atn.
-// If not supported AttributeType is specified, then returns blank ObjectIdentifier and error.
-// The following AttributeType are currently supported:
-//
-//	2.5.4.6  CountryName
-//	2.5.4.10  OrganizationName
-//	2.5.4.11  OrganizationalUnit
-//	2.5.4.46  DnQualifier
-//	2.5.4.8  StateOrProvinceName
-//	2.5.4.3  CommonName
-//	2.5.4.5  SerialNumber
-//	2.5.4.7  LocalityName
-//	2.5.4.12  Title
-//	2.5.4.4  Surname
-//	2.5.4.42  GivenName
-//	2.5.4.43  Initials
-//	2.5.4.65  Pseudonym
-//	2.5.4.44  GenerationQualifier
-//	1.2.840.113549.1.9.1  ElectronicMailAddress
-//	0.9.2342.19200300.100.1.25  DomainComponent
-//
-// https://datatracker.ietf.org/doc/html/rfc5280#section-4.1.2.4
+// newEncodedStringRawValue constructs new RawValue instance of tag t whose contents octets are st encoded by encode.
+func newEncodedStringRawValue(t int, st string, encode func(st string) ([]byte, error)) (r asn1.RawValue, err error) {
+	if !utf8.ValidString(st) {
+		err = fmt.Errorf("AttributeValue creating error: string is not valid UTF-8")
+		return asn1.RawValue{}, err
+	}
+	bs, err := encode(st)
+	if err != nil {
+		err = fmt.Errorf("AttributeValue creating error: %w", err)
+		return asn1.RawValue{}, err
+	}
+	b, err := asn1.Marshal(asn1.RawValue{Class: asn1.ClassUniversal, Tag: t, Bytes: bs})
+	if err != nil {
+		err = fmt.Errorf("AttributeValue creating error: %w", err)
+		return asn1.RawValue{}, err
+	}
+	r = asn1.RawValue{
+		Tag:       t,
+		FullBytes: b,
+	}
+	return r, nil
+}
+
+// encodeBMPString encodes st to the contents octets of ASN.1 BMPString, big-endian UCS-2.
+// If st has a code point over U+FFFF, returns error, because BMPString can not represent it with a surrogate pair.
+func encodeBMPString(st string) ([]byte, error) {
+	b := make([]byte, 0, len(st)*2)
+	for _, r := range st {
+		if r > 0xFFFF {
+			return nil, fmt.Errorf("BMPString can not contain U+%04X, which is not in the Basic Multilingual Plane", r)
+		}
+		b = append(b, byte(r>>8), byte(r))
+	}
+	return b, nil
+}
+
+// encodeLatin1 encodes st as ISO 8859-1 (Latin-1), in which each code point is the octet of the same value.
+// If st has a code point over U+00FF, returns error.
+func encodeLatin1(st string) ([]byte, error) {
+	b := make([]byte, 0, len(st))
+	for _, r := range st {
+		if r > 0xFF {
+			return nil, fmt.Errorf("TeletexString can not contain U+%04X, which is not ISO 8859-1", r)
+		}
+		b = append(b, byte(r))
+	}
+	return b, nil
+}
+
+// findSurrogate returns the first surrogate code point (U+D800 to U+DFFF) encoded in s in the UTF-8 manner
+// (also known as WTF-8 or CESU-8), and its byte index. Such bytes are not valid UTF-8.
+// If s has no such code point, returns false.
+func findSurrogate(s string) (sr rune, index int, ok bool) {
+	for i := 0; i+2 < len(s); i++ {
+		if s[i] == 0xED && s[i+1] >= 0xA0 && s[i+1] <= 0xBF && s[i+2]&0xC0 == 0x80 {
+			return 0xD000 | rune(s[i+1]&0x3F)<<6 | rune(s[i+2]&0x3F), i, true
+		}
+	}
+	return 0, 0, false
+}
+
+// ErrGenericRequiresOid is returned by ReferOid when Generic is specified.
+// Generic has no ObjectIdentifier of its own. It is taken from Oid of the AttributeTypeAndValue.
+var ErrGenericRequiresOid = errors.New("Generic AttributeType requires Oid")
+
+// ReferOid returns corresponding ObjectIdentifier of atn.
+// If Generic is specified, then returns blank ObjectIdentifier and ErrGenericRequiresOid.
+// If not supported AttributeType is specified, then returns blank ObjectIdentifier and error.
+// AttributeTypes registered by RegisterAttributeType are supported in addition to the following AttributeType:
+//
+//	2.5.4.6  CountryName
+//	2.5.4.10  OrganizationName
+//	2.5.4.11  OrganizationalUnit
+//	2.5.4.46  DnQualifier
+//	2.5.4.8  StateOrProvinceName
+//	2.5.4.3  CommonName
+//	2.5.4.5  SerialNumber
+//	2.5.4.7  LocalityName
+//	2.5.4.12  Title
+//	2.5.4.4  Surname
+//	2.5.4.42  GivenName
+//	2.5.4.43  Initials
+//	2.5.4.65  Pseudonym
+//	2.5.4.44  GenerationQualifier
+//	1.2.840.113549.1.9.1  ElectronicMailAddress
+//	0.9.2342.19200300.100.1.25  DomainComponent
+//	1.2.840.113549.1.9.2  UnstructuredName
+//	1.2.840.113549.1.9.8  UnstructuredAddress
+//	2.5.4.9  StreetAddress
+//	2.5.4.17  PostalCode
+//	0.9.2342.19200300.100.1.1  UserID
+//
+// https://datatracker.ietf.org/doc/html/rfc5280#section-4.1.2.4
 // https://datatracker.ietf.org/doc/html/rfc5280#appendix-A.1
 func ReferOid(atn AttributeType) (oid asn1.ObjectIdentifier, err error) {
 	switch atn {
@@ -1074,7 +2689,17 @@ func ReferOid(atn AttributeType) (oid asn1.ObjectIdentifier, err error) {
 	case GenerationQualifier:
 	case ElectronicMailAddress:
 	case DomainComponent:
+	case UnstructuredName:
+	case UnstructuredAddress:
+	case StreetAddress:
+	case PostalCode:
+	case UserID:
+	case Generic:
+		return asn1.ObjectIdentifier{}, ErrGenericRequiresOid
 	default:
+		if oid, ok := registeredOid(atn); ok {
+			return oid, nil
+		}
 		err = fmt.Errorf("not supported AttributeType")
 		return asn1.ObjectIdentifier{}, err
 	}
@@ -1083,7 +2708,7 @@ func ReferOid(atn AttributeType) (oid asn1.ObjectIdentifier, err error) {
 
 // ReferAttributeTypeName returns corresponding AttributeType of ObjectIdentifier.
 // If not supported ObjectIdentifier is specified, then returns 0 and error.
-// The following ObjectIdentifier are currently supported:
+// ObjectIdentifiers registered by RegisterAttributeType are supported in addition to the following ObjectIdentifier:
 //
 //	2.5.4.6  CountryName
 //	2.5.4.10  OrganizationName
@@ -1101,16 +2726,174 @@ func ReferOid(atn AttributeType) (oid asn1.ObjectIdentifier, err error) {
 //	2.5.4.44  GenerationQualifier
 //	1.2.840.113549.1.9.1  ElectronicMailAddress
 //	0.9.2342.19200300.100.1.25  DomainComponent
+//	1.2.840.113549.1.9.2  UnstructuredName
+//	1.2.840.113549.1.9.8  UnstructuredAddress
+//	2.5.4.9  StreetAddress
+//	2.5.4.17  PostalCode
+//	0.9.2342.19200300.100.1.1  UserID
 //
 // https://datatracker.ietf.org/doc/html/rfc5280#section-4.1.2.4
 // https://datatracker.ietf.org/doc/html/rfc5280#appendix-A.1
 func ReferAttributeTypeName(oid asn1.ObjectIdentifier) (atn AttributeType, err error) {
-	if isDefinedOid(oid) {
-		return attributeTypeTable[oid.String()], nil
+	if at, ok := attributeTypeTable[oid.String()]; ok {
+		return at, nil
+	}
+	if at, ok := registeredAttributeType(oid); ok {
+		return at, nil
 	}
 	return 0, fmt.Errorf("%s is not supported AttributeType oid", oid.String())
 }
 
+// AttributeTypeFromShortName returns corresponding AttributeType of the descriptor name.
+// Both short names (e.g. "cn") and long names (e.g. "commonName") are accepted, and they are case insensitive.
+// Short names of AttributeTypes registered by RegisterAttributeType are also accepted.
+// If not supported name is specified, then returns 0 and error.
+//
+// https://www.rfc-editor.org/rfc/rfc4512#section-1.4
+// https://www.iana.org/assignments/ldap-parameters/ldap-parameters.xhtml
+func AttributeTypeFromShortName(name string) (atn AttributeType, err error) {
+	if at, ok := descriptorTable[strings.ToLower(name)]; ok {
+		return at, nil
+	}
+	if at, ok := registeredAttributeTypeByShortName(name); ok {
+		return at, nil
+	}
+	return 0, fmt.Errorf("%s is not supported AttributeType name", name)
+}
+
+// firstCustomAttributeType is the AttributeType allocated first by RegisterAttributeType.
+// It is far above the built-in AttributeTypes, so that adding a built-in AttributeType does not collide with registered ones.
+const firstCustomAttributeType AttributeType = 10000
+
+// customAttributeTypes holds the AttributeTypes registered by RegisterAttributeType.
+// The built-in tables are not modified after init, so they can be read without locking.
+var customAttributeTypes = struct {
+	sync.RWMutex
+	next               AttributeType
+	oidTable           map[AttributeType]asn1.ObjectIdentifier
+	attributeTypeTable map[string]AttributeType
+	shortNameTable     map[AttributeType]string
+	descriptorTable    map[string]AttributeType
+}{
+	next:               firstCustomAttributeType,
+	oidTable:           make(map[AttributeType]asn1.ObjectIdentifier),
+	attributeTypeTable: make(map[string]AttributeType),
+	shortNameTable:     make(map[AttributeType]string),
+	descriptorTable:    make(map[string]AttributeType),
+}
+
+// RegisterAttributeType registers oid with shortName as a custom AttributeType, e.g. an organization-specific oid,
+// and returns the newly allocated AttributeType, which is above the built-in AttributeTypes.
+// A registered AttributeType is treated like the built-in ones rather than Generic:
+// ReferOid, ReferAttributeTypeName and AttributeTypeFromShortName resolve it, ParseDERDN returns it,
+// and string representations use shortName as its descriptor.
+// As with Generic, its AttributeValue can be PrintableString or UTF8String or IA5String or OIDValue or BMPString or TeletexString.
+//
+// If oid is already registered, returns the registered AttributeType and shortName is ignored.
+// Returns error if oid is invalid or is the oid of a built-in AttributeType,
+// or if shortName is not a descriptor (keystring) or is already used by another AttributeType.
+// Descriptors are case insensitive. RegisterAttributeType is safe for concurrent use.
+//
+// https://www.rfc-editor.org/rfc/rfc4512#section-1.4
+func RegisterAttributeType(oid asn1.ObjectIdentifier, shortName string) (AttributeType, error) {
+	o, err := convertToObjectIdentifier(oid.String())
+	if err != nil {
+		return 0, fmt.Errorf("AttributeType registering error: %w", err)
+	}
+	if _, err := asn1.Marshal(o); err != nil {
+		return 0, fmt.Errorf("AttributeType registering error: %w", err)
+	}
+	if _, ok := attributeTypeTable[o.String()]; ok {
+		return 0, fmt.Errorf("AttributeType registering error: %s is the oid of built-in AttributeType", o.String())
+	}
+	if !isKeystring(shortName) {
+		return 0, fmt.Errorf("AttributeType registering error: %s is not a descriptor", shortName)
+	}
+
+	customAttributeTypes.Lock()
+	defer customAttributeTypes.Unlock()
+	if at, ok := customAttributeTypes.attributeTypeTable[o.String()]; ok {
+		return at, nil
+	}
+	d := strings.ToLower(shortName)
+	if _, ok := customAttributeTypes.descriptorTable[d]; ok || isBuiltinAttributeTypeName(d) {
+		return 0, fmt.Errorf("AttributeType registering error: %s is already used", shortName)
+	}
+
+	at := customAttributeTypes.next
+	customAttributeTypes.next++
+	customAttributeTypes.oidTable[at] = o
+	customAttributeTypes.attributeTypeTable[o.String()] = at
+	customAttributeTypes.shortNameTable[at] = shortName
+	customAttributeTypes.descriptorTable[d] = at
+	return at, nil
+}
+
+// isKeystring reports whether s is a keystring, that is, a descriptor.
+//
+//	keystring = leadkeychar *keychar
+//	leadkeychar = ALPHA
+//	keychar = ALPHA / DIGIT / HYPHEN
+//
+// https://www.rfc-editor.org/rfc/rfc4512#section-1.4
+func isKeystring(s string) bool {
+	if s == "" || !isAlpha(s[0]) {
+		return false
+	}
+	for i := 1; i < len(s); i++ {
+		if !isAlpha(s[i]) && !(s[i] >= '0' && s[i] <= '9') && s[i] != '-' {
+			return false
+		}
+	}
+	return true
+}
+
+// isBuiltinAttributeTypeName reports whether the lowercase name d is a descriptor or a String() name of a built-in AttributeType.
+func isBuiltinAttributeTypeName(d string) bool {
+	if _, ok := descriptorTable[d]; ok {
+		return true
+	}
+	for name := range attributeTypeNameTable {
+		if strings.ToLower(name) == d {
+			return true
+		}
+	}
+	return d == "unknown"
+}
+
+// registeredOid returns the oid of at registered by RegisterAttributeType.
+func registeredOid(at AttributeType) (asn1.ObjectIdentifier, bool) {
+	customAttributeTypes.RLock()
+	defer customAttributeTypes.RUnlock()
+	oid, ok := customAttributeTypes.oidTable[at]
+	return oid, ok
+}
+
+// registeredAttributeType returns the AttributeType of oid registered by RegisterAttributeType.
+func registeredAttributeType(oid asn1.ObjectIdentifier) (AttributeType, bool) {
+	customAttributeTypes.RLock()
+	defer customAttributeTypes.RUnlock()
+	at, ok := customAttributeTypes.attributeTypeTable[oid.String()]
+	return at, ok
+}
+
+// registeredShortName returns the short name of at registered by RegisterAttributeType.
+func registeredShortName(at AttributeType) (string, bool) {
+	customAttributeTypes.RLock()
+	defer customAttributeTypes.RUnlock()
+	name, ok := customAttributeTypes.shortNameTable[at]
+	return name, ok
+}
+
+// registeredAttributeTypeByShortName returns the AttributeType whose short name registered by RegisterAttributeType is name.
+// name is case insensitive.
+func registeredAttributeTypeByShortName(name string) (AttributeType, bool) {
+	customAttributeTypes.RLock()
+	defer customAttributeTypes.RUnlock()
+	at, ok := customAttributeTypes.descriptorTable[strings.ToLower(name)]
+	return at, ok
+}
+
 func isDefinedOid(oid asn1.ObjectIdentifier) bool {
 	switch oid.String() {
 	case asn1.ObjectIdentifier{2, 5, 4, 6}.String():
@@ -1129,8 +2912,14 @@ func isDefinedOid(oid asn1.ObjectIdentifier) bool {
 	case asn1.ObjectIdentifier{2, 5, 4, 44}.String():
 	case asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 9, 1}.String():
 	case asn1.ObjectIdentifier{0, 9, 2342, 19200300, 100, 1, 25}.String():
+	case asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 9, 2}.String():
+	case asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 9, 8}.String():
+	case asn1.ObjectIdentifier{2, 5, 4, 9}.String():
+	case asn1.ObjectIdentifier{2, 5, 4, 17}.String():
+	case asn1.ObjectIdentifier{0, 9, 2342, 19200300, 100, 1, 1}.String():
 	default:
-		return false
+		_, ok := registeredAttributeType(oid)
+		return ok
 	}
 	return true
 }
@@ -1180,6 +2969,65 @@ func (r RDN) CountAttributeTypeAndValue() int {
 	return len(r)
 }
 
+// CountAttributeType returns number of AttributeTypeAndValue of the DN whose AttributeType is at.
+// Generic whose Oid is a known AttributeType oid is counted as the known AttributeType.
+func (d DN) CountAttributeType(at AttributeType) int {
+	cnt := 0
+	for _, rdn := range d {
+		for _, atv := range rdn {
+			if atv.resolvedType() == at {
+				cnt++
+			}
+		}
+	}
+	return cnt
+}
+
+// CountOid returns number of AttributeTypeAndValue of the DN whose AttributeType object identifier is oid.
+// oid is the dotted-decimal form, e.g. "2.5.4.3". If oid is invalid, returns 0.
+func (d DN) CountOid(oid string) int {
+	o, err := convertToObjectIdentifier(oid)
+	if err != nil {
+		return 0
+	}
+	cnt := 0
+	for _, rdn := range d {
+		for _, atv := range rdn {
+			if atv.oidString() == o.String() {
+				cnt++
+			}
+		}
+	}
+	return cnt
+}
+
+// HasAttributeType reports whether the DN has at least one AttributeTypeAndValue whose AttributeType is at,
+// regardless of its position or multi-value grouping.
+// Generic whose Oid is a known AttributeType oid is regarded as the known AttributeType.
+func (d DN) HasAttributeType(at AttributeType) bool {
+	return d.CountAttributeType(at) > 0
+}
+
+// HasOid reports whether the DN has at least one AttributeTypeAndValue whose AttributeType object identifier is oid,
+// regardless of its position or multi-value grouping.
+// oid is the dotted-decimal form, e.g. "2.5.4.3". If oid is invalid, returns false.
+func (d DN) HasOid(oid string) bool {
+	return d.CountOid(oid) > 0
+}
+
+// AttributeTypes returns the AttributeTypes of all AttributeTypeAndValues of the DN in DN order.
+// Unlike OIDStrings, duplicated AttributeTypes are not removed.
+// Generic whose Oid is a known AttributeType oid is returned as the known AttributeType.
+func (d DN) AttributeTypes() []AttributeType {
+	ats := []AttributeType{}
+	for _, rdn := range d {
+		for _, atv := range rdn {
+			ats = append(ats, atv.resolvedType())
+		}
+	}
+	return ats
+}
+
 // RetrieveRDN returns the rdn specified by index from the DN.
 func (d DN) RetrieveRDN(index int) (rdn RDN, err error) {
 	if index < 0 || index >= d.CountRDN() {
@@ -1188,6 +3036,50 @@ func (d DN) RetrieveRDN(index int) (rdn RDN, err error) {
 	return d[index], nil
 }
 
+// RemoveRDN returns a new DN with the rdn specified by index removed from the DN. This DN is not modified.
+func (d DN) RemoveRDN(index int) (DN, error) {
+	if index < 0 || index >= d.CountRDN() {
+		return DN{}, fmt.Errorf("index out of bounds error")
+	}
+	rest := make(DN, 0, d.CountRDN()-1)
+	rest = append(rest, d[:index]...)
+	return append(rest, d[index+1:]...), nil
+}
+
+// InsertRDN returns a new DN with rdn inserted before the RDN specified by index. This DN is not modified.
+// If index is CountRDN, rdn is appended. rdn is validated as AppendRDNChecked does.
+func (d DN) InsertRDN(index int, rdn RDN) (DN, error) {
+	if index < 0 || index > d.CountRDN() {
+		return DN{}, fmt.Errorf("index out of bounds error")
+	}
+	if _, err := isValidRDN(rdn, true); err != nil {
+		return DN{}, fmt.Errorf("RDN inserting error: %w", err)
+	}
+	n := make(DN, 0, d.CountRDN()+1)
+	n = append(n, d[:index]...)
+	n = append(n, append(RDN{}, rdn...))
+	return append(n, d[index:]...), nil
+}
+
+// RemoveAttributeTypeAndValue returns a new RDN with the AttributeTypeAndValue specified by index removed from the RDN.
+// This RDN is not modified.
+func (r RDN) RemoveAttributeTypeAndValue(index int) (RDN, error) {
+	if index < 0 || index >= r.CountAttributeTypeAndValue() {
+		return RDN{}, fmt.Errorf("index out of bounds error")
+	}
+	return removeAttributeTypeAndValue(index, r), nil
+}
+
+// LeafLabel returns the RFC4514 Format string of the leaf RDN of the DN, that is, the last RDN in DN order,
+// e.g. "CN=John" or "OU=Dev+OU=Sales". It is intended for labeling a node of a tree.
+// If the DN has no RDN, returns blank string.
+func (d DN) LeafLabel() string {
+	if d.CountRDN() == 0 {
+		return ""
+	}
+	return d[d.CountRDN()-1].ToRFC4514FormatString()
+}
+
 // RetrieveRDNsByOids returns RDN(s) that exactly match the specified oids, AttributeType Oid(s).
 // The order of the AttributeType Oid(s) is ignored because AttributeType Oid(s) is ASN1.SET.
 func (d DN) RetrieveRDNsByOids(oids []string) (rdns []RDN) {
@@ -1233,6 +3125,188 @@ func (d DN) RetrieveRDNsByAttributeTypes(ats []AttributeType) (rdns []RDN) {
 	return rdns
 }
 
+// RetrieveRDNsByAttributeValue returns RDN(s) that contain an AttributeTypeAndValue of at whose AttributeValue matches value,
+// e.g. the RDN(s) where OU is "Engineering". RDN(s) are returned in DN order.
+// AttributeValues are compared by the same matching rules as DN.Equal, e.g. SerialNumber case-exactly,
+// and their Encoding is ignored.
+// Generic whose Oid is a known AttributeType oid is treated as the known AttributeType.
+func (d DN) RetrieveRDNsByAttributeValue(at AttributeType, value string) (rdns []RDN) {
+	rdns = []RDN{}
+	nv := normalizeValue(at, value)
+	for _, rdn := range d {
+		for _, atv := range rdn {
+			if atv.resolvedType() == at && normalizeValue(at, atv.Value.Value) == nv {
+				rdns = append(rdns, rdn)
+				break
+			}
+		}
+	}
+	return rdns
+}
+
+// RetrieveValuesByAttributeType returns the values of all AttributeTypeAndValue(s) of the DN whose AttributeType is at,
+// e.g. all OU values. Values are returned in DN order. If no AttributeTypeAndValue matches, returns empty slice.
+// Generic whose Oid is a known AttributeType oid is treated as the known AttributeType.
+func (d DN) RetrieveValuesByAttributeType(at AttributeType) (values []string) {
+	values = []string{}
+	for _, rdn := range d {
+		for _, atv := range rdn {
+			if atv.resolvedType() == at {
+				values = append(values, atv.Value.Value)
+			}
+		}
+	}
+	return values
+}
+
+// RetrieveValuesByOid returns the values of all AttributeTypeAndValue(s) of the DN whose AttributeType object identifier is oid.
+// oid is the dotted-decimal form, e.g. "2.5.4.11". Values are returned in DN order.
+// If no AttributeTypeAndValue matches or oid is invalid, returns empty slice.
+func (d DN) RetrieveValuesByOid(oid string) (values []string) {
+	values = []string{}
+	o, err := convertToObjectIdentifier(oid)
+	if err != nil {
+		return values
+	}
+	for _, rdn := range d {
+		for _, atv := range rdn {
+			if atv.oidString() == o.String() {
+				values = append(values, atv.Value.Value)
+			}
+		}
+	}
+	return values
+}
+
+// AttributesWithEncoding returns all AttributeTypeAndValue(s) of the DN whose AttributeValue is encoded with enc.
+// AttributeTypeAndValue(s) are returned in DN order.
+func (d DN) AttributesWithEncoding(enc Encoding) (atvs []AttributeTypeAndValue) {
+	atvs = []AttributeTypeAndValue{}
+	for _, rdn := range d {
+		for _, atv := range rdn {
+			if atv.Value.Encoding == enc {
+				atvs = append(atvs, atv)
+			}
+		}
+	}
+	return atvs
+}
+
+// OrgPath returns the organization hierarchy of the DN as a path string, e.g. "Example/Engineering/Backend".
+// The values of OrganizationName and OrganizationalUnit are joined with "/" in DN order,
+// that is, from the top of the hierarchy. The other AttributeTypes are skipped.
+// If the DN has no OrganizationName nor OrganizationalUnit, returns blank string.
+func (d DN) OrgPath() string {
+	var path []string
+	for _, rdn := range d {
+		for _, atv := range rdn {
+			switch atv.resolvedType() {
+			case OrganizationName, OrganizationalUnit:
+				path = append(path, atv.Value.Value)
+			}
+		}
+	}
+	return strings.Join(path, "/")
+}
+
+// EmailAddresses returns the values of all ElectronicMailAddress of the DN in DN order.
+func (d DN) EmailAddresses() []string {
+	emails := []string{}
+	for _, rdn := range d {
+		for _, atv := range rdn {
+			if atv.resolvedType() == ElectronicMailAddress {
+				emails = append(emails, atv.Value.Value)
+			}
+		}
+	}
+	return emails
+}
+
+// SMIMEEmail returns the value of the first ElectronicMailAddress of the DN in DN order, for S/MIME.
+// If the DN has no ElectronicMailAddress or the value does not look like an email address, returns blank string and false.
+// See looksLikeEmail for the check.
+func (d DN) SMIMEEmail() (string, bool) {
+	emails := d.EmailAddresses()
+	if len(emails) == 0 || !looksLikeEmail(emails[0]) {
+		return "", false
+	}
+	return emails[0], true
+}
+
+// looksLikeEmail reports whether s looks like an email address, that is, s has exactly one "@",
+// non-empty local part and domain part, a domain part without leading, trailing or consecutive ".",
+// and no spaces or control characters.
+func looksLikeEmail(s string) bool {
+	local, domain, ok := strings.Cut(s, "@")
+	if !ok || local == "" || domain == "" || strings.Contains(domain, "@") {
+		return false
+	}
+	if strings.HasPrefix(domain, ".") || strings.HasSuffix(domain, ".") || strings.Contains(domain, "..") {
+		return false
+	}
+	for _, r := range s {
+		if unicode.IsSpace(r) || unicode.IsControl(r) {
+			return false
+		}
+	}
+	return true
+}
+
+// SubjectSerialNumber returns the value of the first SerialNumber (2.5.4.5) of the DN in DN order.
+// Note that it is the serialNumber attribute of the DN, e.g. a device identity, not the serial number of a certificate.
+// If the DN has no SerialNumber, returns blank string and false.
+func (d DN) SubjectSerialNumber() (string, bool) {
+	for _, rdn := range d {
+		for _, atv := range rdn {
+			if atv.resolvedType() == SerialNumber {
+				return atv.Value.Value, true
+			}
+		}
+	}
+	return "", false
+}
+
+// OIDStrings returns the dotted-decimal object identifiers of all AttributeTypes of the DN in DN order.
+// Each object identifier appears only once. The object identifier of Generic is taken from Oid.
+func (d DN) OIDStrings() []string {
+	oids := []string{}
+	seen := make(map[string]bool)
+	for _, rdn := range d {
+		for _, atv := range rdn {
+			o := atv.oidString()
+			if o == "" || seen[o] {
+				continue
+			}
+			seen[o] = true
+			oids = append(oids, o)
+		}
+	}
+	return oids
+}
+
+// IsValidIssuer reports whether the DN is suitable as the issuer of a certificate.
+// The DN is regarded as a valid issuer if:
+//
+//	it has at least one RDN
+//	it has at least one CommonName or OrganizationName
+//
+// Otherwise, returns false and an error describing the violated rule.
+// This is a minimal sanity check. Use ValidateProfile for a complete validation.
+func (d DN) IsValidIssuer() (bool, error) {
+	if len(d) == 0 {
+		return false, fmt.Errorf("issuer DN is empty")
+	}
+	for _, rdn := range d {
+		for _, atv := range rdn {
+			switch atv.resolvedType() {
+			case CommonName, OrganizationName:
+				return true, nil
+			}
+		}
+	}
+	return false, fmt.Errorf("issuer DN has neither CommonName nor OrganizationName")
+}
+
 // isMatchedRDN reports whether AttributeType of AttributeTypeAndValue of r RDN matches the specified ats AttributeType(s).
 // Because ats is ASN1.SET, the order of ats is ignored.
 func isMatchedRDN(r RDN, ats []AttributeType) (isMatched bool) {
@@ -1294,265 +3368,1987 @@ func findMatchedOidIndex(r RDN, oid string) (index int) {
 			}
 		}
 
-		o, _ := ReferOid(r[i].Type)
-		if o.String() == oid {
+		if os, ok := attributeTypeOidString(r[i].Type); ok && os == oid {
 			return i
 		}
 	}
 	return -1
 }
 
-func isValidAttributeValueEncoding(av AttributeValue) (isValid bool, err error) {
-	switch av.Encoding {
-	case PrintableString:
-	case UTF8String:
-	case IA5String:
-	default:
-		return false, fmt.Errorf("not supported string encoding error")
+// resolvedType returns the AttributeType of atv.
+// If Type is Generic and Oid is a known AttributeType oid, the known AttributeType is returned.
+func (atv AttributeTypeAndValue) resolvedType() AttributeType {
+	if atv.Type != Generic {
+		return atv.Type
 	}
-	return true, nil
-}
-
-func isValidAttributeTypeAndValue(atv AttributeTypeAndValue) (isValid bool, err error) {
-	if isValid, err = isValidAttributeType(atv.Type); isValid != true {
-		return false, fmt.Errorf("AttributeTypeAndValue error: %w", err)
+	o, err := convertToObjectIdentifier(atv.Oid)
+	if err != nil {
+		return Generic
 	}
-	if isValid, err = isValidAttributeValueEncoding(atv.Value); isValid != true {
-		return false, fmt.Errorf("AttributeTypeAndValue error: %w", err)
+	if at, err := ReferAttributeTypeName(o); err == nil {
+		return at
 	}
+	return Generic
+}
 
+// oidString returns the dotted-decimal oid of the AttributeType of atv.
+// If the oid can not be determined, returns blank string.
+func (atv AttributeTypeAndValue) oidString() string {
 	if atv.Type == Generic {
-		var o asn1.ObjectIdentifier
-		var at AttributeType
-		if o, err = convertToObjectIdentifier(atv.Oid); err != nil {
-			return false, fmt.Errorf("AttributeTypeAndValue error: %w", err)
-		}
-
-		//Check if Oid is one of the member of AttributeType ObjectIdentifier except Generic
-		if isDefinedOid(o) {
-			at, err = ReferAttributeTypeName(o)
-			if err != nil {
-				return false, fmt.Errorf("AttributeTypeAndValue error: %w", err)
-			}
-
-			//Oid is one of the member of AttributeTypes except Generic
-			if isValid, err = isValidAttributeTypeAndAttributeValueComb(at, atv.Value); isValid != true {
-				return false, fmt.Errorf("AttributeTypeAndValue error: %w", err)
-			}
+		o, err := convertToObjectIdentifier(atv.Oid)
+		if err != nil {
+			return atv.Oid
 		}
+		return o.String()
 	}
-
-	if isValid, err = isValidAttributeTypeAndAttributeValueComb(atv.Type, atv.Value); isValid != true {
-		return false, fmt.Errorf("AttributeTypeAndValue error: %w", err)
-	}
-	return true, nil
+	os, _ := attributeTypeOidString(atv.Type)
+	return os
 }
 
-func isValidRDN(r RDN) (isValid bool, err error) {
-	isValid = false
-	if r.CountAttributeTypeAndValue() == 0 {
-		return isValid, errors.New("RDN should have at least one AttributeTypeAndValue")
+// attributeTypeOidString returns the dotted-decimal oid of at, including AttributeTypes registered by RegisterAttributeType.
+func attributeTypeOidString(at AttributeType) (string, bool) {
+	if os, ok := oidStringTable[at]; ok {
+		return os, true
 	}
-	for index, atv := range r {
-		isValid, err = isValidAttributeTypeAndValue(atv)
-		if err != nil {
-			err := fmt.Errorf("%d th AttributeTypeAndValue element validating error: %w", index, err)
-			return isValid, err
-		}
+	if oid, ok := registeredOid(at); ok {
+		return oid.String(), true
 	}
-	return isValid, nil
+	return "", false
 }
 
-func isValidDN(d DN) (isValid bool, err error) {
-	isValid = false
-	if d.CountRDN() == 0 {
-		isValid = true
+// normalizeValue returns v converted for matching as a value of at. See DN.Equal for the matching rules.
+// Leading, trailing and consecutive spaces are removed from values of known AttributeTypes,
+// and values other than SerialNumber are case folded.
+// Values of CountryName are folded to upper case by convention, which keeps them valid PrintableString.
+// Values of Generic are returned as they are, because their matching rule is unknown.
+func normalizeValue(at AttributeType, v string) string {
+	switch at {
+	case Generic:
+		return v
+	case CountryName:
+		return strings.Join(strings.Fields(strings.ToUpper(v)), " ")
+	case SerialNumber:
+		return strings.Join(strings.Fields(v), " ")
+	default:
+		return strings.Join(strings.Fields(strings.ToLower(v)), " ")
 	}
-	for index, rdn := range d {
-		isValid, err = isValidRDN(rdn)
-		if err != nil {
-			err := fmt.Errorf("%d th RDN element validating error: %w", index, err)
-			return isValid, err
+}
+
+// Normalize returns a copy of this DN converted for matching.
+// The following normalizations are applied:
+//
+//	Generic whose Oid is a known AttributeType oid is converted to the known AttributeType.
+//	Leading, trailing and consecutive spaces are removed from AttributeValue of known AttributeTypes.
+//	AttributeValue of known AttributeTypes other than SerialNumber is case folded.
+//	AttributeValue of CountryName is converted to upper case, e.g. "jp" to "JP".
+//	AttributeTypeAndValues of each RDN are sorted by oid and value.
+//
+// The Encoding of each AttributeValue is kept as it is, but it is ignored in matching.
+// The returned DN is intended for matching, not for marshaling.
+func (d DN) Normalize() DN {
+	n := DN{}
+	for _, rdn := range d {
+		n = append(n, rdn.normalize())
+	}
+	return n
+}
+
+// normalize returns a copy of r converted for matching. See DN.Normalize.
+func (r RDN) normalize() RDN {
+	n := make(RDN, 0, len(r))
+	for _, atv := range r {
+		at := atv.resolvedType()
+		natv := AttributeTypeAndValue{Type: at, Value: AttributeValue{Encoding: atv.Value.Encoding, Value: normalizeValue(at, atv.Value.Value)}}
+		if at == Generic {
+			natv.Oid = atv.oidString()
 		}
+		n = append(n, natv)
 	}
-	return isValid, nil
+	sort.SliceStable(n, func(i, j int) bool {
+		oi, oj := n[i].oidString(), n[j].oidString()
+		if oi != oj {
+			return oi < oj
+		}
+		return n[i].Value.Value < n[j].Value.Value
+	})
+	return n
 }
 
-// isPrintableStringOrUTF8StringOrIA5StringEncoding reports whether e is PrintableString or UTF8String or IA5String.
-func isPrintableStringOrUTF8StringOrIA5StringEncoding(e Encoding) (ok bool) {
-	switch e {
-	case PrintableString:
-		return true
-	case UTF8String:
-		return true
-	case IA5String:
-		return true
-	default:
+// Equal reports whether this RDN and other match.
+// AttributeTypeAndValues are compared regardless of their order, because RDN is ASN.1 SET.
+// AttributeValues are compared by the matching rules described in DN.Equal, after the same conversion as DN.Normalize.
+// The Encoding of AttributeValues is ignored, e.g. "Example" encoded as PrintableString
+// and "Example" encoded as UTF8String match, because AttributeValue holds the decoded string.
+func (r RDN) Equal(other RDN) bool {
+	if r.CountAttributeTypeAndValue() != other.CountAttributeTypeAndValue() {
 		return false
 	}
+	nr, no := r.normalize(), other.normalize()
+	for i := range nr {
+		if nr[i].oidString() != no[i].oidString() || nr[i].Value.Value != no[i].Value.Value {
+			return false
+		}
+	}
+	return true
 }
 
-// isPrintableStringOrUTF8StringEncoding reports whether e is PrintableString or UTF8String.
-func isPrintableStringOrUTF8StringEncoding(e Encoding) (ok bool) {
-	switch e {
-	case PrintableString:
-		return true
-	case UTF8String:
-		return true
-	default:
+// Equal reports whether this DN and other match, following distinguishedNameMatch of RFC 4517.
+// RDNs are compared in order by RDN.Equal, and AttributeTypeAndValues of each RDN are compared regardless of their order.
+// AttributeValues are prepared by insignificant space handling of RFC 4518, that is, leading, trailing and consecutive
+// spaces are removed, and then compared by the following matching rules:
+//
+//	CountryName : caseIgnoreMatch, folding to upper case as DN.Normalize does, e.g. "jp" matches "JP"
+//	SerialNumber : caseExactMatch
+//	ElectronicMailAddress, DomainComponent : caseIgnoreIA5Match
+//	The other known AttributeTypes, e.g. CommonName : caseIgnoreMatch
+//	Generic : octet-wise match of the AttributeValue without preparation, because its matching rule is unknown
+//
+// Case folding is simple Unicode lower casing, not full case folding of RFC 4518.
+// The Encoding of AttributeValues is ignored.
+// The AttributeValues are prepared in the same way as DN.Normalize, so two DNs match if and only if
+// their CanonicalString, DedupKey and CanonicalHashInput are the same.
+//
+// https://www.rfc-editor.org/rfc/rfc4517#section-4.2.15
+// https://www.rfc-editor.org/rfc/rfc4518#section-2.6.1
+func (d DN) Equal(other DN) bool {
+	if d.CountRDN() != other.CountRDN() {
 		return false
 	}
+	for i := range d {
+		if !d[i].Equal(other[i]) {
+			return false
+		}
+	}
+	return true
 }
 
-// isIA5StringEncoding reports whether e is IA5String.
-func isIA5StringEncoding(e Encoding) (ok bool) {
-	if e == IA5String {
-		return true
+// EqualUnordered reports whether this DN and other match as multisets of RDNs, ignoring the order of RDNs.
+// Each RDN of this DN must match a distinct RDN of other by RDN.Equal.
+// This is NON-standard: the order of RDNs is significant in a DN, and DN.Equal should be used for matching.
+// EqualUnordered is only for lenient matching of DNs whose RDNs were assembled in a different order, e.g. by a buggy tool.
+func (d DN) EqualUnordered(other DN) bool {
+	if d.CountRDN() != other.CountRDN() {
+		return false
 	}
-	return false
+	used := make([]bool, other.CountRDN())
+	for _, rdn := range d {
+		found := false
+		for j := range other {
+			if !used[j] && rdn.Equal(other[j]) {
+				used[j] = true
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
 }
 
-// isPrintableStringEncoding reports whether e is PrintableString.
-func isPrintableStringEncoding(e Encoding) (ok bool) {
-	if e == PrintableString {
-		return true
+// EqualDER reports whether a and b, distinguished names in ASN.1 DER form, match.
+// If a and b are byte-for-byte identical, returns true without parsing them, even if they are not valid.
+// Otherwise, both are parsed by ParseDERDN and compared by DN.Equal,
+// so the cost is that of two ParseDERDN calls, and an error is returned if either can not be parsed.
+// Identical encodings are common when matching certificates issued by the same CA,
+// in which case EqualDER is considerably faster than parsing both and calling DN.Equal.
+func EqualDER(a, b []byte) (bool, error) {
+	if bytes.Equal(a, b) {
+		return true, nil
 	}
-	return false
+	da, err := ParseDERDN(a)
+	if err != nil {
+		return false, err
+	}
+	db, err := ParseDERDN(b)
+	if err != nil {
+		return false, err
+	}
+	return da.Equal(db), nil
 }
 
-func isValidAttributeTypeAndAttributeValueComb(at AttributeType, av AttributeValue) (isValid bool, err error) {
-	ok := true
-	p := PrintableString.String()
-	pou := PrintableString.String() + " or " + UTF8String.String()
-	pouoia5 := PrintableString.String() + " or " + UTF8String.String() + " or " + IA5String.String()
-	ia5 := IA5String.String()
-	var enlabel string
-	switch at {
-	case CountryName:
-		if !isPrintableStringEncoding(av.Encoding) {
-			enlabel = p
-			ok = false
-		}
-	case OrganizationName:
-		if !isPrintableStringOrUTF8StringEncoding(av.Encoding) {
-			enlabel = pou
-			ok = false
-		}
-	case OrganizationalUnit:
-		if !isPrintableStringOrUTF8StringEncoding(av.Encoding) {
-			enlabel = pou
-			ok = false
-		}
-	case DnQualifier:
-		if !isPrintableStringEncoding(av.Encoding) {
-			enlabel = p
-			ok = false
+// EqualsX509Subject reports whether this DN and certSubjectDER, the raw subject (or issuer) of a certificate
+// such as x509.Certificate.RawSubject, have the same DER encoding after both are canonicalized by Canonicalize.
+// Unlike DN.Equal, AttributeValues are compared byte for byte including their Encodings,
+// e.g. CommonName "a" in PrintableString does not match CommonName "a" in UTF8String.
+// Returns an error if this DN can not be marshaled or certSubjectDER can not be parsed.
+func (d DN) EqualsX509Subject(certSubjectDER []byte) (bool, error) {
+	mine, err := MarshalDN(d.Canonicalize())
+	if err != nil {
+		return false, err
+	}
+	subject, err := ParseDERDN(certSubjectDER)
+	if err != nil {
+		return false, err
+	}
+	theirs, err := MarshalDN(subject.Canonicalize())
+	if err != nil {
+		return false, err
+	}
+	return bytes.Equal(mine, theirs), nil
+}
+
+// CompareDERCanonical parses dnBytes, a distinguished name in ASN.1 DER form, by ParseDERDN, re-marshals it by MarshalDN
+// and reports whether the re-marshaled bytes equal dnBytes.
+// reMarshaled is always the re-marshaled bytes, so if canonical is false, it is the canonical form of dnBytes,
+// e.g. with AttributeTypeAndValues of a multi-valued RDN sorted in DER SET OF order.
+// Note that some BER encodings, e.g. non-minimal lengths, are rejected by ParseDERDN and returned as an error.
+// This is useful for debugging signature validation failures caused by a non-canonical encoder.
+func CompareDERCanonical(dnBytes []byte) (canonical bool, reMarshaled []byte, err error) {
+	dn, err := ParseDERDN(dnBytes)
+	if err != nil {
+		return false, nil, err
+	}
+	reMarshaled, err = MarshalDN(dn)
+	if err != nil {
+		return false, nil, err
+	}
+	return bytes.Equal(dnBytes, reMarshaled), reMarshaled, nil
+}
+
+// RoundTripSafe reports whether dnBytes, a distinguished name in ASN.1 DER form, is re-encoded byte-identically
+// by MarshalDN after it is parsed, that is, whether MarshalDN(ParseDERDN(dnBytes)) equals dnBytes.
+// dnBytes is parsed by ParseDERDNOpts with Compatible, so that inputs which would be altered by a round trip are reported as false
+// rather than as an error, e.g. a multi-valued RDN not in DER SET OF order, an AttributeValue wrapped in an extra SET,
+// or an AttributeValue in an Encoding not allowed for its AttributeType, which MarshalDN rejects.
+// A BMPString with a leading byte order mark is also reported as false, because the byte order mark is stripped
+// when parsing and is not reproduced by MarshalDN, and so is a BMPString with a surrogate pair, which MarshalDN rejects.
+// If it is false, code which is sensitive to signatures must preserve the original bytes.
+// Returns an error if dnBytes can not be parsed.
+func RoundTripSafe(dnBytes []byte) (bool, error) {
+	dn, err := ParseDERDNOpts(dnBytes, WithParseMode(Compatible))
+	if err != nil {
+		return false, err
+	}
+	reMarshaled, err := MarshalDN(dn)
+	if err != nil {
+		return false, nil
+	}
+	return bytes.Equal(dnBytes, reMarshaled), nil
+}
+
+// CanonicalizeDERDN returns dnBytes, a distinguished name in ASN.1 DER form,
+// with AttributeTypeAndValues of each RDN sorted in DER SET OF order.
+// AttributeTypes and AttributeValues are kept byte for byte, and are not validated.
+func CanonicalizeDERDN(dnBytes []byte) ([]byte, error) {
+	var idn innerDN
+	if err := idn.unmarshal(dnBytes); err != nil {
+		err := fmt.Errorf("unable to canonicalize der DN: %w", err)
+		return nil, err
+	}
+	b, err := idn.marshal()
+	if err != nil {
+		err := fmt.Errorf("unable to canonicalize der DN: %w", err)
+		return nil, err
+	}
+	return b, nil
+}
+
+// DERSubjectsEqualIgnoringSetOrder reports whether a and b, distinguished names in ASN.1 DER form,
+// are byte-for-byte identical after both are canonicalized by CanonicalizeDERDN,
+// that is, whether they differ at most in the order of AttributeTypeAndValues of multi-valued RDNs.
+func DERSubjectsEqualIgnoringSetOrder(a, b []byte) (bool, error) {
+	ca, err := CanonicalizeDERDN(a)
+	if err != nil {
+		return false, err
+	}
+	cb, err := CanonicalizeDERDN(b)
+	if err != nil {
+		return false, err
+	}
+	return bytes.Equal(ca, cb), nil
+}
+
+// RDNDiff returns the indices of RDNs which are not Equal between this DN and other, in ascending order.
+// RDNs are compared by RDN.Equal.
+// If the DNs have different numbers of RDNs, the indices of RDNs that only the longer DN has are also returned.
+func (d DN) RDNDiff(other DN) []int {
+	diff := []int{}
+	l := d.CountRDN()
+	if other.CountRDN() > l {
+		l = other.CountRDN()
+	}
+	for i := 0; i < l; i++ {
+		if i >= d.CountRDN() || i >= other.CountRDN() || !d[i].Equal(other[i]) {
+			diff = append(diff, i)
 		}
-	case StateOrProvinceName:
-		if !isPrintableStringOrUTF8StringEncoding(av.Encoding) {
-			enlabel = pou
-			ok = false
+	}
+	return diff
+}
+
+// Subtract returns the RDNs of this DN which follow base, that is, this DN relative to base,
+// e.g. "CN=Mike,OU=Sales" for this DN "CN=Mike,OU=Sales,O=example,C=JP" and base "O=example,C=JP" in RFC4514 format.
+// RDNs are compared by RDN.Equal. If base equals this DN, returns an empty DN.
+// If base is not a prefix of this DN in DN order (a suffix in RFC4514 format), returns an error.
+func (d DN) Subtract(base DN) (DN, error) {
+	if base.CountRDN() > d.CountRDN() {
+		return nil, fmt.Errorf("base has more RDNs than DN")
+	}
+	for i := range base {
+		if !d[i].Equal(base[i]) {
+			return nil, fmt.Errorf("%d th RDN of base does not match DN", i)
 		}
-	case CommonName:
-		if !isPrintableStringOrUTF8StringEncoding(av.Encoding) {
-			enlabel = pou
-			ok = false
+	}
+	return append(DN{}, d[base.CountRDN():]...), nil
+}
+
+// CommonAncestor returns the deepest common base of a and b, that is, their longest common prefix in DN order
+// (the longest common suffix in RFC4514 format), and its number of RDNs.
+// RDNs are compared by RDN.Equal. If a and b have no common base, returns an empty DN and 0.
+func CommonAncestor(a, b DN) (DN, int) {
+	n := 0
+	for n < a.CountRDN() && n < b.CountRDN() && a[n].Equal(b[n]) {
+		n++
+	}
+	return append(DN{}, a[:n]...), n
+}
+
+// MergeDNPreferring returns a DN merging the RDNs of base and overlay, e.g. for certificate profile templating
+// where base supplies defaults and overlay supplies overrides. The merge policy is per AttributeType:
+//
+//	OrganizationalUnit and DomainComponent are repeatable: RDNs of overlay are appended after the RDNs of base
+//	of the same AttributeType, except those which match an RDN already in the result by RDN.Equal.
+//	Other AttributeTypes are overridden: if overlay has RDNs of the AttributeType,
+//	they replace all RDNs of base of the AttributeType, at the position of the first one.
+//
+// A multi-valued RDN is treated as a unit whose AttributeType is the set of its AttributeTypes,
+// so it overrides only a multi-valued RDN of the same AttributeTypes.
+// RDNs of overlay of an AttributeType not in the result are inserted after the last RDN of the same or a broader level
+// in the order DC, C, ST, L, O, OU, other AttributeTypes, CN and E, so that e.g. OU is placed before CN.
+// base and overlay are not modified.
+func MergeDNPreferring(base, overlay DN) DN {
+	overrides := map[string][]RDN{}
+	for _, rdn := range overlay {
+		if k := mergeKey(rdn); !isRepeatableMergeKey(k) {
+			overrides[k] = append(overrides[k], rdn)
 		}
-	case SerialNumber:
-		if !isPrintableStringEncoding(av.Encoding) {
-			enlabel = p
-			ok = false
+	}
+
+	merged := DN{}
+	replaced := map[string]bool{}
+	for _, rdn := range base {
+		k := mergeKey(rdn)
+		ov, ok := overrides[k]
+		if !ok {
+			merged = append(merged, rdn)
+			continue
 		}
-	case LocalityName:
-		if !isPrintableStringOrUTF8StringEncoding(av.Encoding) {
-			enlabel = pou
-			ok = false
+		if !replaced[k] {
+			merged = append(merged, ov...)
+			replaced[k] = true
 		}
-	case Title:
-		if !isPrintableStringOrUTF8StringEncoding(av.Encoding) {
-			enlabel = pou
-			ok = false
+	}
+
+	for _, rdn := range overlay {
+		k := mergeKey(rdn)
+		if replaced[k] {
+			continue
 		}
-	case Surname:
-		if !isPrintableStringOrUTF8StringEncoding(av.Encoding) {
-			enlabel = pou
-			ok = false
+		if isRepeatableMergeKey(k) && merged.containsRDN(rdn) {
+			continue
 		}
-	case GivenName:
-		if !isPrintableStringOrUTF8StringEncoding(av.Encoding) {
-			enlabel = pou
-			ok = false
+		pos := 0
+		for i, m := range merged {
+			if mergeKey(m) == k || mergeRank(m) <= mergeRank(rdn) {
+				pos = i + 1
+			}
 		}
-	case Initials:
-		if !isPrintableStringOrUTF8StringEncoding(av.Encoding) {
-			enlabel = pou
-			ok = false
+		merged = append(merged[:pos], append(DN{rdn}, merged[pos:]...)...)
+	}
+	return merged
+}
+
+// mergeKey returns the key of the AttributeTypes of r for MergeDNPreferring, the sorted oids joined by "+".
+func mergeKey(r RDN) string {
+	var oids []string
+	for _, atv := range r {
+		oids = append(oids, atv.oidString())
+	}
+	sort.Strings(oids)
+	return strings.Join(oids, "+")
+}
+
+// isRepeatableMergeKey reports whether k, a key of mergeKey, is of a repeatable AttributeType for MergeDNPreferring.
+func isRepeatableMergeKey(k string) bool {
+	return k == "2.5.4.11" || k == "0.9.2342.19200300.100.1.25"
+}
+
+// mergeRank returns the level of r for MergeDNPreferring. Smaller is broader.
+func mergeRank(r RDN) int {
+	rank := flexibleJSONRank(Generic)
+	for i, atv := range r {
+		if ar := flexibleJSONRank(atv.resolvedType()); i == 0 || ar < rank {
+			rank = ar
 		}
-	case Pseudonym:
-		if !isPrintableStringOrUTF8StringEncoding(av.Encoding) {
-			enlabel = pou
-			ok = false
+	}
+	return rank
+}
+
+// containsRDN reports whether d has an RDN which matches r by RDN.Equal.
+func (d DN) containsRDN(r RDN) bool {
+	for _, rdn := range d {
+		if rdn.Equal(r) {
+			return true
 		}
-	case GenerationQualifier:
-		if !isPrintableStringOrUTF8StringEncoding(av.Encoding) {
-			enlabel = pou
-			ok = false
+	}
+	return false
+}
+
+// CanonicalString returns a deterministic string representation of this DN, which can be used as a map key.
+// The string is identical for two valid DNs if and only if they are Equal.
+// It is built from the DN normalized as described in DN.Normalize: AttributeTypes are output as dotted-decimal oids,
+// AttributeValues are escaped according to RFC 4514 regardless of their Encoding, and the DN order is kept,
+// e.g. "2.5.4.6=JP,2.5.4.3=mike+2.5.4.4=smith".
+func (d DN) CanonicalString() string {
+	var rdns []string
+	for _, rdn := range d.Normalize() {
+		var atvs []string
+		for _, atv := range rdn {
+			atvs = append(atvs, atv.oidString()+"="+escapeAttributeValue(atv.Value.Value))
 		}
-	case ElectronicMailAddress:
-		if !isIA5StringEncoding(av.Encoding) {
-			enlabel = ia5
-			ok = false
+		rdns = append(rdns, strings.Join(atvs, "+"))
+	}
+	return strings.Join(rdns, ",")
+}
+
+// DedupKey returns a short and stable key of this DN for deduplication, e.g. as a map key of a certificate store.
+// The key is the hex encoded SHA-256 hash of the DN normalized as described in DN.Normalize,
+// so two valid DNs have the same key if and only if they are Equal.
+func (d DN) DedupKey() string {
+	sum := sha256.Sum256([]byte(d.CanonicalString()))
+	return hex.EncodeToString(sum[:])
+}
+
+// CanonicalHashInput returns a normalized ASN.1 DER encoding of this DN to be used as a hash input for matching,
+// e.g. in a hash-based certificate store. The DN is normalized as described in DN.Normalize,
+// and every AttributeValue, including OIDValue, is encoded as UTF8String regardless of its Encoding,
+// so two valid DNs produce the same bytes if and only if they are Equal.
+// The result is for matching only and does not reproduce the wire bytes; use MarshalDN for that.
+// Returns nil if an AttributeType can not be encoded, e.g. Generic with an invalid Oid.
+func (d DN) CanonicalHashInput() []byte {
+	idn := innerDN{}
+	for _, rdn := range d.Normalize() {
+		irdn := innerRDNSET{}
+		for _, atv := range rdn {
+			oid, err := convertToObjectIdentifier(atv.oidString())
+			if err != nil {
+				return nil
+			}
+			v := asn1.RawValue{Class: asn1.ClassUniversal, Tag: asn1.TagUTF8String, Bytes: []byte(atv.Value.Value)}
+			irdn = append(irdn, innerAttributeTypeAndValue{Type: oid, Value: v})
 		}
-	case DomainComponent:
-		if !isIA5StringEncoding(av.Encoding) {
-			enlabel = ia5
-			ok = false
+		idn = append(idn, irdn)
+	}
+	b, err := idn.marshal()
+	if err != nil {
+		return nil
+	}
+	return b
+}
+
+// Canonicalize returns a copy of this DN in DER canonical form without a marshal round-trip.
+// Generic whose Oid is a known AttributeType oid is converted to the known AttributeType,
+// and AttributeTypeAndValues of each RDN are sorted into DER SET order, as MarshalDN does.
+// AttributeValues are kept as they are.
+// AttributeTypeAndValues which can not be encoded are placed first in their RDN.
+func (d DN) Canonicalize() DN {
+	c := DN{}
+	for _, rdn := range d {
+		c = append(c, rdn.canonicalize())
+	}
+	return c
+}
+
+// canonicalize returns a copy of r in DER canonical form. See DN.Canonicalize.
+func (r RDN) canonicalize() RDN {
+	type keyed struct {
+		atv AttributeTypeAndValue
+		key []byte
+	}
+	ks := make([]keyed, 0, len(r))
+	for _, atv := range r {
+		if at := atv.resolvedType(); at != atv.Type {
+			atv = AttributeTypeAndValue{Type: at, Value: atv.Value}
 		}
-	case Generic:
-		if !isPrintableStringOrUTF8StringOrIA5StringEncoding(av.Encoding) {
-			enlabel = pouoia5
-			ok = false
+		var key []byte
+		if iatv, err := convertToInnerAttributeTypeAndValue(atv); err == nil {
+			key, _ = asn1.Marshal(iatv)
 		}
-	default:
-		return false, fmt.Errorf("not supported AttributeType error")
+		ks = append(ks, keyed{atv, key})
 	}
-
-	if !ok {
-		return false, fmt.Errorf("%s’s value should be %s", at.String(), enlabel)
+	sort.SliceStable(ks, func(i, j int) bool {
+		return bytes.Compare(ks[i].key, ks[j].key) < 0
+	})
+	c := make(RDN, 0, len(ks))
+	for _, k := range ks {
+		c = append(c, k.atv)
 	}
-	return true, nil
+	return c
 }
 
-func isValidAttributeType(at AttributeType) (isValid bool, err error) {
-	switch at {
-	case CountryName:
-	case OrganizationName:
-	case OrganizationalUnit:
-	case DnQualifier:
-	case StateOrProvinceName:
-	case CommonName:
-	case SerialNumber:
-	case LocalityName:
-	case Title:
-	case Surname:
-	case GivenName:
-	case Initials:
-	case Pseudonym:
-	case GenerationQualifier:
-	case ElectronicMailAddress:
-	case DomainComponent:
-	case Generic:
+func isValidAttributeValueEncoding(av AttributeValue) (isValid bool, err error) {
+	switch av.Encoding {
+	case PrintableString:
+	case UTF8String:
+	case IA5String:
+	case BMPString:
+	case TeletexString:
+	case OIDValue:
+		if _, err := convertToObjectIdentifier(av.Value); err != nil {
+			return false, fmt.Errorf("OIDValue error: %w", err)
+		}
 	default:
-		return false, fmt.Errorf("not supported AttributeType error")
+		return false, fmt.Errorf("not supported string encoding error")
 	}
 	return true, nil
 }
 
-// ValidateCountryCode validates whether c is a valid country code
-func ValidateCountryCode(c string) (bool, error) {
-	_, exists := countryCodeTable[c]
-	if !exists {
-		return false, fmt.Errorf("country code validation error: %s is not valid iso 3166 alpha2 county code", c)
+// isValidAttributeTypeAndValue reports whether atv is valid.
+// If checkComb is false, the combination of AttributeType and Encoding is not checked.
+// The length of the value is not checked here. See isValidAttributeValueLength.
+func isValidAttributeTypeAndValue(atv AttributeTypeAndValue, checkComb bool) (isValid bool, err error) {
+	if isValid, err = isValidAttributeType(atv.Type); isValid != true {
+		return false, fmt.Errorf("AttributeTypeAndValue error: %w", err)
 	}
-	return true, nil
+	if isValid, err = isValidAttributeValueEncoding(atv.Value); isValid != true {
+		return false, fmt.Errorf("AttributeTypeAndValue error: %w", err)
+	}
+
+	if atv.Type == Generic {
+		var o asn1.ObjectIdentifier
+		var at AttributeType
+		if o, err = convertToObjectIdentifier(atv.Oid); err != nil {
+			return false, fmt.Errorf("AttributeTypeAndValue error: %w", err)
+		}
+
+		//Check if Oid is one of the member of AttributeType ObjectIdentifier except Generic
+		if checkComb && isDefinedOid(o) {
+			at, err = ReferAttributeTypeName(o)
+			if err != nil {
+				return false, fmt.Errorf("AttributeTypeAndValue error: %w", err)
+			}
+
+			//Oid is one of the member of AttributeTypes except Generic
+			if isValid, err = isValidAttributeTypeAndAttributeValueComb(at, atv.Value); isValid != true {
+				return false, fmt.Errorf("AttributeTypeAndValue error: %w", err)
+			}
+		}
+	}
+
+	if !checkComb {
+		return true, nil
+	}
+	if isValid, err = isValidAttributeTypeAndAttributeValueComb(atv.Type, atv.Value); isValid != true {
+		return false, fmt.Errorf("AttributeTypeAndValue error: %w", err)
+	}
+	return true, nil
+}
+
+// isValidAttributeValueLength reports whether the number of characters of av is within the bounds of at in lengthBoundTable.
+// The bounds are enforced only when constructing AttributeTypeAndValues and marshaling with WithStrictValidation,
+// because existing certificates often exceed them, e.g. OrganizationName of a long legal entity name.
+// https://datatracker.ietf.org/doc/html/rfc5280#appendix-A.1
+func isValidAttributeValueLength(at AttributeType, av AttributeValue) (isValid bool, err error) {
+	b, ok := lengthBoundTable[at]
+	if !ok {
+		return true, nil
+	}
+	l := utf8.RuneCountInString(av.Value)
+	if b.Min == b.Max && l != b.Min {
+		return false, fmt.Errorf("%s’s value should be %d characters, but %d characters", at, b.Min, l)
+	}
+	if l < b.Min {
+		return false, fmt.Errorf("%s’s value should be at least %d characters, but %d characters", at, b.Min, l)
+	}
+	if l > b.Max {
+		return false, fmt.Errorf("%s’s value should be at most %d characters, but %d characters", at, b.Max, l)
+	}
+	return true, nil
+}
+
+// isValidRDN reports whether r is valid. See isValidAttributeTypeAndValue for checkComb.
+func isValidRDN(r RDN, checkComb bool) (isValid bool, err error) {
+	isValid = false
+	if r.CountAttributeTypeAndValue() == 0 {
+		return isValid, errors.New("RDN should have at least one AttributeTypeAndValue")
+	}
+	for index, atv := range r {
+		isValid, err = isValidAttributeTypeAndValue(atv, checkComb)
+		if err != nil {
+			err := fmt.Errorf("%d th AttributeTypeAndValue element validating error: %w", index, err)
+			return isValid, err
+		}
+	}
+	return isValid, nil
+}
+
+func isValidDN(d DN) (isValid bool, err error) {
+	return validateDN(d, true)
+}
+
+// isValidDNIgnoringEncoding is like isValidDN but does not check combinations of AttributeType and Encoding.
+func isValidDNIgnoringEncoding(d DN) (isValid bool, err error) {
+	return validateDN(d, false)
+}
+
+// validateDN reports whether d is valid. See isValidAttributeTypeAndValue for checkComb.
+func validateDN(d DN, checkComb bool) (isValid bool, err error) {
+	isValid = false
+	if d.CountRDN() == 0 {
+		isValid = true
+	}
+	for index, rdn := range d {
+		isValid, err = isValidRDN(rdn, checkComb)
+		if err != nil {
+			err := fmt.Errorf("%d th RDN element validating error: %w", index, err)
+			return isValid, err
+		}
+	}
+	return isValid, nil
+}
+
+// Validate returns error if this DN is invalid, that is, if MarshalDN would fail to marshal it.
+// The error describes the first invalid RDN. See RDN.Validate.
+func (d DN) Validate() error {
+	for index, rdn := range d {
+		if err := rdn.Validate(); err != nil {
+			return fmt.Errorf("%d th RDN element validating error: %w", index, err)
+		}
+	}
+	return nil
+}
+
+// Validate returns error if this RDN has no AttributeTypeAndValue or has an invalid AttributeTypeAndValue.
+// See AttributeTypeAndValue.Validate.
+func (r RDN) Validate() error {
+	if r.CountAttributeTypeAndValue() == 0 {
+		return errors.New("RDN should have at least one AttributeTypeAndValue")
+	}
+	for index, atv := range r {
+		if err := atv.Validate(); err != nil {
+			return fmt.Errorf("%d th AttributeTypeAndValue element validating error: %w", index, err)
+		}
+	}
+	return nil
+}
+
+// Validate returns error if this AttributeTypeAndValue is invalid, that is,
+// if the AttributeType or the Encoding is not supported, Oid of Generic is not a valid object identifier,
+// the combination of AttributeType and Encoding is not supported (see AttributeTypeAndValue),
+// or the Value can not be encoded in the Encoding, e.g. "@" in PrintableString.
+func (atv AttributeTypeAndValue) Validate() error {
+	if _, err := isValidAttributeTypeAndValue(atv, true); err != nil {
+		return err
+	}
+	if _, err := newRawValue(atv.Value.Encoding, atv.Value.Value); err != nil {
+		return fmt.Errorf("AttributeTypeAndValue error: %w", err)
+	}
+	return nil
+}
+
+// isPrintableStringOrUTF8StringOrIA5StringEncoding reports whether e is PrintableString or UTF8String or IA5String.
+func isPrintableStringOrUTF8StringOrIA5StringEncoding(e Encoding) (ok bool) {
+	switch e {
+	case PrintableString:
+		return true
+	case UTF8String:
+		return true
+	case IA5String:
+		return true
+	default:
+		return false
+	}
+}
+
+// isDirectoryStringEncoding reports whether e is PrintableString or UTF8String or BMPString or TeletexString,
+// the supported choices of DirectoryString.
+func isDirectoryStringEncoding(e Encoding) (ok bool) {
+	switch e {
+	case PrintableString:
+		return true
+	case UTF8String:
+		return true
+	case BMPString:
+		return true
+	case TeletexString:
+		return true
+	default:
+		return false
+	}
+}
+
+// isGenericEncoding reports whether e is allowed for Generic, that is,
+// PrintableString or UTF8String or IA5String or OIDValue or BMPString or TeletexString.
+func isGenericEncoding(e Encoding) (ok bool) {
+	return isPrintableStringOrUTF8StringOrIA5StringEncoding(e) || e == OIDValue || e == BMPString || e == TeletexString
+}
+
+// isIA5StringOrUTF8StringEncoding reports whether e is IA5String or UTF8String.
+func isIA5StringOrUTF8StringEncoding(e Encoding) (ok bool) {
+	switch e {
+	case IA5String:
+		return true
+	case UTF8String:
+		return true
+	default:
+		return false
+	}
+}
+
+// isIA5StringEncoding reports whether e is IA5String.
+func isIA5StringEncoding(e Encoding) (ok bool) {
+	if e == IA5String {
+		return true
+	}
+	return false
+}
+
+// isPrintableStringEncoding reports whether e is PrintableString.
+func isPrintableStringEncoding(e Encoding) (ok bool) {
+	if e == PrintableString {
+		return true
+	}
+	return false
+}
+
+func isValidAttributeTypeAndAttributeValueComb(at AttributeType, av AttributeValue) (isValid bool, err error) {
+	ok := true
+	p := PrintableString.String()
+	pouobmpot := PrintableString.String() + " or " + UTF8String.String() + " or " + BMPString.String() + " or " + TeletexString.String()
+	pouoia5ooidobmpot := PrintableString.String() + " or " + UTF8String.String() + " or " + IA5String.String() + " or " + OIDValue.String() + " or " + BMPString.String() + " or " + TeletexString.String()
+	ia5 := IA5String.String()
+	ia5ou := IA5String.String() + " or " + UTF8String.String()
+	ia5opouobmpot := IA5String.String() + " or " + pouobmpot
+	var enlabel string
+	switch at {
+	case CountryName:
+		if !isPrintableStringEncoding(av.Encoding) {
+			enlabel = p
+			ok = false
+		}
+	case OrganizationName:
+		if !isDirectoryStringEncoding(av.Encoding) {
+			enlabel = pouobmpot
+			ok = false
+		}
+	case OrganizationalUnit:
+		if !isDirectoryStringEncoding(av.Encoding) {
+			enlabel = pouobmpot
+			ok = false
+		}
+	case DnQualifier:
+		if !isPrintableStringEncoding(av.Encoding) {
+			enlabel = p
+			ok = false
+		}
+	case StateOrProvinceName:
+		if !isDirectoryStringEncoding(av.Encoding) {
+			enlabel = pouobmpot
+			ok = false
+		}
+	case CommonName:
+		if !isDirectoryStringEncoding(av.Encoding) {
+			enlabel = pouobmpot
+			ok = false
+		}
+	case SerialNumber:
+		if !isPrintableStringEncoding(av.Encoding) {
+			enlabel = p
+			ok = false
+		}
+	case LocalityName:
+		if !isDirectoryStringEncoding(av.Encoding) {
+			enlabel = pouobmpot
+			ok = false
+		}
+	case Title:
+		if !isDirectoryStringEncoding(av.Encoding) {
+			enlabel = pouobmpot
+			ok = false
+		}
+	case Surname:
+		if !isDirectoryStringEncoding(av.Encoding) {
+			enlabel = pouobmpot
+			ok = false
+		}
+	case GivenName:
+		if !isDirectoryStringEncoding(av.Encoding) {
+			enlabel = pouobmpot
+			ok = false
+		}
+	case Initials:
+		if !isDirectoryStringEncoding(av.Encoding) {
+			enlabel = pouobmpot
+			ok = false
+		}
+	case Pseudonym:
+		if !isDirectoryStringEncoding(av.Encoding) {
+			enlabel = pouobmpot
+			ok = false
+		}
+	case GenerationQualifier:
+		if !isDirectoryStringEncoding(av.Encoding) {
+			enlabel = pouobmpot
+			ok = false
+		}
+	case ElectronicMailAddress:
+		if !isIA5StringEncoding(av.Encoding) {
+			enlabel = ia5
+			ok = false
+		}
+	case DomainComponent:
+		if !isIA5StringEncoding(av.Encoding) {
+			enlabel = ia5
+			ok = false
+		}
+	case UnstructuredName:
+		if !isIA5StringOrUTF8StringEncoding(av.Encoding) {
+			enlabel = ia5ou
+			ok = false
+		}
+	case UnstructuredAddress:
+		if !isDirectoryStringEncoding(av.Encoding) {
+			enlabel = pouobmpot
+			ok = false
+		}
+	case StreetAddress:
+		if !isDirectoryStringEncoding(av.Encoding) {
+			enlabel = pouobmpot
+			ok = false
+		}
+	case PostalCode:
+		if !isDirectoryStringEncoding(av.Encoding) {
+			enlabel = pouobmpot
+			ok = false
+		}
+	case UserID:
+		if !isIA5StringEncoding(av.Encoding) && !isDirectoryStringEncoding(av.Encoding) {
+			enlabel = ia5opouobmpot
+			ok = false
+		}
+	case Generic:
+		if !isGenericEncoding(av.Encoding) {
+			enlabel = pouoia5ooidobmpot
+			ok = false
+		}
+	default:
+		if _, registered := registeredOid(at); !registered {
+			return false, fmt.Errorf("not supported AttributeType error")
+		}
+		//The syntax of registered AttributeTypes is unknown, so they are treated as Generic.
+		if !isGenericEncoding(av.Encoding) {
+			enlabel = pouoia5ooidobmpot
+			ok = false
+		}
+	}
+
+	if !ok {
+		return false, fmt.Errorf("%s’s value should be %s", at.String(), enlabel)
+	}
+	return true, nil
+}
+
+// IsDirectoryStringType reports whether at is a DirectoryString-typed AttributeType,
+// whose AttributeValue can be PrintableString or UTF8String, e.g. CommonName.
+// Generic and AttributeTypes registered by RegisterAttributeType are not DirectoryString-typed, because their syntax is unknown.
+func IsDirectoryStringType(at AttributeType) bool {
+	if _, registered := registeredOid(at); registered {
+		return false
+	}
+	return at != Generic && isAllowedEncoding(at, PrintableString) && isAllowedEncoding(at, UTF8String)
+}
+
+// IsPrintableOnlyType reports whether at is an AttributeType whose AttributeValue can be only PrintableString,
+// e.g. CountryName.
+func IsPrintableOnlyType(at AttributeType) bool {
+	return isAllowedEncoding(at, PrintableString) && !isAllowedEncoding(at, UTF8String)
+}
+
+// IsIA5Type reports whether at is an AttributeType whose AttributeValue is IA5String, e.g. ElectronicMailAddress.
+// UnstructuredName and UserID are IA5String-typed as well, because IA5String is one of their Encodings,
+// although they also allow UTF8String.
+// Generic and AttributeTypes registered by RegisterAttributeType are not IA5String-typed, because their syntax is unknown.
+func IsIA5Type(at AttributeType) bool {
+	if _, registered := registeredOid(at); registered {
+		return false
+	}
+	return at != Generic && isAllowedEncoding(at, IA5String)
+}
+
+// isAllowedEncoding reports whether e is allowed for at by isValidAttributeTypeAndAttributeValueComb.
+func isAllowedEncoding(at AttributeType, e Encoding) bool {
+	ok, _ := isValidAttributeTypeAndAttributeValueComb(at, AttributeValue{Encoding: e})
+	return ok
+}
+
+func isValidAttributeType(at AttributeType) (isValid bool, err error) {
+	switch at {
+	case CountryName:
+	case OrganizationName:
+	case OrganizationalUnit:
+	case DnQualifier:
+	case StateOrProvinceName:
+	case CommonName:
+	case SerialNumber:
+	case LocalityName:
+	case Title:
+	case Surname:
+	case GivenName:
+	case Initials:
+	case Pseudonym:
+	case GenerationQualifier:
+	case ElectronicMailAddress:
+	case DomainComponent:
+	case UnstructuredName:
+	case UnstructuredAddress:
+	case StreetAddress:
+	case PostalCode:
+	case UserID:
+	case Generic:
+	default:
+		if _, ok := registeredOid(at); ok {
+			return true, nil
+		}
+		return false, fmt.Errorf("not supported AttributeType error")
+	}
+	return true, nil
+}
+
+// ValidateCountryCode validates whether c is a valid country code
+func ValidateCountryCode(c string) (bool, error) {
+	_, exists := countryCodeTable[c]
+	if !exists {
+		return false, fmt.Errorf("country code validation error: %s is not valid iso 3166 alpha2 county code", c)
+	}
+	return true, nil
+}
+
+// LintResult represents an issue found in a DN which does not make the DN invalid.
+type LintResult struct {
+	//RDNIndex is the index of the RDN which has the issue.
+	RDNIndex int
+	//AttributeTypeAndValueIndex is the index of the AttributeTypeAndValue in the RDN which has the issue.
+	//If the issue is about the whole RDN, AttributeTypeAndValueIndex is -1.
+	AttributeTypeAndValueIndex int
+	//Rule is the name of the rule which found the issue.
+	Rule string
+	//Message describes the issue.
+	Message string
+}
+
+// Lint rule names
+const (
+	LintInvalidCountryCode   = "invalid_country_code"
+	LintEmptyValue           = "empty_value"
+	LintSurroundingSpaces    = "surrounding_spaces"
+	LintGenericKnownOid      = "generic_known_oid"
+	LintNonCanonicalSetOrder = "non_canonical_set_order"
+	LintMixedScript          = "mixed_script"
+	LintMismatchedEncoding   = "mismatched_encoding"
+)
+
+// String returns a string representation of this LintResult.
+func (l LintResult) String() string {
+	if l.AttributeTypeAndValueIndex < 0 {
+		return fmt.Sprintf("%d th RDN: %s: %s", l.RDNIndex, l.Rule, l.Message)
+	}
+	return fmt.Sprintf("%d th RDN %d th AttributeTypeAndValue: %s: %s", l.RDNIndex, l.AttributeTypeAndValueIndex, l.Rule, l.Message)
+}
+
+// Lint returns issues found in this DN which do not make the DN invalid.
+// The following rules are checked:
+//
+//	invalid_country_code : CountryName is not an ISO 3166 alpha-2 code
+//	empty_value : AttributeValue is empty
+//	surrounding_spaces : AttributeValue has leading or trailing spaces
+//	generic_known_oid : Generic is used with a known AttributeType oid
+//	mixed_script : AttributeValue mixes letters of confusable scripts (see FindConfusables)
+//	mismatched_encoding : Encoding of AttributeValue is not allowed for AttributeType, e.g. CountryName in UTF8String
+//
+// A DN with mismatched_encoding is invalid, but can be returned by ParseDERDNWithMode in Compatible mode and ParseDERDNWithReport.
+// MarshalDN returns an error for such a DN until the Encoding of the reported AttributeValue is corrected.
+func (d DN) Lint() (results []LintResult) {
+	results = []LintResult{}
+	for i, rdn := range d {
+		for j, atv := range rdn {
+			results = append(results, lintAttributeTypeAndValue(i, j, atv)...)
+		}
+	}
+	return results
+}
+
+func lintAttributeTypeAndValue(i int, j int, atv AttributeTypeAndValue) (results []LintResult) {
+	v := atv.Value.Value
+	if atv.resolvedType() == CountryName {
+		if _, err := ValidateCountryCode(v); err != nil {
+			results = append(results, LintResult{i, j, LintInvalidCountryCode, fmt.Sprintf("%s is not ISO 3166 alpha-2 code", v)})
+		}
+	}
+	if v == "" {
+		results = append(results, LintResult{i, j, LintEmptyValue, "AttributeValue is empty"})
+	} else if strings.TrimSpace(v) != v {
+		results = append(results, LintResult{i, j, LintSurroundingSpaces, "AttributeValue has leading or trailing spaces"})
+	}
+	if atv.Type == Generic && atv.resolvedType() != Generic {
+		results = append(results, LintResult{i, j, LintGenericKnownOid, fmt.Sprintf("%s should be used instead of Generic", atv.resolvedType().String())})
+	}
+	if at := atv.resolvedType(); at != Generic && !isAllowedEncoding(at, atv.Value.Encoding) {
+		results = append(results, LintResult{i, j, LintMismatchedEncoding, fmt.Sprintf("%s is not allowed for %s", atv.Value.Encoding.String(), at.String())})
+	}
+	if scripts := confusableScripts(v); len(scripts) > 1 {
+		results = append(results, LintResult{i, j, LintMixedScript, fmt.Sprintf("AttributeValue mixes %s characters", strings.Join(scripts, " and "))})
+	}
+	return results
+}
+
+// FindConfusables returns AttributeValues of this DN which may be spoofed with homoglyphs,
+// e.g. a CommonName "pаypal.com" whose "а" is Cyrillic.
+// The rule of the results is mixed_script.
+//
+// The heuristic is script mixing: an AttributeValue is reported if it contains letters of
+// two or more of Latin, Greek and Cyrillic scripts, whose letters are often indistinguishable.
+// Digits, punctuation and letters of other scripts are ignored.
+// Values written entirely in one confusable script (e.g. all Cyrillic letters looking like Latin)
+// are not detected, and legitimate values mixing those scripts are reported.
+func (d DN) FindConfusables() (results []LintResult) {
+	results = []LintResult{}
+	for _, l := range d.Lint() {
+		if l.Rule == LintMixedScript {
+			results = append(results, l)
+		}
+	}
+	return results
+}
+
+// confusableScripts returns names of confusable scripts whose letters appear in v, in order of appearance.
+func confusableScripts(v string) (scripts []string) {
+	seen := make(map[string]bool)
+	for _, r := range v {
+		if !unicode.IsLetter(r) {
+			continue
+		}
+		var name string
+		switch {
+		case unicode.Is(unicode.Latin, r):
+			name = "Latin"
+		case unicode.Is(unicode.Greek, r):
+			name = "Greek"
+		case unicode.Is(unicode.Cyrillic, r):
+			name = "Cyrillic"
+		default:
+			continue
+		}
+		if !seen[name] {
+			seen[name] = true
+			scripts = append(scripts, name)
+		}
+	}
+	return scripts
+}
+
+// ParseDERDNWithReport parses a distinguished name, ASN.1 DER form and returns DN,
+// and also returns issues found in the distinguished name which do not make parsing fail.
+// The distinguished name is parsed in the same way as ParseDERDNWithMode in Compatible mode,
+// so that a non-recommended encoding, e.g. CountryName in UTF8String, is reported as mismatched_encoding
+// instead of making parsing fail. Note that MarshalDN returns an error for such a DN.
+// In addition to the rules of DN.Lint, the following rule is checked:
+//
+//	non_canonical_set_order : AttributeTypeAndValues of the RDN are not sorted in DER order
+func ParseDERDNWithReport(dnBytes []byte) (dn DN, results []LintResult, err error) {
+	dn, err = ParseDERDNWithMode(dnBytes, Compatible)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var idn innerDN
+	if err = idn.unmarshal(dnBytes); err != nil {
+		err := fmt.Errorf("unable to parse der DN: %w", err)
+		return nil, nil, err
+	}
+	results = []LintResult{}
+	for i, irdn := range idn {
+		if !irdn.isDEROrder() {
+			results = append(results, LintResult{i, -1, LintNonCanonicalSetOrder, "AttributeTypeAndValues are not sorted in DER order"})
+		}
+	}
+	results = append(results, dn.Lint()...)
+	return dn, results, nil
+}
+
+// isDEROrder reports whether elements of ir are sorted in DER SET OF order.
+func (ir innerRDNSET) isDEROrder() bool {
+	var prev []byte
+	for _, iatv := range ir {
+		b, err := asn1.Marshal(iatv)
+		if err != nil {
+			return false
+		}
+		if prev != nil && bytes.Compare(prev, b) > 0 {
+			return false
+		}
+		prev = b
+	}
+	return true
+}
+
+// Profile represents a set of rules a DN must satisfy, e.g. a certificate profile of a CA.
+// A zero value of each field means no restriction.
+type Profile struct {
+	// Name is used in error messages.
+	Name string
+	// Allowed lists the only AttributeTypes which may appear in the DN, if it is not nil.
+	// AttributeTypes in Forbidden are reported as forbidden only.
+	Allowed []AttributeType
+	// Required lists AttributeTypes which must appear in the DN.
+	Required []AttributeType
+	// Forbidden lists AttributeTypes which must not appear in the DN.
+	Forbidden []AttributeType
+	// Singleton lists AttributeTypes which must not appear more than once in the DN.
+	Singleton []AttributeType
+	// AllowedEncodings restricts Encodings of AttributeValues of each AttributeType.
+	AllowedEncodings map[AttributeType][]Encoding
+	// LengthBounds restricts the number of characters of AttributeValues of each AttributeType.
+	LengthBounds map[AttributeType]LengthBound
+}
+
+// LengthBound represents the bounds of the number of characters of an AttributeValue.
+// If Max is 0, there is no upper bound.
+type LengthBound struct {
+	Min int
+	Max int
+}
+
+// TLSServerProfile returns a basic Profile for the subject of TLS server certificates.
+// It is based on the CA/Browser Forum Baseline Requirements and the upper bounds of RFC 5280.
+//
+// https://cabforum.org/baseline-requirements-documents/
+// https://datatracker.ietf.org/doc/html/rfc5280#appendix-A.1
+func TLSServerProfile() Profile {
+	return Profile{
+		Name:      "TLS server",
+		Allowed:   []AttributeType{CountryName, StateOrProvinceName, LocalityName, StreetAddress, PostalCode, OrganizationName, CommonName, GivenName, Surname},
+		Forbidden: []AttributeType{OrganizationalUnit},
+		Singleton: []AttributeType{CountryName, StateOrProvinceName, LocalityName, OrganizationName, CommonName},
+		AllowedEncodings: map[AttributeType][]Encoding{
+			CountryName: {PrintableString},
+		},
+		LengthBounds: map[AttributeType]LengthBound{
+			CountryName:         {2, 2},
+			StateOrProvinceName: {1, 128},
+			LocalityName:        {1, 128},
+			OrganizationName:    {1, 64},
+			CommonName:          {1, 64},
+		},
+	}
+}
+
+// EVProfile returns a Profile for the subject of Extended Validation TLS server certificates.
+// It is a stub which only covers the AttributeTypes this package supports,
+// so that callers can extend it with their own rules.
+// Generic is allowed for the EV specific AttributeTypes, e.g. businessCategory and jurisdictionCountryName.
+//
+// https://cabforum.org/extended-validation/
+func EVProfile() Profile {
+	p := TLSServerProfile()
+	p.Name = "EV"
+	p.Allowed = append(p.Allowed, SerialNumber, Generic)
+	p.Required = []AttributeType{CountryName, OrganizationName, SerialNumber}
+	p.Singleton = append(p.Singleton, SerialNumber)
+	p.LengthBounds[SerialNumber] = LengthBound{1, 64}
+	return p
+}
+
+// ValidateProfile validates this DN against the rules of p and returns all violations.
+// If the DN satisfies p, returns an empty slice.
+// Generic whose Oid is a known AttributeType oid is treated as the known AttributeType.
+func (d DN) ValidateProfile(p Profile) []error {
+	errs := []error{}
+	counts := make(map[AttributeType]int)
+	for i, rdn := range d {
+		for j, atv := range rdn {
+			at := atv.resolvedType()
+			counts[at]++
+			if p.Allowed != nil && !containsAttributeType(p.Allowed, at) && !containsAttributeType(p.Forbidden, at) {
+				errs = append(errs, fmt.Errorf("profile %s: %d th RDN %d th AttributeTypeAndValue: %s is not allowed", p.Name, i, j, atv.toShortName()))
+			}
+			if encs, ok := p.AllowedEncodings[at]; ok && !containsEncoding(encs, atv.Value.Encoding) {
+				errs = append(errs, fmt.Errorf("profile %s: %d th RDN %d th AttributeTypeAndValue: %s is not allowed for %s", p.Name, i, j, atv.Value.Encoding, at))
+			}
+			if b, ok := p.LengthBounds[at]; ok {
+				l := utf8.RuneCountInString(atv.Value.Value)
+				if l < b.Min || (b.Max > 0 && l > b.Max) {
+					errs = append(errs, fmt.Errorf("profile %s: %d th RDN %d th AttributeTypeAndValue: length %d of %s is out of bounds", p.Name, i, j, l, at))
+				}
+			}
+		}
+	}
+	for _, at := range p.Required {
+		if counts[at] == 0 {
+			errs = append(errs, fmt.Errorf("profile %s: %s is required", p.Name, at))
+		}
+	}
+	for _, at := range p.Forbidden {
+		if counts[at] != 0 {
+			errs = append(errs, fmt.Errorf("profile %s: %s is forbidden", p.Name, at))
+		}
+	}
+	for _, at := range p.Singleton {
+		if counts[at] > 1 {
+			errs = append(errs, fmt.Errorf("profile %s: %s appears %d times", p.Name, at, counts[at]))
+		}
+	}
+	return errs
+}
+
+// ValidateTLSServerSubject validates dn as the subject of a TLS server certificate against TLSServerProfile,
+// which encodes the subject rules of the CA/Browser Forum Baseline Requirements, and returns all violations:
+//
+//	only CountryName, StateOrProvinceName, LocalityName, StreetAddress, PostalCode, OrganizationName, CommonName,
+//	GivenName and Surname are allowed,
+//	and OrganizationalUnit is forbidden
+//	CountryName, StateOrProvinceName, LocalityName, OrganizationName and CommonName appear at most once
+//	CountryName is a PrintableString of 2 characters
+//	the lengths of the other values are within the upper bounds of RFC 5280
+//
+// CountryName and OrganizationName are optional. CommonName is also optional, because the names of the server
+// may be carried only in the subjectAltName extension, which is not part of the DN and is not validated.
+// If dn conforms, returns an empty slice.
+func ValidateTLSServerSubject(dn DN) []error {
+	return dn.ValidateProfile(TLSServerProfile())
+}
+
+// CheckEncodingWhitelist returns errors for all AttributeTypeAndValues of this DN whose Encoding is not in allowed for the AttributeType,
+// e.g. allowed of {CommonName: {UTF8String}} reports a CommonName in PrintableString.
+// AttributeTypes which are not keys of allowed are not checked, as in Profile.AllowedEncodings.
+// Generic whose Oid is a known AttributeType oid is treated as the known AttributeType.
+// If there is no violation, returns an empty slice.
+func (d DN) CheckEncodingWhitelist(allowed map[AttributeType][]Encoding) []error {
+	errs := []error{}
+	for i, rdn := range d {
+		for j, atv := range rdn {
+			at := atv.resolvedType()
+			if encs, ok := allowed[at]; ok && !containsEncoding(encs, atv.Value.Encoding) {
+				errs = append(errs, fmt.Errorf("%d th RDN %d th AttributeTypeAndValue: %s is not allowed for %s", i, j, atv.Value.Encoding, at))
+			}
+		}
+	}
+	return errs
+}
+
+// containsAttributeType reports whether ats contains at.
+func containsAttributeType(ats []AttributeType, at AttributeType) bool {
+	for _, a := range ats {
+		if a == at {
+			return true
+		}
+	}
+	return false
+}
+
+func containsEncoding(encs []Encoding, e Encoding) bool {
+	for _, enc := range encs {
+		if enc == e {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseRFC4514DN parses a string representation of a distinguished name in RFC 4514 format, as output by
+// DN.ToRFC4514FormatString, and returns DN, e.g. `CN=James \"Jim\" Smith\, III,OU=Sales+OU=Dev,O=example,C=JP`.
+// The first RDN of the string is the last RDN of the DN, and AttributeTypeAndValues of a multi-valued RDN are separated by "+".
+// AttributeTypes are short names (descriptors), e.g. "CN", "OU", "DC" and "email", which are case insensitive,
+// or dotted-decimal oids. An oid which is not a known AttributeType oid is parsed as Generic,
+// and an unknown short name is an error.
+// Values are unescaped according to RFC 4514, including "\XX" hex pairs,
+// and a value starting with "#" is the hex encoded BER form of the AttributeValue.
+// See ParseRFC1779DN for the Encodings of the other values.
+// The DN is validated in the same way as MarshalDN.
+//
+// https://www.rfc-editor.org/rfc/rfc4514#section-3
+func ParseRFC4514DN(s string) (dn DN, err error) {
+	dn, err = parseDNString(s, rfc4514Syntax)
+	if err != nil {
+		err := fmt.Errorf("unable to parse RFC4514 DN: %w", err)
+		return nil, err
+	}
+	return dn, nil
+}
+
+// RFC4514ParseOptions represents options of ParseRFC4514DNWithOptions to accept strings which do not strictly conform to RFC4514.
+// The zero value parses strictly, in the same way as ParseRFC4514DN.
+type RFC4514ParseOptions struct {
+	//SemicolonSeparator accepts unescaped ";" as an alternative RDN separator to ",", as allowed by RFC 1779 and RFC 2253,
+	//e.g. "CN=Mike;C=JP" is parsed as "CN=Mike,C=JP".
+	SemicolonSeparator bool
+	//LineContinuation joins lines continued by a backslash immediately before a newline ("\n" or "\r\n") before parsing,
+	//as in human-edited config files, e.g. "CN=Mike,\\\nC=JP" is parsed as "CN=Mike,C=JP".
+	//An escaped backslash before a newline does not continue the line.
+	LineContinuation bool
+	//TrailingSeparator tolerates an RDN separator at the end of the string, that is, an empty trailing RDN denoting the root,
+	//e.g. "CN=foo," is parsed as "CN=foo".
+	TrailingSeparator bool
+}
+
+// parseOptions returns the dnStringParseOptions corresponding to o.
+func (o RFC4514ParseOptions) parseOptions() dnStringParseOptions {
+	return dnStringParseOptions{
+		semicolonSeparator: o.SemicolonSeparator,
+		lineContinuation:   o.LineContinuation,
+		trailingSeparator:  o.TrailingSeparator,
+	}
+}
+
+// ParseRFC4514DNWithOptions is like ParseRFC4514DN but parses s according to o.
+func ParseRFC4514DNWithOptions(s string, o RFC4514ParseOptions) (dn DN, err error) {
+	dn, err = parseDNStringWithOptions(s, rfc4514Syntax, o.parseOptions())
+	if err != nil {
+		err := fmt.Errorf("unable to parse RFC4514 DN: %w", err)
+		return nil, err
+	}
+	return dn, nil
+}
+
+// ParseRFC1779DN parses a string representation of a distinguished name in RFC 1779 format and returns DN.
+// RFC 1779 format is still emitted by some legacy tools, e.g. `CN=Mike, O="Example, Inc.", OID.2.5.4.6=JP`.
+// The following differences from RFC 4514 are handled:
+//
+//	Object identifiers may be prefixed with "OID." or "oid.", e.g. "OID.2.5.4.3=Mike".
+//	Values may be enclosed in double quotes, and then special characters need not be escaped.
+//	RDNs may be separated by ";" as well as ",".
+//	Spaces around "=", ",", ";" and "+" are ignored.
+//
+// As in RFC 4514 format, the first RDN of the string is the last RDN of the DN,
+// and a value starting with "#" is the hex encoded BER form of the AttributeValue.
+// The Encoding of the other values is chosen from the AttributeType:
+// PrintableString for CountryName, DnQualifier and SerialNumber, IA5String for ElectronicMailAddress and DomainComponent,
+// and UTF8String for the others.
+// The DN is validated in the same way as MarshalDN.
+//
+// https://www.rfc-editor.org/rfc/rfc1779#section-2.3
+func ParseRFC1779DN(s string) (dn DN, err error) {
+	dn, err = parseDNString(s, rfc1779Syntax)
+	if err != nil {
+		err := fmt.Errorf("unable to parse RFC1779 DN: %w", err)
+		return nil, err
+	}
+	return dn, nil
+}
+
+// ParseOpenSSLOnelineDN parses a string representation of a distinguished name in OpenSSL oneline format,
+// as output by DN.ToOpenSSLOnelineString and "openssl x509 -subject", and returns DN, e.g. "/C=JP/O=Example/CN=Mike".
+// Unlike RFC 4514 format, each RDN is preceded by "/" and the first RDN of the string is the first RDN of the DN.
+// AttributeTypeAndValues of a multi-valued RDN are separated by "+".
+// AttributeTypes are short names (descriptors), which are case insensitive, or dotted-decimal oids, as in ParseRFC4514DN.
+// "\" escapes the following character, e.g. "\/" for "/" in a value. The other characters, including "," and "#", are literal.
+// See ParseRFC1779DN for the Encodings of parsed values.
+// The DN is validated in the same way as MarshalDN. A blank string is parsed as an empty DN.
+func ParseOpenSSLOnelineDN(s string) (dn DN, err error) {
+	dn, err = parseDNString(s, opensslOnelineSyntax)
+	if err != nil {
+		err := fmt.Errorf("unable to parse OpenSSL oneline DN: %w", err)
+		return nil, err
+	}
+	return dn, nil
+}
+
+// ParseLDIFDN parses the "dn:" line of an LDIF file and returns DN.
+// Both a plain "dn: " line and a base64 encoded "dn:: " line are accepted, and a trailing line separator is ignored.
+// The DN is parsed as RFC 4514 format. See ParseRFC1779DN for the Encodings of parsed values.
+//
+// https://www.rfc-editor.org/rfc/rfc2849
+func ParseLDIFDN(line string) (dn DN, err error) {
+	line = strings.TrimRight(line, "\r\n")
+	if len(line) < 3 || !strings.EqualFold(line[:3], "dn:") {
+		return nil, errors.New("unable to parse LDIF DN: \"dn:\" is expected")
+	}
+	s := line[3:]
+	if strings.HasPrefix(s, ":") {
+		b, err := base64.StdEncoding.DecodeString(strings.TrimLeft(s[1:], " "))
+		if err != nil {
+			err := fmt.Errorf("unable to parse LDIF DN: %w", err)
+			return nil, err
+		}
+		s = string(b)
+	} else {
+		s = strings.TrimLeft(s, " ")
+	}
+
+	dn, err = parseDNString(s, rfc4514Syntax)
+	if err != nil {
+		err := fmt.Errorf("unable to parse LDIF DN: %w", err)
+		return nil, err
+	}
+	return dn, nil
+}
+
+// defaultEncoding returns the Encoding used for a value of at parsed from a string representation.
+func defaultEncoding(at AttributeType) Encoding {
+	switch at {
+	case CountryName, DnQualifier, SerialNumber:
+		return PrintableString
+	case ElectronicMailAddress, DomainComponent:
+		return IA5String
+	default:
+		return UTF8String
+	}
+}
+
+// dnStringSyntax represents a syntax of string representations of distinguished names.
+type dnStringSyntax int
+
+const (
+	rfc4514Syntax dnStringSyntax = iota
+	rfc1779Syntax
+	//opensslOnelineSyntax is the format of X509_NAME_oneline of OpenSSL, e.g. "/C=JP/O=Example/CN=Mike".
+	opensslOnelineSyntax
+)
+
+// dnStringParser parses a string representation of a distinguished name.
+// RFC 4514 and RFC 1779 syntaxes are parsed by the same parser, and their differences are switched by syntax.
+type dnStringParser struct {
+	s      string
+	pos    int
+	syntax dnStringSyntax
+	opts   dnStringParseOptions
+}
+
+// dnStringParseOptions represents options of dnStringParser which are not determined by the syntax.
+type dnStringParseOptions struct {
+	//semicolonSeparator makes unescaped ";" an alternative RDN separator to ",", as allowed by RFC 1779 and RFC 2253.
+	//RFC 4514 does not allow it.
+	semicolonSeparator bool
+	//lineContinuation joins lines continued by a backslash immediately before a newline ("\n" or "\r\n"),
+	//removing the backslash and the newline before parsing, as in human-edited config files.
+	//An escaped backslash before a newline does not continue the line.
+	lineContinuation bool
+	//trailingSeparator tolerates an RDN separator at the end of the string, that is, an empty trailing RDN denoting the root,
+	//e.g. "CN=foo," is parsed as "CN=foo".
+	trailingSeparator bool
+}
+
+// defaultDNStringParseOptions returns the options conforming to syntax.
+func defaultDNStringParseOptions(syntax dnStringSyntax) dnStringParseOptions {
+	return dnStringParseOptions{semicolonSeparator: syntax == rfc1779Syntax}
+}
+
+// parseDNString parses s in syntax with the default options of syntax
+// and returns the DN in DN order, that is, the reverse of the string order except for opensslOnelineSyntax.
+func parseDNString(s string, syntax dnStringSyntax) (DN, error) {
+	return parseDNStringWithOptions(s, syntax, defaultDNStringParseOptions(syntax))
+}
+
+// parseDNStringWithOptions is like parseDNString but parses s with opts.
+func parseDNStringWithOptions(s string, syntax dnStringSyntax, opts dnStringParseOptions) (DN, error) {
+	if opts.lineContinuation {
+		s = joinContinuedLines(s)
+	}
+	p := &dnStringParser{s: s, syntax: syntax, opts: opts}
+	return p.parse()
+}
+
+// joinContinuedLines removes every backslash immediately followed by a newline ("\n" or "\r\n") together with the newline.
+// Other backslash escaped characters, including an escaped backslash, are kept as they are.
+func joinContinuedLines(s string) string {
+	var sb strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\\' || i+1 >= len(s) {
+			sb.WriteByte(s[i])
+			continue
+		}
+		switch {
+		case s[i+1] == '\n':
+			i++
+		case s[i+1] == '\r' && i+2 < len(s) && s[i+2] == '\n':
+			i += 2
+		default:
+			sb.WriteByte(s[i])
+			sb.WriteByte(s[i+1])
+			i++
+		}
+	}
+	return sb.String()
+}
+
+func (p *dnStringParser) parse() (DN, error) {
+	rdns := DN{}
+	p.skipSpaces()
+	if p.eof() {
+		return rdns, nil
+	}
+	if p.syntax == opensslOnelineSyntax {
+		if !p.isRDNSeparator(p.peek()) {
+			return nil, p.errorf("'/' is expected at the beginning")
+		}
+		p.pos++
+	}
+	for {
+		rdn, err := p.parseRDN()
+		if err != nil {
+			return nil, err
+		}
+		rdns = append(rdns, rdn)
+		if p.eof() {
+			break
+		}
+		if !p.isRDNSeparator(p.peek()) {
+			return nil, p.errorf("unexpected character %q", p.peek())
+		}
+		p.pos++
+		p.skipSpaces()
+		if p.opts.trailingSeparator && p.eof() {
+			break
+		}
+	}
+
+	dn := rdns
+	if p.syntax != opensslOnelineSyntax {
+		dn = rdns.ReverseDnOrder()
+	}
+	if isValid, err := isValidDN(dn); isValid == false {
+		return nil, err
+	}
+	return dn, nil
+}
+
+func (p *dnStringParser) parseRDN() (RDN, error) {
+	var rdn RDN
+	for {
+		atv, err := p.parseAttributeTypeAndValue()
+		if err != nil {
+			return nil, err
+		}
+		rdn = append(rdn, atv)
+		if p.eof() || p.peek() != '+' {
+			return rdn, nil
+		}
+		p.pos++
+		p.skipSpaces()
+	}
+}
+
+func (p *dnStringParser) parseAttributeTypeAndValue() (AttributeTypeAndValue, error) {
+	atv, err := p.parseAttributeType()
+	if err != nil {
+		return AttributeTypeAndValue{}, err
+	}
+	p.skipSpaces()
+	if p.eof() || p.peek() != '=' {
+		return AttributeTypeAndValue{}, p.errorf("'=' is expected after AttributeType")
+	}
+	p.pos++
+	p.skipSpaces()
+	if atv.Value, err = p.parseAttributeValue(atv.resolvedType()); err != nil {
+		return AttributeTypeAndValue{}, err
+	}
+	p.skipSpaces()
+	return atv, nil
+}
+
+// parseAttributeType parses a descriptor or a dotted-decimal object identifier
+// and returns AttributeTypeAndValue whose Type (and Oid) is set.
+func (p *dnStringParser) parseAttributeType() (AttributeTypeAndValue, error) {
+	start := p.pos
+	if p.syntax == rfc1779Syntax && len(p.s)-p.pos >= 4 && strings.EqualFold(p.s[p.pos:p.pos+4], "OID.") {
+		p.pos += 4
+	}
+	typeStart := p.pos
+	for !p.eof() && isKeyChar(p.peek()) {
+		p.pos++
+	}
+	name := p.s[typeStart:p.pos]
+	if name == "" {
+		return AttributeTypeAndValue{}, p.errorf("AttributeType is expected")
+	}
+
+	if typeStart != start && (name[0] < '0' || name[0] > '9') {
+		return AttributeTypeAndValue{}, p.errorf("%s is not a dotted-decimal object identifier", name)
+	}
+	atv, err := attributeTypeFromName(name)
+	if err != nil {
+		return AttributeTypeAndValue{}, p.errorf("%w", err)
+	}
+	return atv, nil
+}
+
+// attributeTypeFromName returns AttributeTypeAndValue whose Type (and Oid) is set
+// from name, a descriptor or a dotted-decimal object identifier.
+// A known object identifier is converted to the corresponding AttributeType, and the others to Generic.
+func attributeTypeFromName(name string) (AttributeTypeAndValue, error) {
+	if name == "" || name[0] < '0' || name[0] > '9' {
+		at, err := AttributeTypeFromShortName(name)
+		if err != nil {
+			return AttributeTypeAndValue{}, err
+		}
+		return AttributeTypeAndValue{Type: at}, nil
+	}
+
+	oid, err := convertToObjectIdentifier(name)
+	if err != nil {
+		return AttributeTypeAndValue{}, err
+	}
+	if at, err := ReferAttributeTypeName(oid); err == nil {
+		return AttributeTypeAndValue{Type: at}, nil
+	}
+	return AttributeTypeAndValue{Type: Generic, Oid: oid.String()}, nil
+}
+
+func (p *dnStringParser) parseAttributeValue(at AttributeType) (AttributeValue, error) {
+	if p.syntax != opensslOnelineSyntax && !p.eof() && p.peek() == '#' {
+		return p.parseHexAttributeValue()
+	}
+
+	var v string
+	var err error
+	if p.syntax == rfc1779Syntax && !p.eof() && p.peek() == '"' {
+		v, err = p.parseQuotedString()
+	} else {
+		v, err = p.parseString()
+	}
+	if err != nil {
+		return AttributeValue{}, err
+	}
+	if !utf8.ValidString(v) {
+		return AttributeValue{}, p.errorf("AttributeValue is not a valid UTF-8 string")
+	}
+	return AttributeValue{Encoding: defaultEncoding(at), Value: v}, nil
+}
+
+// parseHexAttributeValue parses "#" followed by the hex encoded BER form of an AttributeValue.
+func (p *dnStringParser) parseHexAttributeValue() (AttributeValue, error) {
+	p.pos++
+	start := p.pos
+	for !p.eof() && isHexChar(p.peek()) {
+		p.pos++
+	}
+	b, err := hex.DecodeString(p.s[start:p.pos])
+	if err != nil {
+		return AttributeValue{}, p.errorf("invalid hex AttributeValue: %w", err)
+	}
+	var r asn1.RawValue
+	rest, err := asn1.Unmarshal(b, &r)
+	if err != nil {
+		return AttributeValue{}, p.errorf("invalid hex AttributeValue: %w", err)
+	} else if len(rest) != 0 {
+		return AttributeValue{}, p.errorf("invalid hex AttributeValue: trailing data after AttributeValue")
+	}
+	av, err := convertToAttributeValue(r)
+	if err != nil {
+		return AttributeValue{}, p.errorf("%w", err)
+	}
+	return av, nil
+}
+
+// parseString parses an unquoted AttributeValue up to the next unescaped separator.
+// In RFC 4514 syntax, "\" followed by two hex digits represents a byte of the UTF-8 form of the value.
+// In RFC 1779 syntax, unescaped trailing spaces are removed.
+// In OpenSSL oneline syntax, only "/", "+" and "\" are special.
+func (p *dnStringParser) parseString() (string, error) {
+	var b []byte
+	trimmed := 0
+	for !p.eof() {
+		c := p.peek()
+		if c == '+' || p.isRDNSeparator(c) {
+			break
+		}
+		switch {
+		case c == '\\':
+			e, err := p.parseEscape()
+			if err != nil {
+				return "", err
+			}
+			b = append(b, e)
+			trimmed = len(b)
+			continue
+		case p.syntax != opensslOnelineSyntax && (c == '"' || c == '<' || c == '>' || c == ';' || c == 0x00):
+			return "", p.errorf("character %q must be escaped", c)
+		}
+		b = append(b, c)
+		if c != ' ' {
+			trimmed = len(b)
+		}
+		p.pos++
+	}
+	if p.syntax == rfc1779Syntax {
+		b = b[:trimmed]
+	}
+	return string(b), nil
+}
+
+// parseEscape parses "\" followed by a special character or, in RFC 4514 syntax, two hex digits.
+func (p *dnStringParser) parseEscape() (byte, error) {
+	p.pos++
+	if p.eof() {
+		return 0, p.errorf("unterminated escape")
+	}
+	c := p.peek()
+	if p.syntax == rfc4514Syntax && isHexChar(c) {
+		if p.pos+1 >= len(p.s) || !isHexChar(p.s[p.pos+1]) {
+			return 0, p.errorf("invalid hex escape")
+		}
+		h, _ := hex.DecodeString(p.s[p.pos : p.pos+2])
+		p.pos += 2
+		return h[0], nil
+	}
+	if p.syntax != opensslOnelineSyntax && !isEscapableChar(c) {
+		return 0, p.errorf("character %q can not be escaped", c)
+	}
+	p.pos++
+	return c, nil
+}
+
+// parseQuotedString parses an AttributeValue enclosed in double quotes of RFC 1779 syntax.
+func (p *dnStringParser) parseQuotedString() (string, error) {
+	p.pos++
+	var b []byte
+	for !p.eof() {
+		c := p.peek()
+		switch c {
+		case '"':
+			p.pos++
+			return string(b), nil
+		case '\\':
+			e, err := p.parseEscape()
+			if err != nil {
+				return "", err
+			}
+			b = append(b, e)
+			continue
+		}
+		b = append(b, c)
+		p.pos++
+	}
+	return "", p.errorf("unterminated quoted AttributeValue")
+}
+
+// skipSpaces skips spaces which are insignificant in the syntax.
+func (p *dnStringParser) skipSpaces() {
+	if p.syntax != rfc1779Syntax {
+		return
+	}
+	for !p.eof() && p.peek() == ' ' {
+		p.pos++
+	}
+}
+
+// isRDNSeparator reports whether c separates RDNs in the syntax.
+func (p *dnStringParser) isRDNSeparator(c byte) bool {
+	if p.syntax == opensslOnelineSyntax {
+		return c == '/'
+	}
+	return c == ',' || (p.opts.semicolonSeparator && c == ';')
+}
+
+func (p *dnStringParser) eof() bool {
+	return p.pos >= len(p.s)
+}
+
+func (p *dnStringParser) peek() byte {
+	return p.s[p.pos]
+}
+
+func (p *dnStringParser) errorf(format string, a ...interface{}) error {
+	return fmt.Errorf("position %d: "+format, append([]interface{}{p.pos}, a...)...)
+}
+
+// isKeyChar reports whether c can be a character of a descriptor or a dotted-decimal object identifier.
+func isKeyChar(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '-' || c == '.'
+}
+
+func isHexChar(c byte) bool {
+	return c >= '0' && c <= '9' || c >= 'a' && c <= 'f' || c >= 'A' && c <= 'F'
+}
+
+// isEscapableChar reports whether c can follow "\" in a string representation of a distinguished name.
+func isEscapableChar(c byte) bool {
+	//https://www.rfc-editor.org/rfc/rfc4514#section-3
+	//special = escaped / SPACE / SHARP / EQUALS
+	//escaped = DQUOTE / PLUS / COMMA / SEMI / LANGLE / RANGLE
+	switch c {
+	case '"', '+', ',', ';', '<', '>', ' ', '#', '=', '\\':
+		return true
+	}
+	return false
+}
+
+// DNProto is a language-neutral representation of DN, e.g. for a protocol buffers message.
+// AttributeTypes are represented by dotted-decimal oids and Encodings by their names, instead of Go enum values.
+type DNProto struct {
+	RDNs []RDNProto
+}
+
+// RDNProto is a language-neutral representation of RDN. See DNProto.
+type RDNProto struct {
+	Attributes []AttributeTypeAndValueProto
+}
+
+// AttributeTypeAndValueProto is a language-neutral representation of AttributeTypeAndValue. See DNProto.
+type AttributeTypeAndValueProto struct {
+	//Oid is the dotted-decimal object identifier of the AttributeType, e.g. "2.5.4.3".
+	Oid string
+	//Encoding is the name of the Encoding, e.g. "UTF8String".
+	Encoding string
+	Value    string
+}
+
+// ToProtoStruct converts dn to DNProto.
+func ToProtoStruct(dn DN) DNProto {
+	p := DNProto{RDNs: []RDNProto{}}
+	for _, rdn := range dn {
+		rp := RDNProto{Attributes: []AttributeTypeAndValueProto{}}
+		for _, atv := range rdn {
+			rp.Attributes = append(rp.Attributes, AttributeTypeAndValueProto{Oid: atv.oidString(), Encoding: atv.Value.Encoding.String(), Value: atv.Value.Value})
+		}
+		p.RDNs = append(p.RDNs, rp)
+	}
+	return p
+}
+
+// FromProtoStruct converts p to DN.
+// Known oids are converted to the corresponding AttributeTypes and the others to Generic,
+// so the conversion from ToProtoStruct is lossless, except that Generic with a known oid becomes the known AttributeType.
+// The DN is validated in the same way as MarshalDN.
+func FromProtoStruct(p DNProto) (dn DN, err error) {
+	dn = DN{}
+	for i, rp := range p.RDNs {
+		var rdn RDN
+		for j, ap := range rp.Attributes {
+			enc, err := encodingFromString(ap.Encoding)
+			if err != nil {
+				err := fmt.Errorf("%d th RDN %d th AttributeTypeAndValue error: %w", i, j, err)
+				return nil, err
+			}
+			oid, err := convertToObjectIdentifier(ap.Oid)
+			if err != nil {
+				err := fmt.Errorf("%d th RDN %d th AttributeTypeAndValue error: %w", i, j, err)
+				return nil, err
+			}
+			atv := AttributeTypeAndValue{Type: Generic, Oid: oid.String(), Value: AttributeValue{Encoding: enc, Value: ap.Value}}
+			if at, err := ReferAttributeTypeName(oid); err == nil {
+				atv = AttributeTypeAndValue{Type: at, Value: atv.Value}
+			}
+			rdn = append(rdn, atv)
+		}
+		dn = append(dn, rdn)
+	}
+	if isValid, err := isValidDN(dn); isValid == false {
+		return nil, err
+	}
+	return dn, nil
+}
+
+// FromFlexibleJSON converts b, a DN in JSON produced by other tools, to DN.
+// The following shapes are supported:
+//
+//	an object of AttributeTypes and values, e.g. {"CN":"foo","O":"bar","C":"JP"}
+//	an array of objects of "type" and "value", e.g. [{"type":"C","value":"JP"},{"type":"CN","value":"foo"}]
+//
+// AttributeTypes are descriptors, e.g. "CN" or "commonName" (case-insensitive), or dotted-decimal object identifiers.
+// Unknown object identifiers are converted to Generic.
+// Each member becomes a single-valued RDN whose Encoding is chosen in the same way as ParseRFC1779DN.
+//
+// As the order of members of an object is not significant in JSON, RDNs of the object shape are ordered by the rule:
+//
+//	DomainComponent, CountryName, StateOrProvinceName, LocalityName, OrganizationName, OrganizationalUnit,
+//	the other AttributeTypes, CommonName, ElectronicMailAddress
+//
+// AttributeTypes of the same rank are ordered by their keys.
+// The array shape is regarded as in DN order, that is, the first element is the most significant RDN.
+func FromFlexibleJSON(b []byte) (dn DN, err error) {
+	trimmed := bytes.TrimLeft(b, " \t\r\n")
+	if len(trimmed) == 0 {
+		return nil, fmt.Errorf("unable to parse flexible JSON DN: empty input")
+	}
+	switch trimmed[0] {
+	case '{':
+		dn, err = fromFlexibleJSONObject(b)
+	case '[':
+		dn, err = fromFlexibleJSONArray(b)
+	default:
+		err = fmt.Errorf("JSON object or array is expected")
+	}
+	if err != nil {
+		err := fmt.Errorf("unable to parse flexible JSON DN: %w", err)
+		return nil, err
+	}
+	if isValid, err := isValidDN(dn); isValid == false {
+		err := fmt.Errorf("unable to parse flexible JSON DN: %w", err)
+		return nil, err
+	}
+	return dn, nil
+}
+
+// fromFlexibleJSONObject converts b, the object shape of FromFlexibleJSON, to DN.
+func fromFlexibleJSONObject(b []byte) (DN, error) {
+	var m map[string]string
+	if err := json.Unmarshal(b, &m); err != nil {
+		return nil, err
+	}
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	dn := DN{}
+	for _, k := range keys {
+		atv, err := attributeTypeFromName(k)
+		if err != nil {
+			return nil, err
+		}
+		atv.Value = AttributeValue{Encoding: defaultEncoding(atv.resolvedType()), Value: m[k]}
+		dn = append(dn, RDN{atv})
+	}
+	sort.SliceStable(dn, func(i, j int) bool {
+		return flexibleJSONRank(dn[i][0].resolvedType()) < flexibleJSONRank(dn[j][0].resolvedType())
+	})
+	return dn, nil
+}
+
+// flexibleJSONRank returns the rank of at in the order of RDNs of the object shape of FromFlexibleJSON.
+func flexibleJSONRank(at AttributeType) int {
+	switch at {
+	case DomainComponent:
+		return 0
+	case CountryName:
+		return 1
+	case StateOrProvinceName:
+		return 2
+	case LocalityName:
+		return 3
+	case OrganizationName:
+		return 4
+	case OrganizationalUnit:
+		return 5
+	case CommonName:
+		return 7
+	case ElectronicMailAddress:
+		return 8
+	default:
+		return 6
+	}
+}
+
+// fromFlexibleJSONArray converts b, the array shape of FromFlexibleJSON, to DN.
+func fromFlexibleJSONArray(b []byte) (DN, error) {
+	var a []struct {
+		Type  string `json:"type"`
+		Value string `json:"value"`
+	}
+	if err := json.Unmarshal(b, &a); err != nil {
+		return nil, err
+	}
+	dn := DN{}
+	for i, e := range a {
+		atv, err := attributeTypeFromName(e.Type)
+		if err != nil {
+			err := fmt.Errorf("%d th element error: %w", i, err)
+			return nil, err
+		}
+		atv.Value = AttributeValue{Encoding: defaultEncoding(atv.resolvedType()), Value: e.Value}
+		dn = append(dn, RDN{atv})
+	}
+	return dn, nil
 }
@@ -1,9 +1,17 @@
 package dnutil
 
 import (
+	"bytes"
+	"crypto/x509/pkix"
 	"encoding/asn1"
 	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
 	"reflect"
+	"strconv"
+	"strings"
 	"testing"
 )
 
@@ -37,6 +45,91 @@ var (
 	dn2bytes, _ = hex.DecodeString("3027310b3009060355040613024a503118300a060355040a0c03616263300a060355040a1303616263")
 )
 
+func Test_findSurrogate(t *testing.T) {
+	tests := []struct {
+		name      string
+		s         string
+		wantSr    rune
+		wantIndex int
+		wantOk    bool
+	}{
+		{"TestCase: empty", "", 0, 0, false},
+		{"TestCase: ASCII", "abc", 0, 0, false},
+		{"TestCase: U+D7FF", "a\ud7ff", 0, 0, false},
+		{"TestCase: U+E000", "a\ue000", 0, 0, false},
+		{"TestCase: U+10000", "a\U00010000", 0, 0, false},
+		{"TestCase: U+D800", "a\xed\xa0\x80", 0xD800, 1, true},
+		{"TestCase: U+DFFF", "ab\xed\xbf\xbfc", 0xDFFF, 2, true},
+		{"TestCase: truncated", "a\xed\xa0", 0, 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotSr, gotIndex, gotOk := findSurrogate(tt.s)
+			if gotSr != tt.wantSr || gotIndex != tt.wantIndex || gotOk != tt.wantOk {
+				t.Errorf("findSurrogate() = %X, %v, %v, want %X, %v, %v", gotSr, gotIndex, gotOk, tt.wantSr, tt.wantIndex, tt.wantOk)
+			}
+		})
+	}
+}
+
+func TestMarshalDN_Surrogate(t *testing.T) {
+	dn := DN{RDN{AttributeTypeAndValue{Type: CommonName, Value: AttributeValue{UTF8String, "a\xed\xa0\x80"}}}}
+	_, err := MarshalDN(dn)
+	if err == nil || !strings.Contains(err.Error(), "surrogate code point U+D800") {
+		t.Errorf("MarshalDN() error = %v, want surrogate code point error", err)
+	}
+}
+
+func TestMarshalDN_StreetAddressPostalCodeUserID(t *testing.T) {
+	dn := DN{
+		RDN{AttributeTypeAndValue{Type: CountryName, Value: AttributeValue{PrintableString, "JP"}}},
+		RDN{AttributeTypeAndValue{Type: PostalCode, Value: AttributeValue{PrintableString, "100-0001"}}},
+		RDN{AttributeTypeAndValue{Type: StreetAddress, Value: AttributeValue{UTF8String, "1-1 Chiyoda"}}},
+		RDN{AttributeTypeAndValue{Type: UserID, Value: AttributeValue{IA5String, "mike"}}},
+	}
+	b, err := MarshalDN(dn)
+	if err != nil {
+		t.Fatalf("MarshalDN() error = %v", err)
+	}
+	got, err := ParseDERDN(b)
+	if err != nil {
+		t.Fatalf("ParseDERDN() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, dn) {
+		t.Errorf("ParseDERDN() = %v, want %v", got, dn)
+	}
+	if s, want := got.ToRFC4514FormatString(), "UID=mike,STREET=1-1 Chiyoda,POSTALCODE=100-0001,C=JP"; s != want {
+		t.Errorf("ToRFC4514FormatString() = %v, want %v", s, want)
+	}
+}
+
+func TestMarshalDN_BMPString(t *testing.T) {
+	dn := DN{
+		RDN{AttributeTypeAndValue{Type: CountryName, Value: AttributeValue{PrintableString, "JP"}}},
+		RDN{AttributeTypeAndValue{Type: CommonName, Value: AttributeValue{BMPString, "日本"}}},
+	}
+	want := decode("301c310b3009060355040613024a50310d300b06035504031e0465e5672c")
+	got, err := MarshalDN(dn)
+	if err != nil {
+		t.Fatalf("MarshalDN() error = %v", err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("MarshalDN() = %x, want %x", got, want)
+	}
+	parsed, err := ParseDERDN(got)
+	if err != nil {
+		t.Fatalf("ParseDERDN() error = %v", err)
+	}
+	if !reflect.DeepEqual(parsed, dn) {
+		t.Errorf("ParseDERDN() = %v, want %v", parsed, dn)
+	}
+
+	dn = DN{RDN{AttributeTypeAndValue{Type: CountryName, Value: AttributeValue{BMPString, "JP"}}}}
+	if _, err := MarshalDN(dn); err == nil {
+		t.Errorf("MarshalDN() error = nil, want CountryName encoding error")
+	}
+}
+
 func Test_newStringRawValue(t *testing.T) {
 	type args struct {
 		e  Encoding
@@ -51,9 +144,17 @@ func Test_newStringRawValue(t *testing.T) {
 		{"TestCase:PrintableString,JP", args{PrintableString, "JP"}, asn1.RawValue{Tag: asn1.TagPrintableString, FullBytes: decode("13024A50")}, false},
 		{"TestCase:UTF8String,日本語", args{UTF8String, "日本語"}, asn1.RawValue{Tag: asn1.TagUTF8String, FullBytes: decode("0C09E697A5E69CACE8AA9E")}, false},
 		{"TestCase:IA5String,a@example.com", args{IA5String, "a@example.com"}, asn1.RawValue{Tag: asn1.TagIA5String, FullBytes: decode("160D61406578616D706C652E636F6D")}, false},
-		{"TestCase:NotSupportedEncoding,JP", args{Encoding(6), "JP"}, asn1.RawValue{}, true},
+		{"TestCase:BMPString,abc", args{BMPString, "abc"}, asn1.RawValue{Tag: asn1.TagBMPString, FullBytes: decode("1E06006100620063")}, false},
+		{"TestCase:BMPString,日本", args{BMPString, "日本"}, asn1.RawValue{Tag: asn1.TagBMPString, FullBytes: decode("1E0465E5672C")}, false},
+		{"TestCase:BMPString,outside BMP", args{BMPString, "\U0001F600"}, asn1.RawValue{}, true},
+		{"TestCase:BMPString,invalid UTF-8", args{BMPString, "\xff"}, asn1.RawValue{}, true},
+		{"TestCase:TeletexString,café", args{TeletexString, "café"}, asn1.RawValue{Tag: asn1.TagT61String, FullBytes: decode("1404636166E9")}, false},
+		{"TestCase:TeletexString,日本", args{TeletexString, "日本"}, asn1.RawValue{}, true},
+		{"TestCase:TeletexString,invalid UTF-8", args{TeletexString, "\xff"}, asn1.RawValue{}, true},
+		{"TestCase:NotSupportedEncoding,JP", args{Encoding(999), "JP"}, asn1.RawValue{}, true},
 		{"TestCase:PrintableString,a@example.com", args{PrintableString, "a@example.com"}, asn1.RawValue{}, true},
 		{"TestCase:IA5String,日本語", args{IA5String, "日本語"}, asn1.RawValue{}, true},
+		{"TestCase:UTF8String,surrogate", args{UTF8String, "\xed\xa0\x80"}, asn1.RawValue{}, true},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -69,6 +170,73 @@ func Test_newStringRawValue(t *testing.T) {
 	}
 }
 
+func Test_decodeBMPString(t *testing.T) {
+	type args struct {
+		b []byte
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    string
+		wantErr bool
+	}{
+		{"TestCase: Test", args{[]byte{0x00, 0x54, 0x00, 0x65, 0x00, 0x73, 0x00, 0x74}}, "Test", false},
+		{"TestCase: Test with BOM", args{[]byte{0xfe, 0xff, 0x00, 0x54, 0x00, 0x65, 0x00, 0x73, 0x00, 0x74}}, "Test", false},
+		{"TestCase: Japanese", args{[]byte{0x30, 0x42, 0x30, 0x44}}, "あい", false},
+		{"TestCase: Japanese with BOM", args{[]byte{0xfe, 0xff, 0x30, 0x42, 0x30, 0x44}}, "あい", false},
+		{"TestCase: BOM not at the beginning is kept", args{[]byte{0x00, 0x54, 0xfe, 0xff}}, "T\uFEFF", false},
+		{"TestCase: BOM only", args{[]byte{0xfe, 0xff}}, "", false},
+		{"TestCase: empty", args{[]byte{}}, "", false},
+		{"TestCase: odd length", args{[]byte{0x00, 0x54, 0x00}}, "", true},
+		{"TestCase: surrogate pair", args{[]byte{0xd8, 0x3d, 0xde, 0x00}}, "\U0001F600", false},
+		{"TestCase: unpaired high surrogate", args{[]byte{0x00, 0x54, 0xd8, 0x3d}}, "", true},
+		{"TestCase: unpaired low surrogate", args{[]byte{0xde, 0x00, 0x00, 0x54}}, "", true},
+		{"TestCase: high surrogates in a row", args{[]byte{0xd8, 0x3d, 0xd8, 0x3d, 0xde, 0x00}}, "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := decodeBMPString(tt.args.b)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("decodeBMPString() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("decodeBMPString() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_newRawValue(t *testing.T) {
+	type args struct {
+		e Encoding
+		v string
+	}
+	tests := []struct {
+		name    string
+		args    args
+		wantR   asn1.RawValue
+		wantErr bool
+	}{
+		{"TestCase:PrintableString,JP", args{PrintableString, "JP"}, asn1.RawValue{Tag: asn1.TagPrintableString, FullBytes: decode("13024A50")}, false},
+		{"TestCase:OIDValue,1.2.3.4", args{OIDValue, "1.2.3.4"}, asn1.RawValue{Tag: asn1.TagOID, FullBytes: decode("06032A0304")}, false},
+		{"TestCase:OIDValue,broken oid", args{OIDValue, "a.b"}, asn1.RawValue{}, true},
+		{"TestCase:OIDValue,1", args{OIDValue, "1"}, asn1.RawValue{}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotR, err := newRawValue(tt.args.e, tt.args.v)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("newRawValue() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(gotR, tt.wantR) {
+				t.Errorf("newRawValue() gotR = %v, want %v", gotR, tt.wantR)
+			}
+		})
+	}
+}
+
 func TestReferOid(t *testing.T) {
 	type args struct {
 		atn AttributeType
@@ -96,6 +264,12 @@ func TestReferOid(t *testing.T) {
 		{"TestCase:GenerationQualifier", args{GenerationQualifier}, []int{2, 5, 4, 44}, false},
 		{"TestCase:ElectronicMailAddress", args{ElectronicMailAddress}, []int{1, 2, 840, 113549, 1, 9, 1}, false},
 		{"TestCase:DomainComponent", args{DomainComponent}, []int{0, 9, 2342, 19200300, 100, 1, 25}, false},
+		{"TestCase:UnstructuredName", args{UnstructuredName}, []int{1, 2, 840, 113549, 1, 9, 2}, false},
+		{"TestCase:UnstructuredAddress", args{UnstructuredAddress}, []int{1, 2, 840, 113549, 1, 9, 8}, false},
+		{"TestCase:StreetAddress", args{StreetAddress}, []int{2, 5, 4, 9}, false},
+		{"TestCase:PostalCode", args{PostalCode}, []int{2, 5, 4, 17}, false},
+		{"TestCase:UserID", args{UserID}, []int{0, 9, 2342, 19200300, 100, 1, 1}, false},
+		{"TestCase:Generic", args{Generic}, asn1.ObjectIdentifier{}, true},
 		{"TestCase:UnKnownAttributeType", args{AttributeType(9999)}, asn1.ObjectIdentifier{}, true},
 	}
 	for _, tt := range tests {
@@ -112,6 +286,170 @@ func TestReferOid(t *testing.T) {
 	}
 }
 
+func TestReferOid_ErrGenericRequiresOid(t *testing.T) {
+	type args struct {
+		atn AttributeType
+	}
+	tests := []struct {
+		name string
+		args args
+		want bool
+	}{
+		{"TestCase:Generic", args{Generic}, true},
+		{"TestCase:CommonName", args{CommonName}, false},
+		{"TestCase:UnKnownAttributeType", args{AttributeType(9999)}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ReferOid(tt.args.atn)
+			if got := errors.Is(err, ErrGenericRequiresOid); got != tt.want {
+				t.Errorf("errors.Is(ReferOid() error, ErrGenericRequiresOid) = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRegisterAttributeType(t *testing.T) {
+	jurisdiction := asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 99999, 1, 1}
+	at, err := RegisterAttributeType(jurisdiction, "jurisdiction")
+	if err != nil {
+		t.Fatalf("RegisterAttributeType() error = %v", err)
+	}
+	if at < firstCustomAttributeType {
+		t.Errorf("RegisterAttributeType() = %v, want above the built-in AttributeTypes", int(at))
+	}
+
+	type args struct {
+		oid       asn1.ObjectIdentifier
+		shortName string
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    AttributeType
+		wantErr bool
+	}{
+		{"TestCase: duplicated oid", args{jurisdiction, "other"}, at, false},
+		{"TestCase: built-in oid", args{asn1.ObjectIdentifier{2, 5, 4, 3}, "myCN"}, 0, true},
+		{"TestCase: invalid oid", args{asn1.ObjectIdentifier{3, 1}, "foo"}, 0, true},
+		{"TestCase: 1 arc oid", args{asn1.ObjectIdentifier{1}, "foo"}, 0, true},
+		{"TestCase: blank shortName", args{asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 99999, 1, 2}, ""}, 0, true},
+		{"TestCase: not descriptor shortName", args{asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 99999, 1, 2}, "1abc"}, 0, true},
+		{"TestCase: built-in shortName", args{asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 99999, 1, 2}, "CN"}, 0, true},
+		{"TestCase: built-in String() name", args{asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 99999, 1, 2}, "OrganizationUnit"}, 0, true},
+		{"TestCase: registered shortName", args{asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 99999, 1, 2}, "Jurisdiction"}, 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := RegisterAttributeType(tt.args.oid, tt.args.shortName)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("RegisterAttributeType() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("RegisterAttributeType() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRegisterAttributeType_Lookup(t *testing.T) {
+	oid := asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 99999, 2, 1}
+	at, err := RegisterAttributeType(oid, "exampleRegion")
+	if err != nil {
+		t.Fatalf("RegisterAttributeType() error = %v", err)
+	}
+
+	if got, err := ReferOid(at); err != nil || !got.Equal(oid) {
+		t.Errorf("ReferOid() = %v, %v, want %v", got, err, oid)
+	}
+	if got, err := ReferAttributeTypeName(oid); err != nil || got != at {
+		t.Errorf("ReferAttributeTypeName() = %v, %v, want %v", got, err, at)
+	}
+	if got, err := AttributeTypeFromShortName("EXAMPLEREGION"); err != nil || got != at {
+		t.Errorf("AttributeTypeFromShortName() = %v, %v, want %v", got, err, at)
+	}
+	if got := toDefinedShortName(at); got != "exampleRegion" {
+		t.Errorf("toDefinedShortName() = %v, want %v", got, "exampleRegion")
+	}
+	if got := at.String(); got != "exampleRegion" {
+		t.Errorf("String() = %v, want %v", got, "exampleRegion")
+	}
+	if !isDefinedOid(oid) {
+		t.Errorf("isDefinedOid() = false, want true")
+	}
+	if IsDirectoryStringType(at) {
+		t.Errorf("IsDirectoryStringType() = true, want false")
+	}
+	if IsIA5Type(at) {
+		t.Errorf("IsIA5Type() = true, want false")
+	}
+
+	d := DN{
+		RDN{AttributeTypeAndValue{Type: CountryName, Value: AttributeValue{PrintableString, "JP"}}},
+		RDN{AttributeTypeAndValue{Type: at, Value: AttributeValue{UTF8String, "Kanto"}}},
+	}
+	if got := d.ToRFC4514FormatString(); got != "EXAMPLEREGION=Kanto,C=JP" {
+		t.Errorf("ToRFC4514FormatString() = %v, want %v", got, "EXAMPLEREGION=Kanto,C=JP")
+	}
+	b, err := MarshalDN(d)
+	if err != nil {
+		t.Fatalf("MarshalDN() error = %v", err)
+	}
+	got, err := ParseDERDN(b)
+	if err != nil {
+		t.Fatalf("ParseDERDN() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, d) {
+		t.Errorf("ParseDERDN() = %v, want %v", got, d)
+	}
+	if got, err := ParseRFC4514DN("exampleRegion=Kanto,C=JP"); err != nil || !reflect.DeepEqual(got, d) {
+		t.Errorf("ParseRFC4514DN() = %v, %v, want %v", got, err, d)
+	}
+	if !d.HasOid(oid.String()) {
+		t.Errorf("HasOid() = false, want true")
+	}
+	jb, err := json.Marshal(at)
+	if err != nil {
+		t.Fatalf("json.Marshal() error = %v", err)
+	}
+	var jat AttributeType
+	if err := json.Unmarshal(jb, &jat); err != nil || jat != at {
+		t.Errorf("json.Unmarshal() = %v, %v, want %v", jat, err, at)
+	}
+
+	g := AttributeTypeAndValue{Type: Generic, Oid: oid.String(), Value: AttributeValue{UTF8String, "Kanto"}}
+	if got := g.resolvedType(); got != at {
+		t.Errorf("resolvedType() = %v, want %v", got, at)
+	}
+}
+
+func Test_isKeystring(t *testing.T) {
+	type args struct {
+		s string
+	}
+	tests := []struct {
+		name string
+		args args
+		want bool
+	}{
+		{"TestCase: short name", args{"cn"}, true},
+		{"TestCase: with digit and hyphen", args{"x-attr1"}, true},
+		{"TestCase: blank", args{""}, false},
+		{"TestCase: leading digit", args{"1abc"}, false},
+		{"TestCase: leading hyphen", args{"-abc"}, false},
+		{"TestCase: dot", args{"a.b"}, false},
+		{"TestCase: underscore", args{"a_b"}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isKeystring(tt.args.s); got != tt.want {
+				t.Errorf("isKeystring() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
 func Test_isDirectoryString(t *testing.T) {
 	type args struct {
 		tn int
@@ -248,8 +586,10 @@ func Test_convertToAttributeValue(t *testing.T) {
 	var r1 = asn1.RawValue{Tag: asn1.TagPrintableString, Bytes: decode("4A50"), FullBytes: decode("13024A50")}                                       //PrintableString JP
 	var r2 = asn1.RawValue{Tag: asn1.TagUTF8String, Bytes: decode("E697A5E69CAC"), FullBytes: decode("0C06E697A5E69CAC")}                            //UTF8String 日本
 	var r3 = asn1.RawValue{Tag: asn1.TagIA5String, Bytes: decode("61406578616D706C652E636F6D"), FullBytes: decode("160D61406578616D706C652E636F6D")} //IA5String a@example.com
-	var r4 = asn1.RawValue{Tag: asn1.TagBMPString, Bytes: decode("006100620063"), FullBytes: decode("1E06006100620063")}                             //BMPString JP
+	var r4 = asn1.RawValue{Tag: asn1.TagBMPString, Bytes: decode("006100620063"), FullBytes: decode("1E06006100620063")}                             //BMPString abc
 	var r5 = asn1.RawValue{Tag: asn1.TagPrintableString, Bytes: decode("AAA"), FullBytes: decode("AAA")}                                             //Broken Data
+	var r6 = asn1.RawValue{Tag: asn1.TagOID, Bytes: decode("2A0304"), FullBytes: decode("06032A0304")}                                               //OBJECT IDENTIFIER 1.2.3.4
+	var r7 = asn1.RawValue{Tag: asn1.TagOID, Bytes: decode("AAA"), FullBytes: decode("AAA")}                                                         //Broken Data
 	type args struct {
 		r asn1.RawValue
 	}
@@ -262,8 +602,12 @@ func Test_convertToAttributeValue(t *testing.T) {
 		{"TestCase:PrintableString ", args{r1}, AttributeValue{Encoding: PrintableString, Value: "JP"}, false},
 		{"TestCase:UTF8String ", args{r2}, AttributeValue{Encoding: UTF8String, Value: "日本"}, false},
 		{"TestCase:IA5String ", args{r3}, AttributeValue{Encoding: IA5String, Value: "a@example.com"}, false},
-		{"TestCase:BMPString ", args{r4}, AttributeValue{}, true},
+		{"TestCase:BMPString ", args{r4}, AttributeValue{Encoding: BMPString, Value: "abc"}, false},
+		{"TestCase:TeletexString ", args{asn1.RawValue{Tag: asn1.TagT61String, Bytes: decode("636166E9"), FullBytes: decode("1404636166E9")}}, AttributeValue{Encoding: TeletexString, Value: "café"}, false},
+		{"TestCase:BMPString , odd length", args{asn1.RawValue{Tag: asn1.TagBMPString, Bytes: decode("0061FF"), FullBytes: decode("1E030061FF")}}, AttributeValue{}, true},
 		{"TestCase:PrintableString , Broken raw", args{r5}, AttributeValue{}, true},
+		{"TestCase:OBJECT IDENTIFIER ", args{r6}, AttributeValue{Encoding: OIDValue, Value: "1.2.3.4"}, false},
+		{"TestCase:OBJECT IDENTIFIER , Broken raw", args{r7}, AttributeValue{}, true},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -405,6 +749,12 @@ func Test_convertToDn(t *testing.T) {
 			DN{},
 			false,
 		},
+		{
+			"TestCase:nil innerDN",
+			args{nil},
+			DN{},
+			false,
+		},
 		{
 			"TestCase:1 RDN",
 			args{innerDN{irv1}},
@@ -436,6 +786,12 @@ func Test_convertToDn(t *testing.T) {
 			DN{},
 			true,
 		},
+		{
+			"TestCase:Empty RDN",
+			args{innerDN{irv1, innerRDNSET{}}},
+			DN{},
+			true,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -451,6 +807,50 @@ func Test_convertToDn(t *testing.T) {
 	}
 }
 
+func TestParseDERDN_EmptyDN(t *testing.T) {
+	tests := []struct {
+		name  string
+		parse func([]byte) (DN, error)
+	}{
+		{"TestCase: ParseDERDN", ParseDERDN},
+		{"TestCase: ParseDERDNWithMode Strict", func(b []byte) (DN, error) { return ParseDERDNWithMode(b, Strict) }},
+		{"TestCase: ParseDERDNWithMode Compatible", func(b []byte) (DN, error) { return ParseDERDNWithMode(b, Compatible) }},
+		{"TestCase: ParseDERDNOpts", func(b []byte) (DN, error) { return ParseDERDNOpts(b) }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.parse(decode("3000"))
+			if err != nil {
+				t.Fatalf("parse error = %v", err)
+			}
+			if got == nil || len(got) != 0 {
+				t.Errorf("parse got = %#v, want non-nil DN{}", got)
+			}
+		})
+	}
+}
+
+func TestParseDERDN_EmptyRDN(t *testing.T) {
+	tests := []struct {
+		name    string
+		dnBytes []byte
+		wantErr string
+	}{
+		{"TestCase: empty SET", decode("30023100"), "unable to parse der DN: RDN at index 0 contains no AttributeTypeAndValue"},
+		{"TestCase: C=JP and empty SET", decode("300f310b3009060355040613024a503100"), "unable to parse der DN: RDN at index 1 contains no AttributeTypeAndValue"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, mode := range []ParseMode{Strict, Compatible} {
+				_, err := ParseDERDNWithMode(tt.dnBytes, mode)
+				if err == nil || err.Error() != tt.wantErr {
+					t.Errorf("ParseDERDNWithMode() error = %v, wantErr %v", err, tt.wantErr)
+				}
+			}
+		})
+	}
+}
+
 func TestParseDERDN(t *testing.T) {
 	type args struct {
 		dnBytes []byte
@@ -479,7 +879,10 @@ func TestParseDERDN(t *testing.T) {
 		{"TestCase:Empty DN", args{decode("3000")}, DN{}, false},
 		{"TestCase:C=JP(UTF8String))", args{decode("300d310b300906035504060c024a50")}, nil, true},
 		{"TestCase:Broken DER DN", args{decode("13016161")}, nil, true},
-		{"TestCase:CN=a(BMPString)", args{decode("300d310b300906035504031e020061")}, nil, true},
+		{"TestCase:CN=a(BMPString)", args{decode("300d310b300906035504031e020061")}, DN{
+			RDN{AttributeTypeAndValue{Type: CommonName, Value: AttributeValue{Encoding: BMPString, Value: "a"}}},
+		}, false},
+		{"TestCase:C=JP(BMPString)", args{decode("300f310d300b06035504061e04004a0050")}, nil, true},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -495,93 +898,424 @@ func TestParseDERDN(t *testing.T) {
 	}
 }
 
-func TestReferAttributeTypeName(t *testing.T) {
+func TestParseDERDNWithMode(t *testing.T) {
+	var cnAbc = DN{RDN{AttributeTypeAndValue{Type: CommonName, Value: AttributeValue{Encoding: UTF8String, Value: "abc"}}}}
+	var c = AttributeTypeAndValue{Type: CountryName, Value: AttributeValue{Encoding: PrintableString, Value: "JP"}}
+	var cn = AttributeTypeAndValue{Type: CommonName, Value: AttributeValue{Encoding: UTF8String, Value: "a"}}
+	var jpA = DN{RDN{c, cn}}
+	var aJp = DN{RDN{cn, c}}
 	type args struct {
-		oid asn1.ObjectIdentifier
+		dnBytes []byte
+		mode    ParseMode
 	}
 	tests := []struct {
 		name    string
 		args    args
-		wantAtn AttributeType
+		wantDn  DN
 		wantErr bool
 	}{
-		{"TestCase:CountryName", args{asn1.ObjectIdentifier{2, 5, 4, 6}}, CountryName, false},
-		{"TestCase:OrganizationName", args{asn1.ObjectIdentifier{2, 5, 4, 10}}, OrganizationName, false},
-		{"TestCase:OrganizationalUnit", args{asn1.ObjectIdentifier{2, 5, 4, 11}}, OrganizationalUnit, false},
-		{"TestCase:DnQualifier", args{asn1.ObjectIdentifier{2, 5, 4, 46}}, DnQualifier, false},
-		{"TestCase:StateOrProvinceName", args{asn1.ObjectIdentifier{2, 5, 4, 8}}, StateOrProvinceName, false},
-		{"TestCase:CommonName", args{asn1.ObjectIdentifier{2, 5, 4, 3}}, CommonName, false},
-		{"TestCase:SerialNumber", args{asn1.ObjectIdentifier{2, 5, 4, 5}}, SerialNumber, false},
-		{"TestCase:LocalityName", args{asn1.ObjectIdentifier{2, 5, 4, 7}}, LocalityName, false},
-		{"TestCase:Title", args{asn1.ObjectIdentifier{2, 5, 4, 12}}, Title, false},
-		{"TestCase:Surname", args{asn1.ObjectIdentifier{2, 5, 4, 4}}, Surname, false},
-		{"TestCase:GivenName", args{asn1.ObjectIdentifier{2, 5, 4, 42}}, GivenName, false},
-		{"TestCase:Initials", args{asn1.ObjectIdentifier{2, 5, 4, 43}}, Initials, false},
-		{"TestCase:Pseudonym", args{asn1.ObjectIdentifier{2, 5, 4, 65}}, Pseudonym, false},
-		{"TestCase:GenerationQualifier", args{asn1.ObjectIdentifier{2, 5, 4, 44}}, GenerationQualifier, false},
-		{"TestCase:ElectronicMailAddress", args{asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 9, 1}}, ElectronicMailAddress, false},
-		{"TestCase:DomainComponent", args{asn1.ObjectIdentifier{0, 9, 2342, 19200300, 100, 1, 25}}, DomainComponent, false},
-		{"TestCase:Others", args{asn1.ObjectIdentifier{9, 9, 9, 9}}, 0, true},
+		{"TestCase:Strict CN=abc", args{decode("300e310c300a06035504030c03616263"), Strict}, cnAbc, false},
+		{"TestCase:Compatible CN=abc", args{decode("300e310c300a06035504030c03616263"), Compatible}, cnAbc, false},
+		{"TestCase:Strict CN=abc wrapped in SET", args{decode("3010310e300c060355040331050c03616263"), Strict}, nil, true},
+		{"TestCase:Compatible CN=abc wrapped in SET", args{decode("3010310e300c060355040331050c03616263"), Compatible}, cnAbc, false},
+		{"TestCase:Compatible CN=abc wrapped in SET with 2 elements", args{decode("3015311330110603550403310a0c036162630c03616263"), Compatible}, nil, true},
+		{"TestCase:Compatible Empty DN", args{decode("3000"), Compatible}, DN{}, false},
+		{"TestCase:Compatible Broken DER DN", args{decode("13016161"), Compatible}, nil, true},
+		{"TestCase:Strict missing AttributeValue", args{decode("3009310730050603550403"), Strict}, nil, true},
+		{"TestCase:Compatible missing AttributeValue", args{decode("3009310730050603550403"), Compatible}, nil, true},
+		{"TestCase:Strict unknown oid", args{decode("300e310c300a06032a03040c03616263"), Strict}, DN{RDN{AttributeTypeAndValue{Type: Generic, Oid: "1.2.3.4", Value: AttributeValue{Encoding: UTF8String, Value: "abc"}}}}, false},
+		{"TestCase:RejectUnknownOIDs unknown oid", args{decode("300e310c300a06032a03040c03616263"), RejectUnknownOIDs}, nil, true},
+		{"TestCase:RejectUnknownOIDs CN=abc", args{decode("300e310c300a06035504030c03616263"), RejectUnknownOIDs}, cnAbc, false},
+		{"TestCase:Strict C=JP in UTF8String", args{decode("300d310b300906035504060c024a50"), Strict}, nil, true},
+		{"TestCase:Compatible C=JP in UTF8String", args{decode("300d310b300906035504060c024a50"), Compatible}, DN{RDN{AttributeTypeAndValue{Type: CountryName, Value: AttributeValue{Encoding: UTF8String, Value: "JP"}}}}, false},
+		{"TestCase:Strict C=JPN", args{decode("300e310c300a060355040613034a504e"), Strict}, DN{RDN{AttributeTypeAndValue{Type: CountryName, Value: AttributeValue{Encoding: PrintableString, Value: "JPN"}}}}, false},
+		{"TestCase:Compatible C=JPN", args{decode("300e310c300a060355040613034a504e"), Compatible}, DN{RDN{AttributeTypeAndValue{Type: CountryName, Value: AttributeValue{Encoding: PrintableString, Value: "JPN"}}}}, false},
+		{"TestCase:Compatible|RejectUnknownOIDs CN=abc wrapped in SET", args{decode("3010310e300c060355040331050c03616263"), Compatible | RejectUnknownOIDs}, cnAbc, false},
+		{"TestCase:Compatible multi-valued RDN not in DER order", args{decode("301731153009060355040613024a50300806035504030c0161"), Compatible}, jpA, false},
+		{"TestCase:Compatible multi-valued RDN in DER order", args{decode("30173115300806035504030c01613009060355040613024a50"), Compatible}, aJp, false},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			gotAtn, err := ReferAttributeTypeName(tt.args.oid)
+			gotDn, err := ParseDERDNWithMode(tt.args.dnBytes, tt.args.mode)
 			if (err != nil) != tt.wantErr {
-				t.Errorf("ReferAttributeTypeName() error = %v, wantErr %v", err, tt.wantErr)
+				t.Errorf("ParseDERDNWithMode() error = %v, wantErr %v", err, tt.wantErr)
 				return
 			}
-			if gotAtn != tt.wantAtn {
-				t.Errorf("ReferAttributeTypeName() gotAtn = %v, want %v", gotAtn, tt.wantAtn)
+			if !reflect.DeepEqual(gotDn, tt.wantDn) {
+				t.Errorf("ParseDERDNWithMode() gotDn = %v, want %v", gotDn, tt.wantDn)
 			}
 		})
 	}
 }
 
-func Test_isDefinedOid(t *testing.T) {
+func TestParseDERDNOpts(t *testing.T) {
+	cn := func(v string) DN {
+		return DN{RDN{AttributeTypeAndValue{Type: CommonName, Value: AttributeValue{Encoding: UTF8String, Value: v}}}}
+	}
+	t61cn := func(v string) DN {
+		return DN{RDN{AttributeTypeAndValue{Type: CommonName, Value: AttributeValue{Encoding: TeletexString, Value: v}}}}
+	}
+	windows1252 := func(b []byte) (string, error) {
+		var sb strings.Builder
+		for _, c := range b {
+			if c == 0x80 {
+				sb.WriteRune('€')
+				continue
+			}
+			sb.WriteRune(rune(c))
+		}
+		return sb.String(), nil
+	}
+	failing := func(b []byte) (string, error) {
+		return "", fmt.Errorf("unsupported charset")
+	}
+	invalidUTF8 := func(b []byte) (string, error) {
+		return string(b), nil
+	}
 	type args struct {
-		oid asn1.ObjectIdentifier
+		dnBytes []byte
+		opts    []ParseOption
 	}
 	tests := []struct {
-		name string
-		args args
-		want bool
+		name    string
+		args    args
+		wantDn  DN
+		wantErr bool
 	}{
-		{"TestCase:CountryName", args{asn1.ObjectIdentifier{2, 5, 4, 6}}, true},
-		{"TestCase:OrganizationName", args{asn1.ObjectIdentifier{2, 5, 4, 10}}, true},
-		{"TestCase:OrganizationalUnit", args{asn1.ObjectIdentifier{2, 5, 4, 11}}, true},
-		{"TestCase:DnQualifier", args{asn1.ObjectIdentifier{2, 5, 4, 46}}, true},
-		{"TestCase:StateOrProvinceName", args{asn1.ObjectIdentifier{2, 5, 4, 8}}, true},
-		{"TestCase:CommonName", args{asn1.ObjectIdentifier{2, 5, 4, 3}}, true},
-		{"TestCase:SerialNumber", args{asn1.ObjectIdentifier{2, 5, 4, 5}}, true},
-		{"TestCase:LocalityName", args{asn1.ObjectIdentifier{2, 5, 4, 7}}, true},
-		{"TestCase:Title", args{asn1.ObjectIdentifier{2, 5, 4, 12}}, true},
-		{"TestCase:Surname", args{asn1.ObjectIdentifier{2, 5, 4, 4}}, true},
-		{"TestCase:GivenName", args{asn1.ObjectIdentifier{2, 5, 4, 42}}, true},
-		{"TestCase:Initials", args{asn1.ObjectIdentifier{2, 5, 4, 43}}, true},
-		{"TestCase:Pseudonym", args{asn1.ObjectIdentifier{2, 5, 4, 65}}, true},
-		{"TestCase:GenerationQualifier", args{asn1.ObjectIdentifier{2, 5, 4, 44}}, true},
-		{"TestCase:ElectronicMailAddress", args{asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 9, 1}}, true},
-		{"TestCase:DomainComponent", args{asn1.ObjectIdentifier{0, 9, 2342, 19200300, 100, 1, 25}}, true},
-		{"TestCase:Others", args{asn1.ObjectIdentifier{9, 9, 9, 9}}, false},
+		{"TestCase: CN=abc", args{decode("300e310c300a06035504030c03616263"), nil}, cn("abc"), false},
+		{"TestCase: TeletexString Latin-1", args{decode("300f310d300b06035504031404636166e9"), nil}, t61cn("café"), false},
+		{"TestCase: TeletexString Latin-1 with decoder", args{decode("300f310d300b06035504031404636166e9"), []ParseOption{WithTeletexDecoder(decodeLatin1)}}, cn("café"), false},
+		{"TestCase: TeletexString Windows-1252 byte as Latin-1", args{decode("300c310a30080603550403140180"), nil}, t61cn("\u0080"), false},
+		{"TestCase: TeletexString Windows-1252 byte with decoder", args{decode("300c310a30080603550403140180"), []ParseOption{WithTeletexDecoder(windows1252)}}, cn("€"), false},
+		{"TestCase: TeletexString decoder error", args{decode("300c310a30080603550403140180"), []ParseOption{WithTeletexDecoder(failing)}}, nil, true},
+		{"TestCase: TeletexString decoder returns invalid UTF-8", args{decode("300c310a30080603550403140180"), []ParseOption{WithTeletexDecoder(invalidUTF8)}}, nil, true},
+		{"TestCase: TeletexString C=JP", args{decode("300d310b3009060355040614024a50"), nil}, nil, true},
+		{"TestCase: Compatible TeletexString C=JP", args{decode("300d310b3009060355040614024a50"), []ParseOption{WithParseMode(Compatible)}},
+			DN{RDN{AttributeTypeAndValue{Type: CountryName, Value: AttributeValue{Encoding: TeletexString, Value: "JP"}}}}, false},
+		{"TestCase: Strict TeletexString wrapped in SET", args{decode("3010310e300c060355040331051403616263"), nil}, nil, true},
+		{"TestCase: Compatible TeletexString wrapped in SET", args{decode("3010310e300c060355040331051403616263"), []ParseOption{WithParseMode(Compatible)}}, t61cn("abc"), false},
+		{"TestCase: RejectUnknownOIDs unknown oid", args{decode("300e310c300a06032a03041403616263"), []ParseOption{WithParseMode(RejectUnknownOIDs)}}, nil, true},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if got := isDefinedOid(tt.args.oid); got != tt.want {
-				t.Errorf("isDefinedOid() = %v, want %v", got, tt.want)
+			gotDn, err := ParseDERDNOpts(tt.args.dnBytes, tt.args.opts...)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseDERDNOpts() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(gotDn, tt.wantDn) {
+				t.Errorf("ParseDERDNOpts() gotDn = %v, want %v", gotDn, tt.wantDn)
 			}
 		})
 	}
 }
-func Test_convertToInnerAttributeTypeAndValue(t *testing.T) {
-	var r1 = asn1.RawValue{Tag: asn1.TagPrintableString, FullBytes: decode("13024A50")}    //PrintableString JP
-	var r2 = asn1.RawValue{Tag: asn1.TagUTF8String, FullBytes: decode("0C06E697A5E69CAC")} //UTF8String 日本
-	var atv1 = AttributeValue{Encoding: PrintableString, Value: "JP"}
-	var atnv1 = AttributeTypeAndValue{Type: CountryName, Value: atv1}
-	var atv2 = AttributeValue{Encoding: 99, Value: "JP"}
-	var atnv2 = AttributeTypeAndValue{Type: CountryName, Value: atv2}
-	var atv3 = AttributeValue{Encoding: PrintableString, Value: "JP"}
-	var atnv3 = AttributeTypeAndValue{Type: 9999, Value: atv3}
-	var atv4 = AttributeValue{Encoding: UTF8String, Value: "日本"}
+
+func TestParseDERDNWithMode_MismatchedEncoding(t *testing.T) {
+	//CountryName in UTF8String
+	dn, err := ParseDERDNWithMode(decode("300d310b300906035504060c024a50"), Compatible)
+	if err != nil {
+		t.Fatalf("ParseDERDNWithMode() error = %v", err)
+	}
+	want := []LintResult{{0, 0, LintMismatchedEncoding, "UTF8String is not allowed for CountryName"}}
+	if got := dn.Lint(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Lint() = %v, want %v", got, want)
+	}
+	if _, err := MarshalDN(dn); err == nil {
+		t.Errorf("MarshalDN() error = nil, want encoding error")
+	}
+}
+
+func TestParseDERDN_TeletexString(t *testing.T) {
+	dnBytes := decode("302e310b3009060355040613024a503110300e060355040a14074578616d706c65310d300b06035504031404636166e9")
+	want := DN{
+		RDN{AttributeTypeAndValue{Type: CountryName, Value: AttributeValue{Encoding: PrintableString, Value: "JP"}}},
+		RDN{AttributeTypeAndValue{Type: OrganizationName, Value: AttributeValue{Encoding: TeletexString, Value: "Example"}}},
+		RDN{AttributeTypeAndValue{Type: CommonName, Value: AttributeValue{Encoding: TeletexString, Value: "café"}}},
+	}
+	got, err := ParseDERDN(dnBytes)
+	if err != nil {
+		t.Fatalf("ParseDERDN() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseDERDN() = %v, want %v", got, want)
+	}
+	reMarshaled, err := MarshalDN(got)
+	if err != nil {
+		t.Fatalf("MarshalDN() error = %v", err)
+	}
+	if !bytes.Equal(reMarshaled, dnBytes) {
+		t.Errorf("MarshalDN() = %x, want %x", reMarshaled, dnBytes)
+	}
+
+	if _, err := ParseDERDN(decode("300d310b3009060355040614024a50")); err == nil {
+		t.Errorf("ParseDERDN() error = nil, want CountryName encoding error")
+	}
+}
+
+func Test_encodeBMPString(t *testing.T) {
+	tests := []struct {
+		name    string
+		st      string
+		want    []byte
+		wantErr bool
+	}{
+		{"TestCase: blank", "", []byte{}, false},
+		{"TestCase: ASCII", "Te", []byte{0x00, 0x54, 0x00, 0x65}, false},
+		{"TestCase: BMP", "日本", []byte{0x65, 0xe5, 0x67, 0x2c}, false},
+		{"TestCase: U+FFFF", "\uffff", []byte{0xff, 0xff}, false},
+		{"TestCase: outside BMP", "a\U0001F600", nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := encodeBMPString(tt.st)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("encodeBMPString() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !bytes.Equal(got, tt.want) {
+				t.Errorf("encodeBMPString() got = %x, want %x", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_encodeLatin1(t *testing.T) {
+	tests := []struct {
+		name    string
+		st      string
+		want    []byte
+		wantErr bool
+	}{
+		{"TestCase: blank", "", []byte{}, false},
+		{"TestCase: ASCII", "abc", []byte("abc"), false},
+		{"TestCase: Latin-1", "café", []byte{0x63, 0x61, 0x66, 0xe9}, false},
+		{"TestCase: U+00FF", "\u00ff", []byte{0xff}, false},
+		{"TestCase: U+0100", "\u0100", nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := encodeLatin1(tt.st)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("encodeLatin1() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("encodeLatin1() = %x, want %x", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_decodeLatin1(t *testing.T) {
+	tests := []struct {
+		name string
+		b    []byte
+		want string
+	}{
+		{"TestCase: blank", []byte{}, ""},
+		{"TestCase: ASCII", []byte("abc"), "abc"},
+		{"TestCase: Latin-1", []byte{0x63, 0x61, 0x66, 0xe9}, "café"},
+		{"TestCase: C1 control", []byte{0x80}, "\u0080"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := decodeLatin1(tt.b)
+			if err != nil || got != tt.want {
+				t.Errorf("decodeLatin1() = %q, %v, want %q", got, err, tt.want)
+			}
+		})
+	}
+}
+
+func Test_findMissingAttributeValue(t *testing.T) {
+	type args struct {
+		dnBytes []byte
+	}
+	tests := []struct {
+		name    string
+		args    args
+		wantErr string
+	}{
+		{"TestCase: CN=abc", args{decode("300e310c300a06035504030c03616263")}, ""},
+		{"TestCase: missing AttributeValue", args{decode("3009310730050603550403")}, "AttributeTypeAndValue at RDN 0, position 0 is missing a value"},
+		{"TestCase: missing AttributeValue in multi value RDN", args{decode("3015311330050603550403300a06035504030c03616263")}, "AttributeTypeAndValue at RDN 0, position 0 is missing a value"},
+		{"TestCase: missing AttributeValue in 2nd RDN", args{decode("3017310c300a06035504030c0361626331073005060355040a")}, "AttributeTypeAndValue at RDN 1, position 0 is missing a value"},
+		{"TestCase: Broken DER DN", args{decode("13016161")}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := findMissingAttributeValue(tt.args.dnBytes)
+			got := ""
+			if err != nil {
+				got = err.Error()
+			}
+			if got != tt.wantErr {
+				t.Errorf("findMissingAttributeValue() error = %v, wantErr %v", got, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestAttributeTypeFromShortName(t *testing.T) {
+	type args struct {
+		name string
+	}
+	tests := []struct {
+		name    string
+		args    args
+		wantAtn AttributeType
+		wantErr bool
+	}{
+		{"TestCase:cn", args{"cn"}, CommonName, false},
+		{"TestCase:CN", args{"CN"}, CommonName, false},
+		{"TestCase:commonName", args{"commonName"}, CommonName, false},
+		{"TestCase:COMMONNAME", args{"COMMONNAME"}, CommonName, false},
+		{"TestCase:c", args{"c"}, CountryName, false},
+		{"TestCase:countryName", args{"countryName"}, CountryName, false},
+		{"TestCase:o", args{"o"}, OrganizationName, false},
+		{"TestCase:organizationName", args{"organizationName"}, OrganizationName, false},
+		{"TestCase:ou", args{"ou"}, OrganizationalUnit, false},
+		{"TestCase:organizationalUnitName", args{"organizationalUnitName"}, OrganizationalUnit, false},
+		{"TestCase:dnQualifier", args{"dnQualifier"}, DnQualifier, false},
+		{"TestCase:st", args{"st"}, StateOrProvinceName, false},
+		{"TestCase:stateOrProvinceName", args{"stateOrProvinceName"}, StateOrProvinceName, false},
+		{"TestCase:serialNumber", args{"serialNumber"}, SerialNumber, false},
+		{"TestCase:L", args{"L"}, LocalityName, false},
+		{"TestCase:localityName", args{"localityName"}, LocalityName, false},
+		{"TestCase:title", args{"title"}, Title, false},
+		{"TestCase:sn", args{"sn"}, Surname, false},
+		{"TestCase:surname", args{"surname"}, Surname, false},
+		{"TestCase:givenName", args{"givenName"}, GivenName, false},
+		{"TestCase:initials", args{"initials"}, Initials, false},
+		{"TestCase:pseudonym", args{"pseudonym"}, Pseudonym, false},
+		{"TestCase:generationQualifier", args{"generationQualifier"}, GenerationQualifier, false},
+		{"TestCase:email", args{"email"}, ElectronicMailAddress, false},
+		{"TestCase:emailAddress", args{"emailAddress"}, ElectronicMailAddress, false},
+		{"TestCase:E", args{"E"}, ElectronicMailAddress, false},
+		{"TestCase:dc", args{"dc"}, DomainComponent, false},
+		{"TestCase:domainComponent", args{"domainComponent"}, DomainComponent, false},
+		{"TestCase:unstructuredName", args{"unstructuredName"}, UnstructuredName, false},
+		{"TestCase:unstructuredAddress", args{"unstructuredAddress"}, UnstructuredAddress, false},
+		{"TestCase:street", args{"street"}, StreetAddress, false},
+		{"TestCase:streetAddress", args{"streetAddress"}, StreetAddress, false},
+		{"TestCase:postalCode", args{"postalCode"}, PostalCode, false},
+		{"TestCase:UID", args{"UID"}, UserID, false},
+		{"TestCase:userid", args{"userid"}, UserID, false},
+		{"TestCase:Generic", args{"Generic"}, 0, true},
+		{"TestCase:blank", args{""}, 0, true},
+		{"TestCase:Others", args{"foo"}, 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotAtn, err := AttributeTypeFromShortName(tt.args.name)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("AttributeTypeFromShortName() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if gotAtn != tt.wantAtn {
+				t.Errorf("AttributeTypeFromShortName() gotAtn = %v, want %v", gotAtn, tt.wantAtn)
+			}
+		})
+	}
+}
+
+func TestReferAttributeTypeName(t *testing.T) {
+	type args struct {
+		oid asn1.ObjectIdentifier
+	}
+	tests := []struct {
+		name    string
+		args    args
+		wantAtn AttributeType
+		wantErr bool
+	}{
+		{"TestCase:CountryName", args{asn1.ObjectIdentifier{2, 5, 4, 6}}, CountryName, false},
+		{"TestCase:OrganizationName", args{asn1.ObjectIdentifier{2, 5, 4, 10}}, OrganizationName, false},
+		{"TestCase:OrganizationalUnit", args{asn1.ObjectIdentifier{2, 5, 4, 11}}, OrganizationalUnit, false},
+		{"TestCase:DnQualifier", args{asn1.ObjectIdentifier{2, 5, 4, 46}}, DnQualifier, false},
+		{"TestCase:StateOrProvinceName", args{asn1.ObjectIdentifier{2, 5, 4, 8}}, StateOrProvinceName, false},
+		{"TestCase:CommonName", args{asn1.ObjectIdentifier{2, 5, 4, 3}}, CommonName, false},
+		{"TestCase:SerialNumber", args{asn1.ObjectIdentifier{2, 5, 4, 5}}, SerialNumber, false},
+		{"TestCase:LocalityName", args{asn1.ObjectIdentifier{2, 5, 4, 7}}, LocalityName, false},
+		{"TestCase:Title", args{asn1.ObjectIdentifier{2, 5, 4, 12}}, Title, false},
+		{"TestCase:Surname", args{asn1.ObjectIdentifier{2, 5, 4, 4}}, Surname, false},
+		{"TestCase:GivenName", args{asn1.ObjectIdentifier{2, 5, 4, 42}}, GivenName, false},
+		{"TestCase:Initials", args{asn1.ObjectIdentifier{2, 5, 4, 43}}, Initials, false},
+		{"TestCase:Pseudonym", args{asn1.ObjectIdentifier{2, 5, 4, 65}}, Pseudonym, false},
+		{"TestCase:GenerationQualifier", args{asn1.ObjectIdentifier{2, 5, 4, 44}}, GenerationQualifier, false},
+		{"TestCase:ElectronicMailAddress", args{asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 9, 1}}, ElectronicMailAddress, false},
+		{"TestCase:DomainComponent", args{asn1.ObjectIdentifier{0, 9, 2342, 19200300, 100, 1, 25}}, DomainComponent, false},
+		{"TestCase:UnstructuredName", args{asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 9, 2}}, UnstructuredName, false},
+		{"TestCase:UnstructuredAddress", args{asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 9, 8}}, UnstructuredAddress, false},
+		{"TestCase:StreetAddress", args{asn1.ObjectIdentifier{2, 5, 4, 9}}, StreetAddress, false},
+		{"TestCase:PostalCode", args{asn1.ObjectIdentifier{2, 5, 4, 17}}, PostalCode, false},
+		{"TestCase:UserID", args{asn1.ObjectIdentifier{0, 9, 2342, 19200300, 100, 1, 1}}, UserID, false},
+		{"TestCase:Others", args{asn1.ObjectIdentifier{9, 9, 9, 9}}, 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotAtn, err := ReferAttributeTypeName(tt.args.oid)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ReferAttributeTypeName() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if gotAtn != tt.wantAtn {
+				t.Errorf("ReferAttributeTypeName() gotAtn = %v, want %v", gotAtn, tt.wantAtn)
+			}
+		})
+	}
+}
+
+func Test_isDefinedOid(t *testing.T) {
+	type args struct {
+		oid asn1.ObjectIdentifier
+	}
+	tests := []struct {
+		name string
+		args args
+		want bool
+	}{
+		{"TestCase:CountryName", args{asn1.ObjectIdentifier{2, 5, 4, 6}}, true},
+		{"TestCase:OrganizationName", args{asn1.ObjectIdentifier{2, 5, 4, 10}}, true},
+		{"TestCase:OrganizationalUnit", args{asn1.ObjectIdentifier{2, 5, 4, 11}}, true},
+		{"TestCase:DnQualifier", args{asn1.ObjectIdentifier{2, 5, 4, 46}}, true},
+		{"TestCase:StateOrProvinceName", args{asn1.ObjectIdentifier{2, 5, 4, 8}}, true},
+		{"TestCase:CommonName", args{asn1.ObjectIdentifier{2, 5, 4, 3}}, true},
+		{"TestCase:SerialNumber", args{asn1.ObjectIdentifier{2, 5, 4, 5}}, true},
+		{"TestCase:LocalityName", args{asn1.ObjectIdentifier{2, 5, 4, 7}}, true},
+		{"TestCase:Title", args{asn1.ObjectIdentifier{2, 5, 4, 12}}, true},
+		{"TestCase:Surname", args{asn1.ObjectIdentifier{2, 5, 4, 4}}, true},
+		{"TestCase:GivenName", args{asn1.ObjectIdentifier{2, 5, 4, 42}}, true},
+		{"TestCase:Initials", args{asn1.ObjectIdentifier{2, 5, 4, 43}}, true},
+		{"TestCase:Pseudonym", args{asn1.ObjectIdentifier{2, 5, 4, 65}}, true},
+		{"TestCase:GenerationQualifier", args{asn1.ObjectIdentifier{2, 5, 4, 44}}, true},
+		{"TestCase:ElectronicMailAddress", args{asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 9, 1}}, true},
+		{"TestCase:DomainComponent", args{asn1.ObjectIdentifier{0, 9, 2342, 19200300, 100, 1, 25}}, true},
+		{"TestCase:UnstructuredName", args{asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 9, 2}}, true},
+		{"TestCase:UnstructuredAddress", args{asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 9, 8}}, true},
+		{"TestCase:StreetAddress", args{asn1.ObjectIdentifier{2, 5, 4, 9}}, true},
+		{"TestCase:PostalCode", args{asn1.ObjectIdentifier{2, 5, 4, 17}}, true},
+		{"TestCase:UserID", args{asn1.ObjectIdentifier{0, 9, 2342, 19200300, 100, 1, 1}}, true},
+		{"TestCase:Others", args{asn1.ObjectIdentifier{9, 9, 9, 9}}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isDefinedOid(tt.args.oid); got != tt.want {
+				t.Errorf("isDefinedOid() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+func Test_convertToInnerAttributeTypeAndValue(t *testing.T) {
+	var r1 = asn1.RawValue{Tag: asn1.TagPrintableString, FullBytes: decode("13024A50")}    //PrintableString JP
+	var r2 = asn1.RawValue{Tag: asn1.TagUTF8String, FullBytes: decode("0C06E697A5E69CAC")} //UTF8String 日本
+	var atv1 = AttributeValue{Encoding: PrintableString, Value: "JP"}
+	var atnv1 = AttributeTypeAndValue{Type: CountryName, Value: atv1}
+	var atv2 = AttributeValue{Encoding: 99, Value: "JP"}
+	var atnv2 = AttributeTypeAndValue{Type: CountryName, Value: atv2}
+	var atv3 = AttributeValue{Encoding: PrintableString, Value: "JP"}
+	var atnv3 = AttributeTypeAndValue{Type: 9999, Value: atv3}
+	var atv4 = AttributeValue{Encoding: UTF8String, Value: "日本"}
 	var atnv4 = AttributeTypeAndValue{Type: CommonName, Value: atv4}
 	var atnv5 = AttributeTypeAndValue{Type: Generic, Oid: "1.2.3.4", Value: atv4}
 	type args struct {
@@ -723,1213 +1457,5772 @@ func Test_convertToInnerDN(t *testing.T) {
 	}
 }
 
-func TestMarshalDN(t *testing.T) {
-	var dn1 = DN{
-		RDN{AttributeTypeAndValue{Type: CountryName, Value: AttributeValue{Encoding: PrintableString, Value: "JP"}}},
-		RDN{AttributeTypeAndValue{Type: OrganizationName, Value: AttributeValue{Encoding: UTF8String, Value: "example"}}},
-		RDN{AttributeTypeAndValue{Type: OrganizationalUnit, Value: AttributeValue{Encoding: UTF8String, Value: "Ext"}}},
-		RDN{
-			AttributeTypeAndValue{Type: OrganizationalUnit, Value: AttributeValue{Encoding: UTF8String, Value: "Dev"}},
-			AttributeTypeAndValue{Type: OrganizationalUnit, Value: AttributeValue{Encoding: UTF8String, Value: "Sales"}},
-		},
-		RDN{
-			AttributeTypeAndValue{Type: CommonName, Value: AttributeValue{Encoding: UTF8String, Value: "ex"}},
-			AttributeTypeAndValue{Type: ElectronicMailAddress, Value: AttributeValue{Encoding: IA5String, Value: "ex@example.com"}}},
-	}
-	var dn2 = DN{
-		RDN{AttributeTypeAndValue{Type: 999, Value: AttributeValue{Encoding: UTF8String, Value: "cn1"}}},
-	}
-	var dn3 = DN{
-		RDN{AttributeTypeAndValue{Type: Generic, Oid: "2.5.4.6", Value: AttributeValue{Encoding: PrintableString, Value: "JP"}}},
-		RDN{AttributeTypeAndValue{Type: Generic, Oid: "2.5.4.10", Value: AttributeValue{Encoding: UTF8String, Value: "example"}}},
-		RDN{AttributeTypeAndValue{Type: Generic, Oid: "2.5.4.11", Value: AttributeValue{Encoding: UTF8String, Value: "Ext"}}},
-		RDN{
-			AttributeTypeAndValue{Type: Generic, Oid: "2.5.4.11", Value: AttributeValue{Encoding: UTF8String, Value: "Dev"}},
-			AttributeTypeAndValue{Type: Generic, Oid: "2.5.4.11", Value: AttributeValue{Encoding: UTF8String, Value: "Sales"}},
-		},
-		RDN{
-			AttributeTypeAndValue{Type: Generic, Oid: "2.5.4.3", Value: AttributeValue{Encoding: UTF8String, Value: "ex"}},
-			AttributeTypeAndValue{Type: Generic, Oid: "1.2.840.113549.1.9.1", Value: AttributeValue{Encoding: IA5String, Value: "ex@example.com"}}},
-	}
-	var dn4 = DN{
-		RDN{
-			AttributeTypeAndValue{Type: OrganizationalUnit, Value: AttributeValue{Encoding: PrintableString, Value: "a"}},
-			AttributeTypeAndValue{Type: OrganizationalUnit, Value: AttributeValue{Encoding: PrintableString, Value: "aa"}},
-		},
-	}
-	var dn5 = DN{
-		RDN{AttributeTypeAndValue{Type: Generic, Oid: "1.2.3.4", Value: AttributeValue{Encoding: PrintableString, Value: "a"}}},
-	}
-	//C=JP,O=example,OU=Ext,OU=Dev+OU=Sales,CN=ex+E=ex@example.com
-	var dnbytes1 = decode("3073310b3009060355040613024a503110300e060355040a0c076578616d706c65310c300a060355040b0c03457874311a300a060355040b0c03446576300c060355040b0c0553616c65733128300906035504030c026578301b06092a864886f70d010901160e6578406578616d706c652e636f6d")
-	var dnbytes2 = decode("3000")
-	//OU=a(Printable)+OU=aa(Printable)
-	var dnbytes3 = decode("301731153008060355040B1301613009060355040B13026161")
-	//1.2.3.4=a(Printable)
-	var dnbytes4 = decode("300C310A300806032A0304130161")
+func TestParseDERDNFromTagged(t *testing.T) {
+	cn := DN{RDN{AttributeTypeAndValue{Type: CommonName, Value: AttributeValue{UTF8String, "Test"}}}}
 	type args struct {
-		dn DN
+		b     []byte
+		class int
+		tag   int
 	}
-
 	tests := []struct {
-		name        string
-		args        args
-		wantDnBytes []byte
-		wantErr     bool
+		name    string
+		args    args
+		want    DN
+		wantErr bool
 	}{
-		{"TestCase:C=JP,O=example,OU=Ext,OU=Dev+OU=Sales,CN=ex+E=ex@example.com", args{dn1}, dnbytes1, false},
-		{"TestCase:Generic C=JP,O=example,OU=Ext,OU=Dev+OU=Sales,CN=ex+E=ex@example.com", args{dn3}, dnbytes1, false},
-		{"TestCase:OU=a+OU=aa ", args{dn4}, dnbytes3, false},
-		{"TestCase:Generic 1.2.3.4=a ", args{dn5}, dnbytes4, false},
-		{"TestCase: Empty DN", args{DN{}}, dnbytes2, false},
-		{"TestCase: Invalid AttributeType DN", args{dn2}, nil, true},
+		{"TestCase: explicit context [0]", args{[]byte{0xa0, 0x11, 0x30, 0x0f, 0x31, 0x0d, 0x30, 0x0b, 0x06, 0x03, 0x55, 0x04, 0x03, 0x0c, 0x04, 0x54, 0x65, 0x73, 0x74}, asn1.ClassContextSpecific, 0}, cn, false},
+		{"TestCase: implicit context [0]", args{[]byte{0xa0, 0x0f, 0x31, 0x0d, 0x30, 0x0b, 0x06, 0x03, 0x55, 0x04, 0x03, 0x0c, 0x04, 0x54, 0x65, 0x73, 0x74}, asn1.ClassContextSpecific, 0}, cn, false},
+		{"TestCase: explicit context [4]", args{[]byte{0xa4, 0x11, 0x30, 0x0f, 0x31, 0x0d, 0x30, 0x0b, 0x06, 0x03, 0x55, 0x04, 0x03, 0x0c, 0x04, 0x54, 0x65, 0x73, 0x74}, asn1.ClassContextSpecific, 4}, cn, false},
+		{"TestCase: explicit empty DN", args{[]byte{0xa0, 0x02, 0x30, 0x00}, asn1.ClassContextSpecific, 0}, DN{}, false},
+		{"TestCase: implicit empty DN", args{[]byte{0xa0, 0x00}, asn1.ClassContextSpecific, 0}, DN{}, false},
+		{"TestCase: unexpected tag", args{[]byte{0xa1, 0x11, 0x30, 0x0f, 0x31, 0x0d, 0x30, 0x0b, 0x06, 0x03, 0x55, 0x04, 0x03, 0x0c, 0x04, 0x54, 0x65, 0x73, 0x74}, asn1.ClassContextSpecific, 0}, nil, true},
+		{"TestCase: unexpected class", args{[]byte{0xa0, 0x11, 0x30, 0x0f, 0x31, 0x0d, 0x30, 0x0b, 0x06, 0x03, 0x55, 0x04, 0x03, 0x0c, 0x04, 0x54, 0x65, 0x73, 0x74}, asn1.ClassApplication, 0}, nil, true},
+		{"TestCase: trailing data", args{[]byte{0xa0, 0x02, 0x30, 0x00, 0x00}, asn1.ClassContextSpecific, 0}, nil, true},
+		{"TestCase: invalid inner DN", args{[]byte{0xa0, 0x03, 0x30, 0x01, 0x31}, asn1.ClassContextSpecific, 0}, nil, true},
+		{"TestCase: invalid DER", args{[]byte{0xa0, 0x05}, asn1.ClassContextSpecific, 0}, nil, true},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			gotDnBytes, err := MarshalDN(tt.args.dn)
+			got, err := ParseDERDNFromTagged(tt.args.b, tt.args.class, tt.args.tag)
 			if (err != nil) != tt.wantErr {
-				t.Errorf("MarshalDN() error = %v, wantErr %v", err, tt.wantErr)
+				t.Errorf("ParseDERDNFromTagged() error = %v, wantErr %v", err, tt.wantErr)
 				return
 			}
-			if !reflect.DeepEqual(gotDnBytes, tt.wantDnBytes) {
-				t.Errorf("MarshalDN() gotDnBytes = %v, want %v", gotDnBytes, tt.wantDnBytes)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseDERDNFromTagged() got = %v, want %v", got, tt.want)
 			}
 		})
 	}
 }
 
-func TestMarshalDNToParseDERDn(t *testing.T) {
-	var inDn = DN{
-		RDN{
-			AttributeTypeAndValue{Type: OrganizationalUnit, Value: AttributeValue{Encoding: UTF8String, Value: "a2"}},
-			AttributeTypeAndValue{Type: OrganizationalUnit, Value: AttributeValue{Encoding: UTF8String, Value: "a1"}},
-			AttributeTypeAndValue{Type: Generic, Oid: "1.2.3.4", Value: AttributeValue{Encoding: UTF8String, Value: "g1"}},
-		},
+func TestFromOIDTriples(t *testing.T) {
+	type args struct {
+		triples []OIDTriple
 	}
-	//AttributeTypeAndValues of the dn are Binary sorted.
-	var expectedDn = DN{
-		RDN{
-			AttributeTypeAndValue{Type: Generic, Oid: "1.2.3.4", Value: AttributeValue{Encoding: UTF8String, Value: "g1"}},
-			AttributeTypeAndValue{Type: OrganizationalUnit, Value: AttributeValue{Encoding: UTF8String, Value: "a1"}},
-			AttributeTypeAndValue{Type: OrganizationalUnit, Value: AttributeValue{Encoding: UTF8String, Value: "a2"}},
-		},
+	tests := []struct {
+		name    string
+		args    args
+		wantDn  DN
+		wantErr bool
+	}{
+		{"TestCase: empty", args{[]OIDTriple{}}, DN{}, false},
+		{"TestCase: known oids", args{[]OIDTriple{{"2.5.4.6", PrintableString, "JP"}, {"2.5.4.3", UTF8String, "Mike"}}},
+			DN{
+				RDN{AttributeTypeAndValue{Type: CountryName, Value: AttributeValue{PrintableString, "JP"}}},
+				RDN{AttributeTypeAndValue{Type: CommonName, Value: AttributeValue{UTF8String, "Mike"}}},
+			}, false},
+		{"TestCase: unknown oid", args{[]OIDTriple{{"1.2.3.4", IA5String, "x"}}},
+			DN{RDN{AttributeTypeAndValue{Type: Generic, Oid: "1.2.3.4", Value: AttributeValue{IA5String, "x"}}}}, false},
+		{"TestCase: invalid oid", args{[]OIDTriple{{"1.a", UTF8String, "x"}}}, nil, true},
+		{"TestCase: not supported encoding", args{[]OIDTriple{{"1.2.3.4", Encoding(99), "x"}}}, nil, true},
+		{"TestCase: invalid encoding for known oid", args{[]OIDTriple{{"2.5.4.6", UTF8String, "JP"}}}, nil, true},
 	}
-	marshaledDn, _ := MarshalDN(inDn)
-	parsedDn, _ := ParseDERDN(marshaledDn)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotDn, err := FromOIDTriples(tt.args.triples)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("FromOIDTriples() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(gotDn, tt.wantDn) {
+				t.Errorf("FromOIDTriples() gotDn = %v, want %v", gotDn, tt.wantDn)
+			}
+		})
+	}
+}
 
-	if !reflect.DeepEqual(parsedDn, expectedDn) {
-		t.Errorf("ReParseDERDn = %v, want %v", parsedDn, expectedDn)
+func Test_newAttributeTypeAndValue(t *testing.T) {
+	type args struct {
+		at    AttributeType
+		enc   Encoding
+		value string
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    AttributeTypeAndValue
+		wantErr bool
+	}{
+		{"TestCase: CommonName UTF8String", args{CommonName, UTF8String, "abc"}, AttributeTypeAndValue{Type: CommonName, Value: AttributeValue{UTF8String, "abc"}}, false},
+		{"TestCase: CommonName IA5String", args{CommonName, IA5String, "abc"}, AttributeTypeAndValue{}, true},
+		{"TestCase: CommonName of 64 characters", args{CommonName, UTF8String, strings.Repeat("a", 64)}, AttributeTypeAndValue{Type: CommonName, Value: AttributeValue{UTF8String, strings.Repeat("a", 64)}}, false},
+		{"TestCase: CommonName of 65 characters", args{CommonName, UTF8String, strings.Repeat("a", 65)}, AttributeTypeAndValue{}, true},
+		{"TestCase: Generic without oid", args{Generic, UTF8String, "abc"}, AttributeTypeAndValue{}, true},
+		{"TestCase: not supported AttributeType", args{AttributeType(0), UTF8String, "abc"}, AttributeTypeAndValue{}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := newAttributeTypeAndValue(tt.args.at, tt.args.enc, tt.args.value)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("newAttributeTypeAndValue() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("newAttributeTypeAndValue() got = %v, want %v", got, tt.want)
+			}
+		})
 	}
+}
 
+func TestTypedConstructors(t *testing.T) {
+	tests := []struct {
+		name    string
+		fn      func() (AttributeTypeAndValue, error)
+		want    AttributeTypeAndValue
+		wantErr bool
+	}{
+		{"TestCase: C", func() (AttributeTypeAndValue, error) { return C("JP") }, AttributeTypeAndValue{Type: CountryName, Value: AttributeValue{PrintableString, "JP"}}, false},
+		{"TestCase: C three letters", func() (AttributeTypeAndValue, error) { return C("JPN") }, AttributeTypeAndValue{}, true},
+		{"TestCase: C digits", func() (AttributeTypeAndValue, error) { return C("12") }, AttributeTypeAndValue{}, true},
+		{"TestCase: C blank", func() (AttributeTypeAndValue, error) { return C("") }, AttributeTypeAndValue{}, true},
+		{"TestCase: CN", func() (AttributeTypeAndValue, error) { return CN(UTF8String, "abc") }, AttributeTypeAndValue{Type: CommonName, Value: AttributeValue{UTF8String, "abc"}}, false},
+		{"TestCase: CN IA5String", func() (AttributeTypeAndValue, error) { return CN(IA5String, "abc") }, AttributeTypeAndValue{}, true},
+		{"TestCase: O", func() (AttributeTypeAndValue, error) { return O(PrintableString, "abc") }, AttributeTypeAndValue{Type: OrganizationName, Value: AttributeValue{PrintableString, "abc"}}, false},
+		{"TestCase: O OIDValue", func() (AttributeTypeAndValue, error) { return O(OIDValue, "1.2.3") }, AttributeTypeAndValue{}, true},
+		{"TestCase: OU", func() (AttributeTypeAndValue, error) { return OU(UTF8String, "abc") }, AttributeTypeAndValue{Type: OrganizationalUnit, Value: AttributeValue{UTF8String, "abc"}}, false},
+		{"TestCase: ST", func() (AttributeTypeAndValue, error) { return ST(UTF8String, "Tokyo") }, AttributeTypeAndValue{Type: StateOrProvinceName, Value: AttributeValue{UTF8String, "Tokyo"}}, false},
+		{"TestCase: L", func() (AttributeTypeAndValue, error) { return L(PrintableString, "Chiyoda") }, AttributeTypeAndValue{Type: LocalityName, Value: AttributeValue{PrintableString, "Chiyoda"}}, false},
+		{"TestCase: DC", func() (AttributeTypeAndValue, error) { return DC("example") }, AttributeTypeAndValue{Type: DomainComponent, Value: AttributeValue{IA5String, "example"}}, false},
+		{"TestCase: E", func() (AttributeTypeAndValue, error) { return E("a@example.com") }, AttributeTypeAndValue{Type: ElectronicMailAddress, Value: AttributeValue{IA5String, "a@example.com"}}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.fn()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got = %v, want %v", got, tt.want)
+			}
+		})
+	}
 }
 
-func Test_isValidAttributeType(t *testing.T) {
-	type args struct {
-		at AttributeType
+func TestTrimValues(t *testing.T) {
+	defer func(v bool) { TrimValues = v }(TrimValues)
+	foo := func(v string) AttributeTypeAndValue {
+		return AttributeTypeAndValue{Type: CommonName, Value: AttributeValue{UTF8String, v}}
 	}
 	tests := []struct {
-		name        string
-		args        args
-		wantIsValid bool
-		wantErr     bool
+		name       string
+		trimValues bool
+		fn         func() (AttributeTypeAndValue, error)
+		want       AttributeTypeAndValue
+		wantErr    bool
 	}{
-		{"TestCase: CountryName", args{CountryName}, true, false},
-		{"TestCase: OrganizationName", args{OrganizationName}, true, false},
-		{"TestCase: OrganizationalUnit", args{OrganizationalUnit}, true, false},
-		{"TestCase: DnQualifier", args{DnQualifier}, true, false},
-		{"TestCase: StateOrProvinceName", args{StateOrProvinceName}, true, false},
-		{"TestCase: CommonName", args{CommonName}, true, false},
-		{"TestCase: SerialNumber", args{SerialNumber}, true, false},
-		{"TestCase: LocalityName", args{LocalityName}, true, false},
-		{"TestCase: Title", args{Title}, true, false},
-		{"TestCase: Surname", args{Surname}, true, false},
-		{"TestCase: GivenName", args{GivenName}, true, false},
-		{"TestCase: Initials", args{Initials}, true, false},
-		{"TestCase: Pseudonym", args{Pseudonym}, true, false},
-		{"TestCase: GenerationQualifier", args{GenerationQualifier}, true, false},
-		{"TestCase: ElectronicMailAddress", args{ElectronicMailAddress}, true, false},
-		{"TestCase: DomainComponent", args{DomainComponent}, true, false},
-		{"TestCase: the other", args{999}, false, true},
+		{"TestCase: CN policy disabled", false, func() (AttributeTypeAndValue, error) { return CN(UTF8String, "  Foo  ") }, foo("  Foo  "), false},
+		{"TestCase: CN policy enabled", true, func() (AttributeTypeAndValue, error) { return CN(UTF8String, "  Foo  ") }, foo("Foo"), false},
+		{"TestCase: CN policy enabled with tab and newline", true, func() (AttributeTypeAndValue, error) { return CN(UTF8String, "\tFoo Bar\n") }, foo("Foo Bar"), false},
+		{"TestCase: C policy disabled", false, func() (AttributeTypeAndValue, error) { return C(" JP ") }, AttributeTypeAndValue{}, true},
+		{"TestCase: C policy enabled", true, func() (AttributeTypeAndValue, error) { return C(" JP ") }, AttributeTypeAndValue{Type: CountryName, Value: AttributeValue{PrintableString, "JP"}}, false},
+		{"TestCase: AttributeFromOID policy disabled", false, func() (AttributeTypeAndValue, error) { return AttributeFromOID("2.5.4.3", UTF8String, "  Foo  ") }, foo("  Foo  "), false},
+		{"TestCase: AttributeFromOID policy enabled", true, func() (AttributeTypeAndValue, error) { return AttributeFromOID("2.5.4.3", UTF8String, "  Foo  ") }, foo("Foo"), false},
+		{"TestCase: AttributeFromOID Generic policy enabled", true, func() (AttributeTypeAndValue, error) { return AttributeFromOID("1.2.3", UTF8String, "  Foo  ") },
+			AttributeTypeAndValue{Type: Generic, Oid: "1.2.3", Value: AttributeValue{UTF8String, "Foo"}}, false},
+		{"TestCase: FromOIDTriples policy disabled", false, func() (AttributeTypeAndValue, error) {
+			dn, err := FromOIDTriples([]OIDTriple{{"2.5.4.3", UTF8String, "  Foo  "}})
+			if err != nil {
+				return AttributeTypeAndValue{}, err
+			}
+			return dn[0][0], nil
+		}, foo("  Foo  "), false},
+		{"TestCase: FromOIDTriples policy enabled", true, func() (AttributeTypeAndValue, error) {
+			dn, err := FromOIDTriples([]OIDTriple{{"2.5.4.3", UTF8String, "  Foo  "}})
+			if err != nil {
+				return AttributeTypeAndValue{}, err
+			}
+			return dn[0][0], nil
+		}, foo("Foo"), false},
+		{"TestCase: ParseDERDN policy enabled", true, func() (AttributeTypeAndValue, error) {
+			dn, err := ParseDERDN(decode("30123110300e06035504030c072020466f6f2020"))
+			if err != nil {
+				return AttributeTypeAndValue{}, err
+			}
+			return dn[0][0], nil
+		}, foo("  Foo  "), false},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			gotIsValid, err := isValidAttributeType(tt.args.at)
+			TrimValues = tt.trimValues
+			got, err := tt.fn()
 			if (err != nil) != tt.wantErr {
-				t.Errorf("isValidAttributeType() error = %v, wantErr %v", err, tt.wantErr)
+				t.Errorf("error = %v, wantErr %v", err, tt.wantErr)
 				return
 			}
-			if gotIsValid != tt.wantIsValid {
-				t.Errorf("isValidAttributeType() gotIsValid = %v, want %v", gotIsValid, tt.wantIsValid)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got = %v, want %v", got, tt.want)
 			}
 		})
 	}
 }
 
-func Test_isValidAttributeTypeAndAttributeValueComb(t *testing.T) {
+func TestAttributeFromOID(t *testing.T) {
 	type args struct {
-		at AttributeType
-		av AttributeValue
+		oid   string
+		enc   Encoding
+		value string
 	}
 	tests := []struct {
-		name        string
-		args        args
-		wantIsValid bool
-		wantErr     bool
+		name    string
+		args    args
+		want    AttributeTypeAndValue
+		wantErr bool
 	}{
-		{"TestCase: CountryName, PrintableString", args{CountryName, AttributeValue{Encoding: PrintableString}}, true, false},
-		{"TestCase: CountryName, the other", args{CountryName, AttributeValue{Encoding: UTF8String}}, false, true},
-
-		{"TestCase: OrganizationName, PrintableString", args{OrganizationName, AttributeValue{Encoding: PrintableString}}, true, false},
-		{"TestCase: OrganizationName, UTF8String", args{OrganizationName, AttributeValue{Encoding: UTF8String}}, true, false},
-		{"TestCase: OrganizationName, the other", args{OrganizationName, AttributeValue{Encoding: IA5String}}, false, true},
-
-		{"TestCase: OrganizationalUnit, PrintableString", args{OrganizationalUnit, AttributeValue{Encoding: PrintableString}}, true, false},
-		{"TestCase: OrganizationalUnit, UTF8String", args{OrganizationalUnit, AttributeValue{Encoding: UTF8String}}, true, false},
-		{"TestCase: OrganizationalUnit, the other", args{OrganizationalUnit, AttributeValue{Encoding: IA5String}}, false, true},
-
-		{"TestCase: DnQualifier, PrintableString", args{DnQualifier, AttributeValue{Encoding: PrintableString}}, true, false},
-		{"TestCase: DnQualifier, the other", args{DnQualifier, AttributeValue{Encoding: UTF8String}}, false, true},
-
-		{"TestCase: StateOrProvinceName, PrintableString", args{StateOrProvinceName, AttributeValue{Encoding: PrintableString}}, true, false},
-		{"TestCase: StateOrProvinceName, UTF8String", args{StateOrProvinceName, AttributeValue{Encoding: UTF8String}}, true, false},
-		{"TestCase: StateOrProvinceName, the other", args{StateOrProvinceName, AttributeValue{Encoding: IA5String}}, false, true},
-
-		{"TestCase: CommonNam, PrintableString", args{CommonName, AttributeValue{Encoding: PrintableString}}, true, false},
-		{"TestCase: CommonName, UTF8String", args{CommonName, AttributeValue{Encoding: UTF8String}}, true, false},
-		{"TestCase: CommonName, the other", args{CommonName, AttributeValue{Encoding: IA5String}}, false, true},
-
-		{"TestCase: SerialNumber, PrintableString", args{SerialNumber, AttributeValue{Encoding: PrintableString}}, true, false},
-		{"TestCase: SerialNumber, the other", args{SerialNumber, AttributeValue{Encoding: UTF8String}}, false, true},
-
-		{"TestCase: LocalityName, PrintableString", args{LocalityName, AttributeValue{Encoding: PrintableString}}, true, false},
-		{"TestCase: LocalityName, UTF8String", args{LocalityName, AttributeValue{Encoding: UTF8String}}, true, false},
-		{"TestCase: LocalityName, the other", args{LocalityName, AttributeValue{Encoding: IA5String}}, false, true},
-
-		{"TestCase: Title, PrintableString", args{Title, AttributeValue{Encoding: PrintableString}}, true, false},
-		{"TestCase: Title, UTF8String", args{Title, AttributeValue{Encoding: UTF8String}}, true, false},
-		{"TestCase: Title, the other", args{Title, AttributeValue{Encoding: IA5String}}, false, true},
-
-		{"TestCase: Surname, PrintableString", args{Surname, AttributeValue{Encoding: PrintableString}}, true, false},
-		{"TestCase: Surname, UTF8String", args{Surname, AttributeValue{Encoding: UTF8String}}, true, false},
-		{"TestCase: Surname, the other", args{Surname, AttributeValue{Encoding: IA5String}}, false, true},
-
-		{"TestCase: GivenName, PrintableString", args{GivenName, AttributeValue{Encoding: PrintableString}}, true, false},
-		{"TestCase: GivenName, UTF8String", args{GivenName, AttributeValue{Encoding: UTF8String}}, true, false},
-		{"TestCase: GivenName, the other", args{GivenName, AttributeValue{Encoding: IA5String}}, false, true},
-
-		{"TestCase: Initials, PrintableString", args{Initials, AttributeValue{Encoding: PrintableString}}, true, false},
-		{"TestCase: Initials, UTF8String", args{Initials, AttributeValue{Encoding: UTF8String}}, true, false},
-		{"TestCase: Initials, the other", args{Initials, AttributeValue{Encoding: IA5String}}, false, true},
-
-		{"TestCase: Pseudonym, PrintableString", args{Pseudonym, AttributeValue{Encoding: PrintableString}}, true, false},
-		{"TestCase: Pseudonym, UTF8String", args{Pseudonym, AttributeValue{Encoding: UTF8String}}, true, false},
-		{"TestCase: Pseudonym, the other", args{Pseudonym, AttributeValue{Encoding: IA5String}}, false, true},
-
-		{"TestCase: GenerationQualifier, PrintableString", args{GenerationQualifier, AttributeValue{Encoding: PrintableString}}, true, false},
-		{"TestCase: GenerationQualifier, UTF8String", args{GenerationQualifier, AttributeValue{Encoding: UTF8String}}, true, false},
-		{"TestCase: GenerationQualifier, the other", args{GenerationQualifier, AttributeValue{Encoding: IA5String}}, false, true},
-
-		{"TestCase: ElectronicMailAddress, IA5String", args{ElectronicMailAddress, AttributeValue{Encoding: IA5String}}, true, false},
-		{"TestCase: ElectronicMailAddress, the other", args{ElectronicMailAddress, AttributeValue{Encoding: UTF8String}}, false, true},
-
-		{"TestCase: DomainComponent, IA5String", args{DomainComponent, AttributeValue{Encoding: IA5String}}, true, false},
-		{"TestCase: DomainComponent, the other", args{DomainComponent, AttributeValue{Encoding: UTF8String}}, false, true},
-
-		{"TestCase: Generic, IA5String", args{Generic, AttributeValue{Encoding: IA5String}}, true, false},
-		{"TestCase: Generic, UTF8String", args{Generic, AttributeValue{Encoding: UTF8String}}, true, false},
-		{"TestCase: Generic, PrintableString", args{Generic, AttributeValue{Encoding: PrintableString}}, true, false},
-		{"TestCase: Generic, the other", args{Generic, AttributeValue{Encoding: 999}}, false, true},
-
-		{"TestCase: UnKnown, UTF8String", args{999, AttributeValue{Encoding: UTF8String}}, false, true},
+		{"TestCase: CommonName oid", args{"2.5.4.3", UTF8String, "abc"}, AttributeTypeAndValue{Type: CommonName, Value: AttributeValue{UTF8String, "abc"}}, false},
+		{"TestCase: CountryName oid", args{"2.5.4.6", PrintableString, "JP"}, AttributeTypeAndValue{Type: CountryName, Value: AttributeValue{PrintableString, "JP"}}, false},
+		{"TestCase: DomainComponent oid", args{"0.9.2342.19200300.100.1.25", IA5String, "example"}, AttributeTypeAndValue{Type: DomainComponent, Value: AttributeValue{IA5String, "example"}}, false},
+		{"TestCase: CountryName oid with UTF8String", args{"2.5.4.6", UTF8String, "JP"}, AttributeTypeAndValue{}, true},
+		{"TestCase: unknown oid", args{"1.2.3.4", UTF8String, "abc"}, AttributeTypeAndValue{Type: Generic, Oid: "1.2.3.4", Value: AttributeValue{UTF8String, "abc"}}, false},
+		{"TestCase: unknown oid with OIDValue", args{"1.2.3.4", OIDValue, "1.2.3"}, AttributeTypeAndValue{Type: Generic, Oid: "1.2.3.4", Value: AttributeValue{OIDValue, "1.2.3"}}, false},
+		{"TestCase: unknown oid with not supported encoding", args{"1.2.3.4", Encoding(999), "abc"}, AttributeTypeAndValue{}, true},
+		{"TestCase: invalid oid", args{"a.b", UTF8String, "abc"}, AttributeTypeAndValue{}, true},
+		{"TestCase: blank oid", args{"", UTF8String, "abc"}, AttributeTypeAndValue{}, true},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			gotIsValid, err := isValidAttributeTypeAndAttributeValueComb(tt.args.at, tt.args.av)
+			got, err := AttributeFromOID(tt.args.oid, tt.args.enc, tt.args.value)
 			if (err != nil) != tt.wantErr {
-				t.Errorf("isValidAttributeTypeAndAttributeValueComb() error = %v, wantErr %v", err, tt.wantErr)
+				t.Errorf("AttributeFromOID() error = %v, wantErr %v", err, tt.wantErr)
 				return
 			}
-			if gotIsValid != tt.wantIsValid {
-				t.Errorf("isValidAttributeTypeAndAttributeValueComb() gotIsValid = %v, want %v", gotIsValid, tt.wantIsValid)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("AttributeFromOID() got = %v, want %v", got, tt.want)
 			}
 		})
 	}
 }
 
-func Test_isPrintableStringEncoding(t *testing.T) {
+func Test_isAlpha(t *testing.T) {
+	tests := []struct {
+		name string
+		c    byte
+		want bool
+	}{
+		{"TestCase: a", 'a', true},
+		{"TestCase: Z", 'Z', true},
+		{"TestCase: 0", '0', false},
+		{"TestCase: @", '@', false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isAlpha(tt.c); got != tt.want {
+				t.Errorf("isAlpha() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMustParseDERDN(t *testing.T) {
 	type args struct {
-		e Encoding
+		dnBytes []byte
 	}
 	tests := []struct {
-		name   string
-		args   args
-		wantOk bool
+		name      string
+		args      args
+		want      DN
+		wantPanic bool
 	}{
-		{"TestCase: PrintableString", args{PrintableString}, true},
-		{"TestCase: UTF8String", args{UTF8String}, false},
-		{"TestCase: IA5String", args{IA5String}, false},
-		{"TestCase: The other", args{9999}, false},
+		{"TestCase: CN=Test", args{[]byte{0x30, 0x0f, 0x31, 0x0d, 0x30, 0x0b, 0x06, 0x03, 0x55, 0x04, 0x03, 0x0c, 0x04, 0x54, 0x65, 0x73, 0x74}}, DN{RDN{AttributeTypeAndValue{Type: CommonName, Value: AttributeValue{UTF8String, "Test"}}}}, false},
+		{"TestCase: invalid DER", args{[]byte{0x30, 0x01}}, nil, true},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if gotOk := isPrintableStringEncoding(tt.args.e); gotOk != tt.wantOk {
-				t.Errorf("isPrintableStringEncoding() = %v, want %v", gotOk, tt.wantOk)
+			defer func() {
+				if r := recover(); (r != nil) != tt.wantPanic {
+					t.Errorf("MustParseDERDN() panic = %v, wantPanic %v", r, tt.wantPanic)
+				}
+			}()
+			if got := MustParseDERDN(tt.args.dnBytes); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("MustParseDERDN() = %v, want %v", got, tt.want)
 			}
 		})
 	}
 }
 
-func Test_isIA5StringEncoding(t *testing.T) {
+func TestMustMarshalDN(t *testing.T) {
 	type args struct {
-		e Encoding
+		dn DN
+	}
+	tests := []struct {
+		name      string
+		args      args
+		want      []byte
+		wantPanic bool
+	}{
+		{"TestCase: CN=Test", args{DN{RDN{AttributeTypeAndValue{Type: CommonName, Value: AttributeValue{UTF8String, "Test"}}}}}, []byte{0x30, 0x0f, 0x31, 0x0d, 0x30, 0x0b, 0x06, 0x03, 0x55, 0x04, 0x03, 0x0c, 0x04, 0x54, 0x65, 0x73, 0x74}, false},
+		{"TestCase: empty RDN", args{DN{RDN{}}}, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer func() {
+				if r := recover(); (r != nil) != tt.wantPanic {
+					t.Errorf("MustMarshalDN() panic = %v, wantPanic %v", r, tt.wantPanic)
+				}
+			}()
+			if got := MustMarshalDN(tt.args.dn); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("MustMarshalDN() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseDERDN_LongFormLength(t *testing.T) {
+	o := strings.Repeat("a", 200)
+	//SEQUENCE(214) SET(211) SEQUENCE(208) OBJECT IDENTIFIER 2.5.4.10 UTF8String(200)
+	dnBytes := decode("3081d6" + "3181d3" + "3081d0" + "060355040a" + "0c81c8" + hex.EncodeToString([]byte(o)))
+	want := DN{RDN{AttributeTypeAndValue{Type: OrganizationName, Value: AttributeValue{UTF8String, o}}}}
+
+	dn, err := ParseDERDN(dnBytes)
+	if err != nil {
+		t.Fatalf("ParseDERDN() error = %v", err)
+	}
+	if !reflect.DeepEqual(dn, want) {
+		t.Errorf("ParseDERDN() = %v, want %v", dn, want)
+	}
+	b, err := MarshalDN(dn)
+	if err != nil {
+		t.Fatalf("MarshalDN() error = %v", err)
+	}
+	if !reflect.DeepEqual(b, dnBytes) {
+		t.Errorf("MarshalDN() = %x, want %x", b, dnBytes)
+	}
+	if b, err := CanonicalizeDERDN(dnBytes); err != nil || !reflect.DeepEqual(b, dnBytes) {
+		t.Errorf("CanonicalizeDERDN() = %x, %v, want %x", b, err, dnBytes)
+	}
+	//[4] IMPLICIT
+	implicit := append(decode("a481d6"), dnBytes[3:]...)
+	if dn, err := ParseDERDNFromTagged(implicit, asn1.ClassContextSpecific, 4); err != nil || !reflect.DeepEqual(dn, want) {
+		t.Errorf("ParseDERDNFromTagged() = %v, %v, want %v", dn, err, want)
+	}
+	//[4] EXPLICIT
+	explicit := append(decode("a481d9"), dnBytes...)
+	if dn, err := ParseDERDNFromTagged(explicit, asn1.ClassContextSpecific, 4); err != nil || !reflect.DeepEqual(dn, want) {
+		t.Errorf("ParseDERDNFromTagged() = %v, %v, want %v", dn, err, want)
 	}
+}
+
+func TestMarshalDNOpts_Length(t *testing.T) {
+	c := RDN{AttributeTypeAndValue{Type: CountryName, Value: AttributeValue{PrintableString, "JP"}}}
+	longCN := RDN{AttributeTypeAndValue{Type: CommonName, Value: AttributeValue{UTF8String, strings.Repeat("a", 65)}}}
+	longGenericCN := RDN{AttributeTypeAndValue{Type: Generic, Oid: "2.5.4.3", Value: AttributeValue{UTF8String, strings.Repeat("a", 65)}}}
+	maxCN := RDN{AttributeTypeAndValue{Type: CommonName, Value: AttributeValue{UTF8String, strings.Repeat("日", 64)}}}
+	tests := []struct {
+		name    string
+		dn      DN
+		opts    []MarshalOption
+		wantErr bool
+	}{
+		{"TestCase: CommonName of 65 characters without strict validation", DN{c, longCN}, nil, false},
+		{"TestCase: CommonName of 65 characters with strict validation", DN{c, longCN}, []MarshalOption{WithStrictValidation()}, true},
+		{"TestCase: Generic CommonName of 65 characters with strict validation", DN{c, longGenericCN}, []MarshalOption{WithStrictValidation()}, true},
+		{"TestCase: CommonName of 64 characters with strict validation", DN{c, maxCN}, []MarshalOption{WithStrictValidation()}, false},
+		{"TestCase: CountryName of 3 characters with strict validation", DN{RDN{AttributeTypeAndValue{Type: CountryName, Value: AttributeValue{PrintableString, "JPN"}}}}, []MarshalOption{WithStrictValidation()}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := MarshalDNOpts(tt.dn, tt.opts...); (err != nil) != tt.wantErr {
+				t.Errorf("MarshalDNOpts() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestMarshalDNOpts_EmptyValue(t *testing.T) {
+	c := RDN{AttributeTypeAndValue{Type: CountryName, Value: AttributeValue{PrintableString, "JP"}}}
+	emptyCN := RDN{AttributeTypeAndValue{Type: CommonName, Value: AttributeValue{UTF8String, ""}}}
+	emptyGenericCN := RDN{AttributeTypeAndValue{Type: Generic, Oid: "2.5.4.3", Value: AttributeValue{UTF8String, ""}}}
+	emptyOU := RDN{AttributeTypeAndValue{Type: OrganizationalUnit, Value: AttributeValue{UTF8String, ""}}}
+	emptyEmail := RDN{AttributeTypeAndValue{Type: ElectronicMailAddress, Value: AttributeValue{IA5String, ""}}}
+	tests := []struct {
+		name    string
+		dn      DN
+		opts    []MarshalOption
+		wantErr bool
+	}{
+		{"TestCase: empty CommonName without strict validation", DN{c, emptyCN}, nil, false},
+		{"TestCase: empty CommonName with strict validation", DN{c, emptyCN}, []MarshalOption{WithStrictValidation()}, true},
+		{"TestCase: empty Generic CommonName with strict validation", DN{c, emptyGenericCN}, []MarshalOption{WithStrictValidation()}, true},
+		{"TestCase: empty OrganizationalUnit with strict validation", DN{c, emptyOU}, []MarshalOption{WithStrictValidation()}, true},
+		{"TestCase: empty OrganizationalUnit allowed by policy", DN{c, emptyOU}, []MarshalOption{WithStrictValidation(), WithEmptyValueAllowed(OrganizationalUnit)}, false},
+		{"TestCase: empty CommonName not allowed by policy", DN{c, emptyCN, emptyOU}, []MarshalOption{WithStrictValidation(), WithEmptyValueAllowed(OrganizationalUnit)}, true},
+		{"TestCase: empty ElectronicMailAddress is not DirectoryString", DN{c, emptyEmail}, []MarshalOption{WithStrictValidation()}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := MarshalDNOpts(tt.dn, tt.opts...); (err != nil) != tt.wantErr {
+				t.Errorf("MarshalDNOpts() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_findControlCharacter(t *testing.T) {
 	tests := []struct {
 		name   string
-		args   args
+		av     AttributeValue
+		want   rune
 		wantOk bool
 	}{
-		{"TestCase: IA5String", args{IA5String}, true},
-		{"TestCase: UTF8String", args{UTF8String}, false},
-		{"TestCase: The other", args{9999}, false},
+		{"TestCase: no control character", AttributeValue{UTF8String, "abc あ"}, 0, false},
+		{"TestCase: NUL", AttributeValue{UTF8String, "a\x00b"}, 0x00, true},
+		{"TestCase: BEL in IA5String", AttributeValue{IA5String, "a\x07b"}, 0x07, true},
+		{"TestCase: LF", AttributeValue{UTF8String, "a\nb"}, 0x0A, true},
+		{"TestCase: DEL", AttributeValue{PrintableString, "a\x7fb"}, 0x7F, true},
+		{"TestCase: C1 NEL", AttributeValue{UTF8String, "a\u0085b"}, 0x85, true},
+		{"TestCase: U+00A0 is not a control character", AttributeValue{UTF8String, "a\u00a0b"}, 0, false},
+		{"TestCase: OIDValue", AttributeValue{OIDValue, "1.2.3"}, 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := findControlCharacter(tt.av)
+			if got != tt.want || ok != tt.wantOk {
+				t.Errorf("findControlCharacter() = %U, %v, want %U, %v", got, ok, tt.want, tt.wantOk)
+			}
+		})
+	}
+}
+
+func TestMarshalDNOpts_ControlCharacter(t *testing.T) {
+	dn := DN{RDN{AttributeTypeAndValue{Type: CommonName, Value: AttributeValue{UTF8String, "admin\x1b[31m"}}}}
+	if _, err := MarshalDNOpts(dn); err != nil {
+		t.Errorf("MarshalDNOpts() error = %v, want nil", err)
+	}
+	_, err := MarshalDNOpts(dn, WithStrictValidation())
+	if err == nil || !strings.Contains(err.Error(), "control character U+001B") {
+		t.Errorf("MarshalDNOpts() with WithStrictValidation error = %v, want control character U+001B error", err)
+	}
+	//relaxed parsing of existing certificates
+	got, err := ParseDERDN(MustMarshalDN(dn))
+	if err != nil || !reflect.DeepEqual(got, dn) {
+		t.Errorf("ParseDERDN() = %v, %v, want %v", got, err, dn)
+	}
+}
+
+func TestRegisterValueValidator(t *testing.T) {
+	deviceSerial := func(v string) error {
+		if !strings.HasPrefix(v, "PID:") || !strings.Contains(v, " SN:") {
+			return fmt.Errorf("%s is not in PID:... SN:... format", v)
+		}
+		return nil
+	}
+	RegisterValueValidator(SerialNumber, deviceSerial)
+	defer RegisterValueValidator(SerialNumber, nil)
+
+	cn := AttributeTypeAndValue{Type: CommonName, Value: AttributeValue{UTF8String, "device"}}
+	tests := []struct {
+		name    string
+		dn      DN
+		opts    []MarshalOption
+		wantErr bool
+	}{
+		{"TestCase: valid serialNumber", DN{RDN{cn}, RDN{AttributeTypeAndValue{Type: SerialNumber, Value: AttributeValue{PrintableString, "PID:ABC SN:123"}}}}, []MarshalOption{WithStrictValidation()}, false},
+		{"TestCase: invalid serialNumber", DN{RDN{cn}, RDN{AttributeTypeAndValue{Type: SerialNumber, Value: AttributeValue{PrintableString, "123"}}}}, []MarshalOption{WithStrictValidation()}, true},
+		{"TestCase: invalid serialNumber as Generic", DN{RDN{AttributeTypeAndValue{Type: Generic, Oid: "2.5.4.5", Value: AttributeValue{PrintableString, "123"}}}}, []MarshalOption{WithStrictValidation()}, true},
+		{"TestCase: invalid serialNumber without strict validation", DN{RDN{AttributeTypeAndValue{Type: SerialNumber, Value: AttributeValue{PrintableString, "123"}}}}, nil, false},
+		{"TestCase: no validator", DN{RDN{cn}}, []MarshalOption{WithStrictValidation()}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := MarshalDNOpts(tt.dn, tt.opts...); (err != nil) != tt.wantErr {
+				t.Errorf("MarshalDNOpts() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	RegisterValueValidator(SerialNumber, nil)
+	if fn := valueValidator(SerialNumber); fn != nil {
+		t.Errorf("valueValidator() after registering nil = %p, want nil", fn)
+	}
+}
+
+func TestParseDirectoryName(t *testing.T) {
+	cnAbc := DN{RDN{AttributeTypeAndValue{Type: CommonName, Value: AttributeValue{Encoding: UTF8String, Value: "abc"}}}}
+	type args struct {
+		b []byte
+	}
+	tests := []struct {
+		name    string
+		args    args
+		wantDn  DN
+		wantErr bool
+	}{
+		{"TestCase: [4] EXPLICIT", args{decode("a410300e310c300a06035504030c03616263")}, cnAbc, false},
+		{"TestCase: [4] IMPLICIT", args{decode("a40e310c300a06035504030c03616263")}, cnAbc, false},
+		{"TestCase: empty DN", args{decode("a4023000")}, DN{}, false},
+		{"TestCase: [1] rfc822Name", args{decode("8103616263")}, nil, true},
+		{"TestCase: untagged DN", args{decode("300e310c300a06035504030c03616263")}, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotDn, err := ParseDirectoryName(tt.args.b)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseDirectoryName() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(gotDn, tt.wantDn) {
+				t.Errorf("ParseDirectoryName() gotDn = %v, want %v", gotDn, tt.wantDn)
+			}
+		})
+	}
+}
+
+func TestMarshalDirectoryName(t *testing.T) {
+	cnAbc := DN{RDN{AttributeTypeAndValue{Type: CommonName, Value: AttributeValue{Encoding: UTF8String, Value: "abc"}}}}
+	long := DN{RDN{AttributeTypeAndValue{Type: OrganizationName, Value: AttributeValue{Encoding: UTF8String, Value: strings.Repeat("a", 200)}}}}
+	tests := []struct {
+		name    string
+		dn      DN
+		want    []byte
+		wantErr bool
+	}{
+		{"TestCase: CN=abc", cnAbc, decode("a410300e310c300a06035504030c03616263"), false},
+		{"TestCase: empty DN", DN{}, decode("a4023000"), false},
+		{"TestCase: long form length", long, append(decode("a481d9"), MustMarshalDN(long)...), false},
+		{"TestCase: invalid DN", DN{RDN{}}, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := MarshalDirectoryName(tt.dn)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("MarshalDirectoryName() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("MarshalDirectoryName() got = %x, want %x", got, tt.want)
+			}
+			if err != nil {
+				return
+			}
+			back, err := ParseDirectoryName(got)
+			if err != nil || !reflect.DeepEqual(back, tt.dn) {
+				t.Errorf("ParseDirectoryName(MarshalDirectoryName()) = %v, %v, want %v", back, err, tt.dn)
+			}
+		})
+	}
+}
+
+func TestMarshalDN(t *testing.T) {
+	var dn1 = DN{
+		RDN{AttributeTypeAndValue{Type: CountryName, Value: AttributeValue{Encoding: PrintableString, Value: "JP"}}},
+		RDN{AttributeTypeAndValue{Type: OrganizationName, Value: AttributeValue{Encoding: UTF8String, Value: "example"}}},
+		RDN{AttributeTypeAndValue{Type: OrganizationalUnit, Value: AttributeValue{Encoding: UTF8String, Value: "Ext"}}},
+		RDN{
+			AttributeTypeAndValue{Type: OrganizationalUnit, Value: AttributeValue{Encoding: UTF8String, Value: "Dev"}},
+			AttributeTypeAndValue{Type: OrganizationalUnit, Value: AttributeValue{Encoding: UTF8String, Value: "Sales"}},
+		},
+		RDN{
+			AttributeTypeAndValue{Type: CommonName, Value: AttributeValue{Encoding: UTF8String, Value: "ex"}},
+			AttributeTypeAndValue{Type: ElectronicMailAddress, Value: AttributeValue{Encoding: IA5String, Value: "ex@example.com"}}},
+	}
+	var dn2 = DN{
+		RDN{AttributeTypeAndValue{Type: 999, Value: AttributeValue{Encoding: UTF8String, Value: "cn1"}}},
+	}
+	var dn3 = DN{
+		RDN{AttributeTypeAndValue{Type: Generic, Oid: "2.5.4.6", Value: AttributeValue{Encoding: PrintableString, Value: "JP"}}},
+		RDN{AttributeTypeAndValue{Type: Generic, Oid: "2.5.4.10", Value: AttributeValue{Encoding: UTF8String, Value: "example"}}},
+		RDN{AttributeTypeAndValue{Type: Generic, Oid: "2.5.4.11", Value: AttributeValue{Encoding: UTF8String, Value: "Ext"}}},
+		RDN{
+			AttributeTypeAndValue{Type: Generic, Oid: "2.5.4.11", Value: AttributeValue{Encoding: UTF8String, Value: "Dev"}},
+			AttributeTypeAndValue{Type: Generic, Oid: "2.5.4.11", Value: AttributeValue{Encoding: UTF8String, Value: "Sales"}},
+		},
+		RDN{
+			AttributeTypeAndValue{Type: Generic, Oid: "2.5.4.3", Value: AttributeValue{Encoding: UTF8String, Value: "ex"}},
+			AttributeTypeAndValue{Type: Generic, Oid: "1.2.840.113549.1.9.1", Value: AttributeValue{Encoding: IA5String, Value: "ex@example.com"}}},
+	}
+	var dn4 = DN{
+		RDN{
+			AttributeTypeAndValue{Type: OrganizationalUnit, Value: AttributeValue{Encoding: PrintableString, Value: "a"}},
+			AttributeTypeAndValue{Type: OrganizationalUnit, Value: AttributeValue{Encoding: PrintableString, Value: "aa"}},
+		},
+	}
+	var dn5 = DN{
+		RDN{AttributeTypeAndValue{Type: Generic, Oid: "1.2.3.4", Value: AttributeValue{Encoding: PrintableString, Value: "a"}}},
+	}
+	//C=JP,O=example,OU=Ext,OU=Dev+OU=Sales,CN=ex+E=ex@example.com
+	var dnbytes1 = decode("3073310b3009060355040613024a503110300e060355040a0c076578616d706c65310c300a060355040b0c03457874311a300a060355040b0c03446576300c060355040b0c0553616c65733128300906035504030c026578301b06092a864886f70d010901160e6578406578616d706c652e636f6d")
+	var dnbytes2 = decode("3000")
+	//OU=a(Printable)+OU=aa(Printable)
+	var dnbytes3 = decode("301731153008060355040B1301613009060355040B13026161")
+	//1.2.3.4=a(Printable)
+	var dnbytes4 = decode("300C310A300806032A0304130161")
+	type args struct {
+		dn DN
+	}
+
+	tests := []struct {
+		name        string
+		args        args
+		wantDnBytes []byte
+		wantErr     bool
+	}{
+		{"TestCase:C=JP,O=example,OU=Ext,OU=Dev+OU=Sales,CN=ex+E=ex@example.com", args{dn1}, dnbytes1, false},
+		{"TestCase:Generic C=JP,O=example,OU=Ext,OU=Dev+OU=Sales,CN=ex+E=ex@example.com", args{dn3}, dnbytes1, false},
+		{"TestCase:OU=a+OU=aa ", args{dn4}, dnbytes3, false},
+		{"TestCase:Generic 1.2.3.4=a ", args{dn5}, dnbytes4, false},
+		{"TestCase: Empty DN", args{DN{}}, dnbytes2, false},
+		{"TestCase: Invalid AttributeType DN", args{dn2}, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotDnBytes, err := MarshalDN(tt.args.dn)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("MarshalDN() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(gotDnBytes, tt.wantDnBytes) {
+				t.Errorf("MarshalDN() gotDnBytes = %v, want %v", gotDnBytes, tt.wantDnBytes)
+			}
+		})
+	}
+}
+
+func TestMarshalDNOpts(t *testing.T) {
+	var dn1 = DN{
+		RDN{
+			AttributeTypeAndValue{Type: OrganizationalUnit, Value: AttributeValue{Encoding: PrintableString, Value: "aa"}},
+			AttributeTypeAndValue{Type: OrganizationalUnit, Value: AttributeValue{Encoding: PrintableString, Value: "a"}},
+		},
+	}
+	var dn2 = DN{
+		RDN{AttributeTypeAndValue{Type: CountryName, Value: AttributeValue{Encoding: PrintableString, Value: "XX"}}},
+	}
+	var dn3 = DN{
+		RDN{AttributeTypeAndValue{Type: Generic, Oid: "2.5.4.6", Value: AttributeValue{Encoding: PrintableString, Value: "XX"}}},
+	}
+	//OU=a(Printable)+OU=aa(Printable)
+	var dnbytes1 = decode("301731153008060355040B1301613009060355040B13026161")
+	//OU=aa(Printable)+OU=a(Printable)
+	var dnbytes2 = decode("301731153009060355040B130261613008060355040B130161")
+	//C=XX
+	var dnbytes3 = decode("300d310b3009060355040613025858")
+	type args struct {
+		dn   DN
+		opts []MarshalOption
+	}
+	tests := []struct {
+		name        string
+		args        args
+		wantDnBytes []byte
+		wantErr     bool
+	}{
+		{"TestCase: no option", args{dn1, nil}, dnbytes1, false},
+		{"TestCase: WithPreserveOrder", args{dn1, []MarshalOption{WithPreserveOrder()}}, dnbytes2, false},
+		{"TestCase: WithPreserveOrder Empty DN", args{DN{}, []MarshalOption{WithPreserveOrder()}}, decode("3000"), false},
+		{"TestCase: WithMaxSize equal", args{dn1, []MarshalOption{WithMaxSize(25)}}, dnbytes1, false},
+		{"TestCase: WithMaxSize exceeded", args{dn1, []MarshalOption{WithMaxSize(24)}}, nil, true},
+		{"TestCase: invalid country code", args{dn2, nil}, dnbytes3, false},
+		{"TestCase: WithStrictValidation invalid country code", args{dn2, []MarshalOption{WithStrictValidation()}}, nil, true},
+		{"TestCase: WithStrictValidation Generic invalid country code", args{dn3, []MarshalOption{WithStrictValidation()}}, nil, true},
+		{"TestCase: WithStrictValidation", args{dn1, []MarshalOption{WithStrictValidation()}}, dnbytes1, false},
+		{"TestCase: all options", args{dn1, []MarshalOption{WithStrictValidation(), WithPreserveOrder(), WithMaxSize(100)}}, dnbytes2, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotDnBytes, err := MarshalDNOpts(tt.args.dn, tt.args.opts...)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("MarshalDNOpts() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(gotDnBytes, tt.wantDnBytes) {
+				t.Errorf("MarshalDNOpts() gotDnBytes = %v, want %v", gotDnBytes, tt.wantDnBytes)
+			}
+		})
+	}
+}
+
+func TestMarshalDNToParseDERDn_OIDValue(t *testing.T) {
+	var inDn = DN{
+		RDN{AttributeTypeAndValue{Type: Generic, Oid: "1.2.3.4", Value: AttributeValue{Encoding: OIDValue, Value: "1.2.3.5"}}},
+	}
+	//1.2.3.4=1.2.3.5(OBJECT IDENTIFIER)
+	var dnBytes = decode("300e310c300a06032a030406032a0305")
+	marshaledDn, err := MarshalDN(inDn)
+	if err != nil || !reflect.DeepEqual(marshaledDn, dnBytes) {
+		t.Errorf("MarshalDN() = %x, %v, want %x", marshaledDn, err, dnBytes)
+	}
+	parsedDn, err := ParseDERDN(marshaledDn)
+	if err != nil || !reflect.DeepEqual(parsedDn, inDn) {
+		t.Errorf("ParseDERDN() = %v, %v, want %v", parsedDn, err, inDn)
+	}
+	if got := parsedDn.ToRFC4514FormatString(); got != "1.2.3.4=1.2.3.5" {
+		t.Errorf("ToRFC4514FormatString() = %v, want %v", got, "1.2.3.4=1.2.3.5")
+	}
+}
+
+func TestMarshalDNToParseDERDn_UnstructuredName(t *testing.T) {
+	var inDn = DN{
+		RDN{AttributeTypeAndValue{Type: UnstructuredName, Value: AttributeValue{Encoding: IA5String, Value: "router1"}}},
+	}
+	//unstructuredName=router1(IA5String)
+	var dnBytes = decode("30183116301406092a864886f70d0109021607726f7574657231")
+	marshaledDn, err := MarshalDN(inDn)
+	if err != nil || !reflect.DeepEqual(marshaledDn, dnBytes) {
+		t.Errorf("MarshalDN() = %x, %v, want %x", marshaledDn, err, dnBytes)
+	}
+	parsedDn, err := ParseDERDN(marshaledDn)
+	if err != nil || !reflect.DeepEqual(parsedDn, inDn) {
+		t.Errorf("ParseDERDN() = %v, %v, want %v", parsedDn, err, inDn)
+	}
+	if got := parsedDn.ToRFC4514FormatString(); got != "UNSTRUCTUREDNAME=router1" {
+		t.Errorf("ToRFC4514FormatString() = %v, want %v", got, "UNSTRUCTUREDNAME=router1")
+	}
+}
+
+func TestMarshalDNToParseDERDn(t *testing.T) {
+	var inDn = DN{
+		RDN{
+			AttributeTypeAndValue{Type: OrganizationalUnit, Value: AttributeValue{Encoding: UTF8String, Value: "a2"}},
+			AttributeTypeAndValue{Type: OrganizationalUnit, Value: AttributeValue{Encoding: UTF8String, Value: "a1"}},
+			AttributeTypeAndValue{Type: Generic, Oid: "1.2.3.4", Value: AttributeValue{Encoding: UTF8String, Value: "g1"}},
+		},
+	}
+	//AttributeTypeAndValues of the dn are Binary sorted.
+	var expectedDn = DN{
+		RDN{
+			AttributeTypeAndValue{Type: Generic, Oid: "1.2.3.4", Value: AttributeValue{Encoding: UTF8String, Value: "g1"}},
+			AttributeTypeAndValue{Type: OrganizationalUnit, Value: AttributeValue{Encoding: UTF8String, Value: "a1"}},
+			AttributeTypeAndValue{Type: OrganizationalUnit, Value: AttributeValue{Encoding: UTF8String, Value: "a2"}},
+		},
+	}
+	marshaledDn, _ := MarshalDN(inDn)
+	parsedDn, _ := ParseDERDN(marshaledDn)
+
+	if !reflect.DeepEqual(parsedDn, expectedDn) {
+		t.Errorf("ReParseDERDn = %v, want %v", parsedDn, expectedDn)
+	}
+
+}
+
+func TestAttributeTypeAndValue_resolvedType(t *testing.T) {
+	tests := []struct {
+		name string
+		atv  AttributeTypeAndValue
+		want AttributeType
+	}{
+		{"TestCase:CommonName", AttributeTypeAndValue{Type: CommonName}, CommonName},
+		{"TestCase:Generic(CommonName)", AttributeTypeAndValue{Type: Generic, Oid: "2.5.4.3"}, CommonName},
+		{"TestCase:Generic", AttributeTypeAndValue{Type: Generic, Oid: "1.2.3.4"}, Generic},
+		{"TestCase:Generic(broken oid)", AttributeTypeAndValue{Type: Generic, Oid: "broken oid"}, Generic},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.atv.resolvedType(); got != tt.want {
+				t.Errorf("resolvedType() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAttributeTypeAndValue_oidString(t *testing.T) {
+	tests := []struct {
+		name string
+		atv  AttributeTypeAndValue
+		want string
+	}{
+		{"TestCase:CommonName", AttributeTypeAndValue{Type: CommonName}, "2.5.4.3"},
+		{"TestCase:Generic(CommonName)", AttributeTypeAndValue{Type: Generic, Oid: "2.5.4.3"}, "2.5.4.3"},
+		{"TestCase:Generic", AttributeTypeAndValue{Type: Generic, Oid: "1.2.3.4"}, "1.2.3.4"},
+		{"TestCase:Generic(broken oid)", AttributeTypeAndValue{Type: Generic, Oid: "broken oid"}, "broken oid"},
+		{"TestCase:UnKnownAttributeType", AttributeTypeAndValue{Type: AttributeType(9999)}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.atv.oidString(); got != tt.want {
+				t.Errorf("oidString() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_normalizeValue(t *testing.T) {
+	type args struct {
+		at AttributeType
+		v  string
+	}
+	tests := []struct {
+		name string
+		args args
+		want string
+	}{
+		{"TestCase:CommonName upper case", args{CommonName, "ABC"}, "abc"},
+		{"TestCase:CommonName spaces", args{CommonName, "  A  B C "}, "a b c"},
+		{"TestCase:CommonName multibyte", args{CommonName, "Ä 日本"}, "ä 日本"},
+		{"TestCase:Generic", args{Generic, "  A  B C "}, "  A  B C "},
+		{"TestCase:CountryName lower case", args{CountryName, "jp"}, "JP"},
+		{"TestCase:CountryName upper case", args{CountryName, "JP"}, "JP"},
+		{"TestCase:CountryName spaces", args{CountryName, " JP "}, "JP"},
+		{"TestCase:SerialNumber", args{SerialNumber, " AB  12 "}, "AB 12"},
+		{"TestCase:DnQualifier", args{DnQualifier, "AB"}, "ab"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := normalizeValue(tt.args.at, tt.args.v); got != tt.want {
+				t.Errorf("normalizeValue() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDN_Normalize(t *testing.T) {
+	atv1 := AttributeTypeAndValue{Type: CommonName, Value: AttributeValue{UTF8String, " Mike  Smith "}}
+	atv2 := AttributeTypeAndValue{Type: ElectronicMailAddress, Value: AttributeValue{IA5String, "Mike@Example.org"}}
+	atv3 := AttributeTypeAndValue{Type: Generic, Oid: "2.5.4.10", Value: AttributeValue{UTF8String, "AAA"}}
+	atv4 := AttributeTypeAndValue{Type: Generic, Oid: "1.2.3.4", Value: AttributeValue{UTF8String, "AAA"}}
+	atv5 := AttributeTypeAndValue{Type: CountryName, Value: AttributeValue{PrintableString, "jp"}}
+	tests := []struct {
+		name string
+		d    DN
+		want DN
+	}{
+		{"TestCase: 0 RDN", DN{}, DN{}},
+		{"TestCase: cn", DN{RDN{atv1}}, DN{RDN{AttributeTypeAndValue{Type: CommonName, Value: AttributeValue{UTF8String, "mike smith"}}}}},
+		{"TestCase: Generic(o)", DN{RDN{atv3}}, DN{RDN{AttributeTypeAndValue{Type: OrganizationName, Value: AttributeValue{UTF8String, "aaa"}}}}},
+		{"TestCase: Generic", DN{RDN{atv4}}, DN{RDN{atv4}}},
+		{"TestCase: c", DN{RDN{atv5}}, DN{RDN{AttributeTypeAndValue{Type: CountryName, Value: AttributeValue{PrintableString, "JP"}}}}},
+		{"TestCase: email+cn sorted", DN{RDN{atv2, atv1}}, DN{RDN{
+			AttributeTypeAndValue{Type: ElectronicMailAddress, Value: AttributeValue{IA5String, "mike@example.org"}},
+			AttributeTypeAndValue{Type: CommonName, Value: AttributeValue{UTF8String, "mike smith"}},
+		}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.d.Normalize(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Normalize() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRDN_Equal(t *testing.T) {
+	atv1 := AttributeTypeAndValue{Type: CommonName, Value: AttributeValue{UTF8String, "Mike"}}
+	atv2 := AttributeTypeAndValue{Type: CommonName, Value: AttributeValue{UTF8String, " mike "}}
+	atv3 := AttributeTypeAndValue{Type: ElectronicMailAddress, Value: AttributeValue{IA5String, "mike@example.org"}}
+	atv4 := AttributeTypeAndValue{Type: Generic, Oid: "2.5.4.3", Value: AttributeValue{UTF8String, "MIKE"}}
+	atv5 := AttributeTypeAndValue{Type: Generic, Oid: "1.2.3.4", Value: AttributeValue{UTF8String, "Mike"}}
+	atv6 := AttributeTypeAndValue{Type: Generic, Oid: "1.2.3.4", Value: AttributeValue{UTF8String, "mike"}}
+	type args struct {
+		other RDN
+	}
+	tests := []struct {
+		name string
+		r    RDN
+		args args
+		want bool
+	}{
+		{"TestCase: same", RDN{atv1}, args{RDN{atv1}}, true},
+		{"TestCase: case and spaces", RDN{atv1}, args{RDN{atv2}}, true},
+		{"TestCase: Generic(cn)", RDN{atv1}, args{RDN{atv4}}, true},
+		{"TestCase: different order", RDN{atv1, atv3}, args{RDN{atv3, atv2}}, true},
+		{"TestCase: different count", RDN{atv1, atv3}, args{RDN{atv1}}, false},
+		{"TestCase: different type", RDN{atv1}, args{RDN{atv3}}, false},
+		{"TestCase: Generic case exact", RDN{atv5}, args{RDN{atv6}}, false},
+		{"TestCase: duplicated", RDN{atv1, atv1}, args{RDN{atv1, atv3}}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.r.Equal(tt.args.other); got != tt.want {
+				t.Errorf("Equal() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDN_Equal(t *testing.T) {
+	rdn1 := RDN{AttributeTypeAndValue{Type: CountryName, Value: AttributeValue{PrintableString, "JP"}}}
+	rdn2 := RDN{AttributeTypeAndValue{Type: OrganizationName, Value: AttributeValue{UTF8String, "Example"}}}
+	rdn3 := RDN{AttributeTypeAndValue{Type: OrganizationName, Value: AttributeValue{UTF8String, "EXAMPLE"}}}
+	rdn4 := RDN{AttributeTypeAndValue{Type: CommonName, Value: AttributeValue{UTF8String, "Mike"}}}
+	rdn5 := RDN{AttributeTypeAndValue{Type: OrganizationName, Value: AttributeValue{PrintableString, "Example"}}}
+	rdn6 := RDN{AttributeTypeAndValue{Type: CommonName, Value: AttributeValue{PrintableString, "Mike"}}}
+	rdn7 := RDN{AttributeTypeAndValue{Type: Generic, Oid: "1.2.3.4", Value: AttributeValue{UTF8String, "x"}}}
+	rdn8 := RDN{AttributeTypeAndValue{Type: Generic, Oid: "1.2.3.4", Value: AttributeValue{IA5String, "x"}}}
+	rdn9 := RDN{AttributeTypeAndValue{Type: CountryName, Value: AttributeValue{PrintableString, "jp"}}}
+	rdn10 := RDN{AttributeTypeAndValue{Type: OrganizationName, Value: AttributeValue{UTF8String, "  Example "}}}
+	rdn11 := RDN{AttributeTypeAndValue{Type: SerialNumber, Value: AttributeValue{PrintableString, "AB 12"}}}
+	rdn12 := RDN{AttributeTypeAndValue{Type: SerialNumber, Value: AttributeValue{PrintableString, " AB  12"}}}
+	rdn13 := RDN{AttributeTypeAndValue{Type: SerialNumber, Value: AttributeValue{PrintableString, "ab 12"}}}
+	rdn14 := RDN{AttributeTypeAndValue{Type: Generic, Oid: "1.2.3.4", Value: AttributeValue{UTF8String, "X"}}}
+	rdn15 := RDN{
+		AttributeTypeAndValue{Type: CommonName, Value: AttributeValue{UTF8String, "Mike"}},
+		AttributeTypeAndValue{Type: ElectronicMailAddress, Value: AttributeValue{IA5String, "mike@example.org"}},
+	}
+	rdn16 := RDN{
+		AttributeTypeAndValue{Type: ElectronicMailAddress, Value: AttributeValue{IA5String, "Mike@Example.org"}},
+		AttributeTypeAndValue{Type: CommonName, Value: AttributeValue{UTF8String, "MIKE"}},
+	}
+	type args struct {
+		other DN
+	}
+	tests := []struct {
+		name string
+		d    DN
+		args args
+		want bool
+	}{
+		{"TestCase: 0 RDN", DN{}, args{DN{}}, true},
+		{"TestCase: same", DN{rdn1, rdn2, rdn4}, args{DN{rdn1, rdn2, rdn4}}, true},
+		{"TestCase: case", DN{rdn1, rdn2, rdn4}, args{DN{rdn1, rdn3, rdn4}}, true},
+		{"TestCase: PrintableString and UTF8String", DN{rdn1, rdn2, rdn4}, args{DN{rdn1, rdn5, rdn6}}, true},
+		{"TestCase: Generic UTF8String and IA5String", DN{rdn1, rdn7}, args{DN{rdn1, rdn8}}, true},
+		{"TestCase: different order", DN{rdn1, rdn2, rdn4}, args{DN{rdn2, rdn1, rdn4}}, false},
+		{"TestCase: different count", DN{rdn1, rdn2, rdn4}, args{DN{rdn1, rdn2}}, false},
+		{"TestCase: insignificant spaces", DN{rdn1, rdn2}, args{DN{rdn1, rdn10}}, true},
+		{"TestCase: CountryName case ignored", DN{rdn1}, args{DN{rdn9}}, true},
+		{"TestCase: SerialNumber insignificant spaces", DN{rdn11}, args{DN{rdn12}}, true},
+		{"TestCase: SerialNumber case exact", DN{rdn11}, args{DN{rdn13}}, false},
+		{"TestCase: Generic octet-wise", DN{rdn7}, args{DN{rdn14}}, false},
+		{"TestCase: multi-valued RDN in different order", DN{rdn1, rdn15}, args{DN{rdn1, rdn16}}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.d.Equal(tt.args.other); got != tt.want {
+				t.Errorf("Equal() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDN_EqualUnordered(t *testing.T) {
+	rdn1 := RDN{AttributeTypeAndValue{Type: CountryName, Value: AttributeValue{PrintableString, "JP"}}}
+	rdn2 := RDN{AttributeTypeAndValue{Type: OrganizationName, Value: AttributeValue{UTF8String, "Example"}}}
+	rdn3 := RDN{AttributeTypeAndValue{Type: OrganizationName, Value: AttributeValue{PrintableString, "EXAMPLE"}}}
+	rdn4 := RDN{AttributeTypeAndValue{Type: CommonName, Value: AttributeValue{UTF8String, "Mike"}}}
+	rdn5 := RDN{AttributeTypeAndValue{Type: OrganizationalUnit, Value: AttributeValue{UTF8String, "Sales"}}}
+	type args struct {
+		other DN
+	}
+	tests := []struct {
+		name      string
+		d         DN
+		args      args
+		want      bool
+		wantEqual bool
+	}{
+		{"TestCase: 0 RDN", DN{}, args{DN{}}, true, true},
+		{"TestCase: same order", DN{rdn1, rdn2, rdn4}, args{DN{rdn1, rdn2, rdn4}}, true, true},
+		{"TestCase: different order", DN{rdn1, rdn2, rdn4}, args{DN{rdn4, rdn1, rdn2}}, true, false},
+		{"TestCase: different order, RDN missing", DN{rdn1, rdn2, rdn4}, args{DN{rdn2, rdn4, rdn3}}, false, false},
+		{"TestCase: different order with matched values", DN{rdn1, rdn2, rdn4}, args{DN{rdn3, rdn4, rdn1}}, true, false},
+		{"TestCase: repeated RDN", DN{rdn1, rdn5, rdn5}, args{DN{rdn5, rdn1, rdn5}}, true, false},
+		{"TestCase: repeated RDN count differs", DN{rdn1, rdn5, rdn5}, args{DN{rdn5, rdn1, rdn1}}, false, false},
+		{"TestCase: different RDN", DN{rdn1, rdn2, rdn4}, args{DN{rdn1, rdn2, rdn5}}, false, false},
+		{"TestCase: different count", DN{rdn1, rdn2, rdn4}, args{DN{rdn1, rdn2}}, false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.d.EqualUnordered(tt.args.other); got != tt.want {
+				t.Errorf("EqualUnordered() = %v, want %v", got, tt.want)
+			}
+			if got := tt.d.Equal(tt.args.other); got != tt.wantEqual {
+				t.Errorf("Equal() = %v, want %v", got, tt.wantEqual)
+			}
+		})
+	}
+}
+
+func TestEqualDER(t *testing.T) {
+	type args struct {
+		a []byte
+		b []byte
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    bool
+		wantErr bool
+	}{
+		{"TestCase: identical bytes", args{decode("300e310c300a06035504030c03616263"), decode("300e310c300a06035504030c03616263")}, true, false},
+		{"TestCase: identical invalid bytes", args{decode("1301"), decode("1301")}, true, false},
+		{"TestCase: different encoding and case", args{decode("300e310c300a06035504030c03616263"), decode("300e310c300a06035504031303414243")}, true, false},
+		{"TestCase: different value", args{decode("300e310c300a06035504030c03616263"), decode("300e310c300a06035504030c03616264")}, false, false},
+		{"TestCase: invalid a", args{decode("1301"), decode("300e310c300a06035504030c03616263")}, false, true},
+		{"TestCase: invalid b", args{decode("300e310c300a06035504030c03616263"), decode("1301")}, false, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := EqualDER(tt.args.a, tt.args.b)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("EqualDER() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("EqualDER() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDN_EqualsX509Subject(t *testing.T) {
+	name := pkix.Name{Country: []string{"JP"}, Organization: []string{"Example"}, CommonName: "www.example.com"}
+	subject, err := asn1.Marshal(name.ToRDNSequence())
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := RDN{AttributeTypeAndValue{Type: CountryName, Value: AttributeValue{PrintableString, "JP"}}}
+	o := RDN{AttributeTypeAndValue{Type: OrganizationName, Value: AttributeValue{PrintableString, "Example"}}}
+	cn := RDN{AttributeTypeAndValue{Type: CommonName, Value: AttributeValue{PrintableString, "www.example.com"}}}
+	gcn := RDN{AttributeTypeAndValue{Type: Generic, Oid: "2.5.4.3", Value: AttributeValue{PrintableString, "www.example.com"}}}
+	cnU := RDN{AttributeTypeAndValue{Type: CommonName, Value: AttributeValue{UTF8String, "www.example.com"}}}
+	type args struct {
+		certSubjectDER []byte
+	}
+	tests := []struct {
+		name    string
+		d       DN
+		args    args
+		want    bool
+		wantErr bool
+	}{
+		{"TestCase: same subject", DN{c, o, cn}, args{subject}, true, false},
+		{"TestCase: Generic with known oid", DN{c, o, gcn}, args{subject}, true, false},
+		{"TestCase: different Encoding", DN{c, o, cnU}, args{subject}, false, false},
+		{"TestCase: different order", DN{o, c, cn}, args{subject}, false, false},
+		{"TestCase: invalid DN", DN{RDN{}}, args{subject}, false, true},
+		{"TestCase: invalid subject", DN{c, o, cn}, args{decode("1301")}, false, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.d.EqualsX509Subject(tt.args.certSubjectDER)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("EqualsX509Subject() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("EqualsX509Subject() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCompareDERCanonical(t *testing.T) {
+	type args struct {
+		dnBytes []byte
+	}
+	tests := []struct {
+		name            string
+		args            args
+		wantCanonical   bool
+		wantReMarshaled []byte
+		wantErr         bool
+	}{
+		{"TestCase: canonical", args{decode("300e310c300a06035504030c03616263")}, true, decode("300e310c300a06035504030c03616263"), false},
+		{"TestCase: empty DN", args{decode("3000")}, true, decode("3000"), false},
+		{"TestCase: non-canonical SET order", args{decode("301731153009060355040613024a50300806035504030c0161")}, false, decode("30173115300806035504030c01613009060355040613024a50"), false},
+		{"TestCase: non-minimal length", args{decode("30810e310c300a06035504030c03616263")}, false, nil, true},
+		{"TestCase: invalid", args{decode("1301")}, false, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotCanonical, gotReMarshaled, err := CompareDERCanonical(tt.args.dnBytes)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("CompareDERCanonical() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if gotCanonical != tt.wantCanonical {
+				t.Errorf("CompareDERCanonical() gotCanonical = %v, want %v", gotCanonical, tt.wantCanonical)
+			}
+			if !reflect.DeepEqual(gotReMarshaled, tt.wantReMarshaled) {
+				t.Errorf("CompareDERCanonical() gotReMarshaled = %x, want %x", gotReMarshaled, tt.wantReMarshaled)
+			}
+		})
+	}
+}
+
+func TestRoundTripSafe(t *testing.T) {
+	type args struct {
+		dnBytes []byte
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    bool
+		wantErr bool
+	}{
+		{"TestCase: canonical", args{decode("300e310c300a06035504030c03616263")}, true, false},
+		{"TestCase: empty DN", args{decode("3000")}, true, false},
+		{"TestCase: multi-valued RDN in DER order", args{decode("30173115300806035504030c01613009060355040613024a50")}, true, false},
+		{"TestCase: multi-valued RDN not in DER order", args{decode("301731153009060355040613024a50300806035504030c0161")}, false, false},
+		{"TestCase: AttributeValue wrapped in SET", args{decode("3010310e300c060355040331050c03616263")}, false, false},
+		{"TestCase: TeletexString", args{decode("300e310c300a06035504031403616263")}, true, false},
+		{"TestCase: C=JP in UTF8String", args{decode("300d310b300906035504060c024a50")}, false, false},
+		{"TestCase: BMPString", args{decode("300d310b30090603550403" + "1e020054")}, true, false},
+		{"TestCase: BMPString with BOM", args{decode("300f310d300b0603550403" + "1e04feff0054")}, false, false},
+		{"TestCase: BMPString with surrogate pair", args{decode("300f310d300b0603550403" + "1e04d83dde00")}, false, false},
+		{"TestCase: BMPString with unpaired surrogate", args{decode("300d310b30090603550403" + "1e02d83d")}, false, true},
+		{"TestCase: non-minimal length", args{decode("30810e310c300a06035504030c03616263")}, false, true},
+		{"TestCase: invalid", args{decode("1301")}, false, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := RoundTripSafe(tt.args.dnBytes)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("RoundTripSafe() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("RoundTripSafe() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCanonicalizeDERDN(t *testing.T) {
+	type args struct {
+		dnBytes []byte
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    []byte
+		wantErr bool
+	}{
+		{"TestCase: empty DN", args{decode("3000")}, decode("3000"), false},
+		{"TestCase: canonical", args{decode("30173115300806035504030c01613009060355040613024a50")}, decode("30173115300806035504030c01613009060355040613024a50"), false},
+		{"TestCase: non-canonical SET order", args{decode("301731153009060355040613024a50300806035504030c0161")}, decode("30173115300806035504030c01613009060355040613024a50"), false},
+		{"TestCase: not supported encoding is kept", args{decode("300f310d300b06035504031e0400610062")}, decode("300f310d300b06035504031e0400610062"), false},
+		{"TestCase: invalid", args{decode("1301")}, nil, true},
+		{"TestCase: trailing data", args{decode("30000000")}, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := CanonicalizeDERDN(tt.args.dnBytes)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("CanonicalizeDERDN() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("CanonicalizeDERDN() got = %x, want %x", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDERSubjectsEqualIgnoringSetOrder(t *testing.T) {
+	canonical := decode("30173115300806035504030c01613009060355040613024a50")
+	reordered := decode("301731153009060355040613024a50300806035504030c0161")
+	type args struct {
+		a []byte
+		b []byte
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    bool
+		wantErr bool
+	}{
+		{"TestCase: identical", args{canonical, canonical}, true, false},
+		{"TestCase: different SET order", args{canonical, reordered}, true, false},
+		{"TestCase: different SET order reversed", args{reordered, canonical}, true, false},
+		{"TestCase: different value", args{canonical, decode("30173115300806035504030c01623009060355040613024a50")}, false, false},
+		{"TestCase: different encoding", args{canonical, decode("30173115300806035504031301613009060355040613024a50")}, false, false},
+		{"TestCase: invalid a", args{decode("1301"), canonical}, false, true},
+		{"TestCase: invalid b", args{canonical, decode("1301")}, false, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := DERSubjectsEqualIgnoringSetOrder(tt.args.a, tt.args.b)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("DERSubjectsEqualIgnoringSetOrder() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("DERSubjectsEqualIgnoringSetOrder() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDN_writeRFC4514FormatString(t *testing.T) {
+	c := RDN{AttributeTypeAndValue{Type: CountryName, Value: AttributeValue{PrintableString, "JP"}}}
+	cn := RDN{
+		AttributeTypeAndValue{Type: CommonName, Value: AttributeValue{UTF8String, " a+b "}},
+		AttributeTypeAndValue{Type: GivenName, Value: AttributeValue{UTF8String, "#Mike"}},
+	}
+	tests := []struct {
+		name string
+		d    DN
+		o    RFC4514Options
+		want string
+	}{
+		{"TestCase: 0 RDN", DN{}, RFC4514Options{}, "dn: "},
+		{"TestCase: default", DN{c, cn}, RFC4514Options{}, `dn: CN=\ a\+b\ +GIVENNAME=\#Mike,C=JP`},
+		{"TestCase: options", DN{c, cn}, RFC4514Options{DescriptorCase: MixedCaseDescriptor, SpaceAroundPlus: true, SpaceAfterComma: true}, `dn: cn=\ a\+b\  + givenName=\#Mike, c=JP`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var sb strings.Builder
+			sb.WriteString("dn: ")
+			tt.d.writeRFC4514FormatString(&sb, tt.o)
+			if got := sb.String(); got != tt.want {
+				t.Errorf("writeRFC4514FormatString() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func BenchmarkDN_ToRFC4514FormatString(b *testing.B) {
+	d := DN{}
+	for i := 0; i < 100; i++ {
+		d = append(d, RDN{
+			AttributeTypeAndValue{Type: OrganizationalUnit, Value: AttributeValue{Encoding: UTF8String, Value: " Unit #" + strings.Repeat("a,b+c", 20) + strconv.Itoa(i) + " "}},
+			AttributeTypeAndValue{Type: Generic, Oid: "1.2.3.4", Value: AttributeValue{Encoding: UTF8String, Value: strings.Repeat("やまだ", 20)}},
+		})
+	}
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = d.ToRFC4514FormatString()
+	}
+}
+
+func BenchmarkDN_RetrieveRDNsByOids(b *testing.B) {
+	d := DN{}
+	for i := 0; i < 100; i++ {
+		d = append(d,
+			RDN{AttributeTypeAndValue{Type: OrganizationalUnit, Value: AttributeValue{Encoding: UTF8String, Value: "Unit" + strconv.Itoa(i)}}},
+			RDN{
+				AttributeTypeAndValue{Type: CommonName, Value: AttributeValue{Encoding: UTF8String, Value: "Name" + strconv.Itoa(i)}},
+				AttributeTypeAndValue{Type: SerialNumber, Value: AttributeValue{Encoding: PrintableString, Value: strconv.Itoa(i)}},
+			},
+			RDN{AttributeTypeAndValue{Type: Generic, Oid: "1.2.3.4", Value: AttributeValue{Encoding: UTF8String, Value: "v"}}},
+		)
+	}
+	oids := []string{"2.5.4.5", "2.5.4.3"}
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = d.RetrieveRDNsByOids(oids)
+	}
+}
+
+func BenchmarkEqualDER(b *testing.B) {
+	dnBytes := MustMarshalDN(DN{
+		RDN{AttributeTypeAndValue{Type: CountryName, Value: AttributeValue{Encoding: PrintableString, Value: "JP"}}},
+		RDN{AttributeTypeAndValue{Type: OrganizationName, Value: AttributeValue{Encoding: UTF8String, Value: "Example"}}},
+		RDN{AttributeTypeAndValue{Type: CommonName, Value: AttributeValue{Encoding: UTF8String, Value: "www.example.com"}}},
+	})
+	other := append([]byte{}, dnBytes...)
+	b.Run("EqualDER", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			_, _ = EqualDER(dnBytes, other)
+		}
+	})
+	b.Run("ParseDERDN and Equal", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			da, _ := ParseDERDN(dnBytes)
+			db, _ := ParseDERDN(other)
+			_ = da.Equal(db)
+		}
+	})
+
+	//logically equal, but different in bytes by the case, spaces and encodings of the values.
+	equivalent := MustMarshalDN(DN{
+		RDN{AttributeTypeAndValue{Type: CountryName, Value: AttributeValue{Encoding: PrintableString, Value: "JP"}}},
+		RDN{AttributeTypeAndValue{Type: OrganizationName, Value: AttributeValue{Encoding: PrintableString, Value: "EXAMPLE"}}},
+		RDN{AttributeTypeAndValue{Type: CommonName, Value: AttributeValue{Encoding: UTF8String, Value: " www.Example.com  "}}},
+	})
+	if ok, err := EqualDER(dnBytes, equivalent); err != nil || !ok {
+		b.Fatalf("EqualDER() = %v, %v, want true", ok, err)
+	}
+	b.Run("EqualDER logically equal", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			_, _ = EqualDER(dnBytes, equivalent)
+		}
+	})
+	b.Run("ParseDERDN and Equal logically equal", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			da, _ := ParseDERDN(dnBytes)
+			db, _ := ParseDERDN(equivalent)
+			_ = da.Equal(db)
+		}
+	})
+}
+
+func TestDN_Subtract(t *testing.T) {
+	c := RDN{AttributeTypeAndValue{Type: CountryName, Value: AttributeValue{PrintableString, "JP"}}}
+	o := RDN{AttributeTypeAndValue{Type: OrganizationName, Value: AttributeValue{UTF8String, "example"}}}
+	oUpper := RDN{AttributeTypeAndValue{Type: OrganizationName, Value: AttributeValue{PrintableString, "EXAMPLE"}}}
+	ou := RDN{AttributeTypeAndValue{Type: OrganizationalUnit, Value: AttributeValue{UTF8String, "Sales"}}}
+	cn := RDN{AttributeTypeAndValue{Type: CommonName, Value: AttributeValue{UTF8String, "Mike"}}}
+	full := DN{c, o, ou, cn}
+	type args struct {
+		base DN
+	}
+	tests := []struct {
+		name    string
+		d       DN
+		args    args
+		want    DN
+		wantErr bool
+	}{
+		{"TestCase: proper suffix", full, args{DN{c, o}}, DN{ou, cn}, false},
+		{"TestCase: proper suffix matched by Equal", full, args{DN{c, oUpper}}, DN{ou, cn}, false},
+		{"TestCase: equal", full, args{full}, DN{}, false},
+		{"TestCase: empty base", full, args{DN{}}, full, false},
+		{"TestCase: non-suffix", full, args{DN{c, ou}}, nil, true},
+		{"TestCase: base longer than DN", DN{c, o}, args{full}, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.d.Subtract(tt.args.base)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Subtract() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Subtract() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMergeDNPreferring(t *testing.T) {
+	c := RDN{AttributeTypeAndValue{Type: CountryName, Value: AttributeValue{PrintableString, "JP"}}}
+	cUS := RDN{AttributeTypeAndValue{Type: CountryName, Value: AttributeValue{PrintableString, "US"}}}
+	st := RDN{AttributeTypeAndValue{Type: StateOrProvinceName, Value: AttributeValue{UTF8String, "Tokyo"}}}
+	o := RDN{AttributeTypeAndValue{Type: OrganizationName, Value: AttributeValue{UTF8String, "Example"}}}
+	o2 := RDN{AttributeTypeAndValue{Type: Generic, Oid: "2.5.4.10", Value: AttributeValue{UTF8String, "Overlay"}}}
+	ou1 := RDN{AttributeTypeAndValue{Type: OrganizationalUnit, Value: AttributeValue{UTF8String, "Sales"}}}
+	ou2 := RDN{AttributeTypeAndValue{Type: OrganizationalUnit, Value: AttributeValue{UTF8String, "Dev"}}}
+	ou1Upper := RDN{AttributeTypeAndValue{Type: OrganizationalUnit, Value: AttributeValue{PrintableString, "SALES"}}}
+	dc1 := RDN{AttributeTypeAndValue{Type: DomainComponent, Value: AttributeValue{IA5String, "com"}}}
+	dc2 := RDN{AttributeTypeAndValue{Type: DomainComponent, Value: AttributeValue{IA5String, "example"}}}
+	cn := RDN{AttributeTypeAndValue{Type: CommonName, Value: AttributeValue{UTF8String, "base"}}}
+	cn2 := RDN{AttributeTypeAndValue{Type: CommonName, Value: AttributeValue{UTF8String, "overlay"}}}
+	multi := RDN{
+		AttributeTypeAndValue{Type: CommonName, Value: AttributeValue{UTF8String, "Mike"}},
+		AttributeTypeAndValue{Type: Surname, Value: AttributeValue{UTF8String, "Smith"}},
+	}
+	multi2 := RDN{
+		AttributeTypeAndValue{Type: Surname, Value: AttributeValue{UTF8String, "Jones"}},
+		AttributeTypeAndValue{Type: CommonName, Value: AttributeValue{UTF8String, "Mike"}},
+	}
+	type args struct {
+		base    DN
+		overlay DN
+	}
+	tests := []struct {
+		name string
+		args args
+		want DN
+	}{
+		{"TestCase: both empty", args{DN{}, DN{}}, DN{}},
+		{"TestCase: empty overlay", args{DN{c, o, cn}, DN{}}, DN{c, o, cn}},
+		{"TestCase: empty base", args{DN{}, DN{c, o, cn}}, DN{c, o, cn}},
+		{"TestCase: override", args{DN{c, o, cn}, DN{cUS, cn2}}, DN{cUS, o, cn2}},
+		{"TestCase: override by Generic with known oid", args{DN{c, o, cn}, DN{o2}}, DN{c, o2, cn}},
+		{"TestCase: override repeated base", args{DN{c, cn, cn}, DN{cn2}}, DN{c, cn2}},
+		{"TestCase: append OU", args{DN{c, o, ou1, cn}, DN{ou2}}, DN{c, o, ou1, ou2, cn}},
+		{"TestCase: append matched OU", args{DN{c, o, ou1, cn}, DN{ou1Upper, ou2}}, DN{c, o, ou1, ou2, cn}},
+		{"TestCase: insert OU before CN", args{DN{c, o, cn}, DN{ou1, ou2}}, DN{c, o, ou1, ou2, cn}},
+		{"TestCase: insert ST after C", args{DN{c, o, cn}, DN{st}}, DN{c, st, o, cn}},
+		{"TestCase: append DC", args{DN{dc1, cn}, DN{dc2}}, DN{dc1, dc2, cn}},
+		{"TestCase: insert C and OU into DC DN", args{DN{dc1, dc2, cn}, DN{c, ou1}}, DN{dc1, dc2, c, ou1, cn}},
+		{"TestCase: override multi-valued RDN", args{DN{c, multi}, DN{multi2}}, DN{c, multi2}},
+		{"TestCase: multi-valued RDN does not override CN", args{DN{c, cn}, DN{multi}}, DN{c, multi, cn}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			base := append(DN{}, tt.args.base...)
+			if got := MergeDNPreferring(tt.args.base, tt.args.overlay); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("MergeDNPreferring() = %v, want %v", got, tt.want)
+			}
+			if !reflect.DeepEqual(tt.args.base, base) {
+				t.Errorf("MergeDNPreferring() modified base = %v, want %v", tt.args.base, base)
+			}
+		})
+	}
+}
+
+func Test_mergeKey(t *testing.T) {
+	tests := []struct {
+		name string
+		r    RDN
+		want string
+	}{
+		{"TestCase: 0 AttributeTypeAndValue", RDN{}, ""},
+		{"TestCase: CN", RDN{AttributeTypeAndValue{Type: CommonName}}, "2.5.4.3"},
+		{"TestCase: Generic with known oid", RDN{AttributeTypeAndValue{Type: Generic, Oid: "2.5.4.3"}}, "2.5.4.3"},
+		{"TestCase: multi-valued", RDN{AttributeTypeAndValue{Type: Surname}, AttributeTypeAndValue{Type: CommonName}}, "2.5.4.3+2.5.4.4"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := mergeKey(tt.r); got != tt.want {
+				t.Errorf("mergeKey() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_isRepeatableMergeKey(t *testing.T) {
+	tests := []struct {
+		name string
+		k    string
+		want bool
+	}{
+		{"TestCase: OU", "2.5.4.11", true},
+		{"TestCase: DC", "0.9.2342.19200300.100.1.25", true},
+		{"TestCase: CN", "2.5.4.3", false},
+		{"TestCase: multi-valued OU", "2.5.4.11+2.5.4.11", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isRepeatableMergeKey(tt.k); got != tt.want {
+				t.Errorf("isRepeatableMergeKey() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_mergeRank(t *testing.T) {
+	tests := []struct {
+		name string
+		r    RDN
+		want int
+	}{
+		{"TestCase: 0 AttributeTypeAndValue", RDN{}, 6},
+		{"TestCase: DC", RDN{AttributeTypeAndValue{Type: DomainComponent}}, 0},
+		{"TestCase: CN", RDN{AttributeTypeAndValue{Type: CommonName}}, 7},
+		{"TestCase: E", RDN{AttributeTypeAndValue{Type: ElectronicMailAddress}}, 8},
+		{"TestCase: CN and OU", RDN{AttributeTypeAndValue{Type: CommonName}, AttributeTypeAndValue{Type: OrganizationalUnit}}, 5},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := mergeRank(tt.r); got != tt.want {
+				t.Errorf("mergeRank() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDN_containsRDN(t *testing.T) {
+	c := RDN{AttributeTypeAndValue{Type: CountryName, Value: AttributeValue{PrintableString, "JP"}}}
+	ou := RDN{AttributeTypeAndValue{Type: OrganizationalUnit, Value: AttributeValue{UTF8String, "Sales"}}}
+	ouUpper := RDN{AttributeTypeAndValue{Type: OrganizationalUnit, Value: AttributeValue{PrintableString, "SALES"}}}
+	tests := []struct {
+		name string
+		d    DN
+		r    RDN
+		want bool
+	}{
+		{"TestCase: 0 RDN", DN{}, ou, false},
+		{"TestCase: contained", DN{c, ou}, ouUpper, true},
+		{"TestCase: not contained", DN{c}, ou, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.d.containsRDN(tt.r); got != tt.want {
+				t.Errorf("containsRDN() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCommonAncestor(t *testing.T) {
+	c := RDN{AttributeTypeAndValue{Type: CountryName, Value: AttributeValue{PrintableString, "JP"}}}
+	cUS := RDN{AttributeTypeAndValue{Type: CountryName, Value: AttributeValue{PrintableString, "US"}}}
+	o := RDN{AttributeTypeAndValue{Type: OrganizationName, Value: AttributeValue{UTF8String, "example"}}}
+	oUpper := RDN{AttributeTypeAndValue{Type: OrganizationName, Value: AttributeValue{PrintableString, "EXAMPLE"}}}
+	sales := RDN{AttributeTypeAndValue{Type: OrganizationalUnit, Value: AttributeValue{UTF8String, "Sales"}}}
+	dev := RDN{AttributeTypeAndValue{Type: OrganizationalUnit, Value: AttributeValue{UTF8String, "Dev"}}}
+	cn := RDN{AttributeTypeAndValue{Type: CommonName, Value: AttributeValue{UTF8String, "Mike"}}}
+	type args struct {
+		a DN
+		b DN
+	}
+	tests := []struct {
+		name  string
+		args  args
+		want  DN
+		want1 int
+	}{
+		{"TestCase: partial overlap", args{DN{c, o, sales, cn}, DN{c, oUpper, dev, cn}}, DN{c, o}, 2},
+		{"TestCase: full overlap", args{DN{c, o, sales}, DN{c, o, sales}}, DN{c, o, sales}, 3},
+		{"TestCase: ancestor and descendant", args{DN{c, o}, DN{c, o, sales, cn}}, DN{c, o}, 2},
+		{"TestCase: no common ancestor", args{DN{c, o}, DN{cUS, o}}, DN{}, 0},
+		{"TestCase: empty DN", args{DN{}, DN{c, o}}, DN{}, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, got1 := CommonAncestor(tt.args.a, tt.args.b)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("CommonAncestor() got = %v, want %v", got, tt.want)
+			}
+			if got1 != tt.want1 {
+				t.Errorf("CommonAncestor() got1 = %v, want %v", got1, tt.want1)
+			}
+		})
+	}
+}
+
+func TestDN_RDNDiff(t *testing.T) {
+	c := RDN{AttributeTypeAndValue{Type: CountryName, Value: AttributeValue{PrintableString, "JP"}}}
+	o1 := RDN{AttributeTypeAndValue{Type: OrganizationName, Value: AttributeValue{UTF8String, "Example"}}}
+	o2 := RDN{AttributeTypeAndValue{Type: OrganizationName, Value: AttributeValue{PrintableString, "EXAMPLE"}}}
+	o3 := RDN{AttributeTypeAndValue{Type: OrganizationName, Value: AttributeValue{UTF8String, "Other"}}}
+	cn1 := RDN{AttributeTypeAndValue{Type: CommonName, Value: AttributeValue{UTF8String, "Mike"}}}
+	cn2 := RDN{AttributeTypeAndValue{Type: CommonName, Value: AttributeValue{UTF8String, "Bob"}}}
+	type args struct {
+		other DN
+	}
+	tests := []struct {
+		name string
+		d    DN
+		args args
+		want []int
+	}{
+		{"TestCase: 0 RDN", DN{}, args{DN{}}, []int{}},
+		{"TestCase: Equal DNs", DN{c, o1, cn1}, args{DN{c, o2, cn1}}, []int{}},
+		{"TestCase: 1 RDN differs", DN{c, o1, cn1}, args{DN{c, o1, cn2}}, []int{2}},
+		{"TestCase: 2 RDNs differ", DN{c, o1, cn1}, args{DN{c, o3, cn2}}, []int{1, 2}},
+		{"TestCase: other is longer", DN{c, o1}, args{DN{c, o1, cn1}}, []int{2}},
+		{"TestCase: other is shorter and differs", DN{c, o1, cn1}, args{DN{c, o3}}, []int{1, 2}},
+		{"TestCase: other is empty", DN{c, o1}, args{DN{}}, []int{0, 1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.d.RDNDiff(tt.args.other); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("RDNDiff() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDN_CanonicalString(t *testing.T) {
+	c := RDN{AttributeTypeAndValue{Type: CountryName, Value: AttributeValue{PrintableString, "jp"}}}
+	cnsn := RDN{
+		AttributeTypeAndValue{Type: Surname, Value: AttributeValue{UTF8String, "Smith"}},
+		AttributeTypeAndValue{Type: CommonName, Value: AttributeValue{PrintableString, " Mike "}},
+	}
+	o1 := RDN{AttributeTypeAndValue{Type: OrganizationName, Value: AttributeValue{UTF8String, "a,O=b"}}}
+	o2 := RDN{AttributeTypeAndValue{Type: OrganizationName, Value: AttributeValue{UTF8String, "a"}}}
+	o3 := RDN{AttributeTypeAndValue{Type: OrganizationName, Value: AttributeValue{UTF8String, "b"}}}
+	g := RDN{AttributeTypeAndValue{Type: Generic, Oid: "1.2.3.4", Value: AttributeValue{IA5String, "A+B"}}}
+	tests := []struct {
+		name string
+		d    DN
+		want string
+	}{
+		{"TestCase: 0 RDN", DN{}, ""},
+		{"TestCase: normalized and sorted", DN{c, cnsn}, "2.5.4.6=JP,2.5.4.3=mike+2.5.4.4=smith"},
+		{"TestCase: escaped comma", DN{o1}, "2.5.4.10=a\\,o=b"},
+		{"TestCase: 2 RDNs", DN{o2, o3}, "2.5.4.10=a,2.5.4.10=b"},
+		{"TestCase: Generic", DN{g}, "1.2.3.4=A\\+B"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.d.CanonicalString(); got != tt.want {
+				t.Errorf("CanonicalString() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+	if (DN{o1}).String() != (DN{o2, o3}).String() {
+		t.Fatalf("String() is expected to collide")
+	}
+	if (DN{o1}).CanonicalString() == (DN{o2, o3}).CanonicalString() {
+		t.Errorf("CanonicalString() collides: %v", DN{o1}.CanonicalString())
+	}
+}
+
+func TestDN_DedupKey(t *testing.T) {
+	dn1 := DN{
+		RDN{AttributeTypeAndValue{Type: CountryName, Value: AttributeValue{PrintableString, "JP"}}},
+		RDN{AttributeTypeAndValue{Type: OrganizationName, Value: AttributeValue{UTF8String, "Example"}}},
+		RDN{
+			AttributeTypeAndValue{Type: CommonName, Value: AttributeValue{UTF8String, "Mike"}},
+			AttributeTypeAndValue{Type: ElectronicMailAddress, Value: AttributeValue{IA5String, "mike@example.org"}},
+		},
+	}
+	dn2 := DN{
+		RDN{AttributeTypeAndValue{Type: CountryName, Value: AttributeValue{PrintableString, "jp"}}},
+		RDN{AttributeTypeAndValue{Type: Generic, Oid: "2.5.4.10", Value: AttributeValue{PrintableString, "EXAMPLE"}}},
+		RDN{
+			AttributeTypeAndValue{Type: ElectronicMailAddress, Value: AttributeValue{IA5String, "Mike@Example.org"}},
+			AttributeTypeAndValue{Type: CommonName, Value: AttributeValue{UTF8String, " MIKE "}},
+		},
+	}
+	dn3 := DN{
+		RDN{AttributeTypeAndValue{Type: CountryName, Value: AttributeValue{PrintableString, "JP"}}},
+		RDN{AttributeTypeAndValue{Type: OrganizationName, Value: AttributeValue{UTF8String, "Example"}}},
+		RDN{AttributeTypeAndValue{Type: CommonName, Value: AttributeValue{UTF8String, "Mike"}}},
+	}
+	dn4 := DN{
+		RDN{AttributeTypeAndValue{Type: OrganizationName, Value: AttributeValue{UTF8String, "a,b"}}},
+	}
+	dn5 := DN{
+		RDN{AttributeTypeAndValue{Type: OrganizationName, Value: AttributeValue{UTF8String, "a"}}},
+		RDN{AttributeTypeAndValue{Type: OrganizationName, Value: AttributeValue{UTF8String, "b"}}},
+	}
+	tests := []struct {
+		name string
+		d1   DN
+		d2   DN
+		want bool
+	}{
+		{"TestCase: differently cased DNs", dn1, dn2, true},
+		{"TestCase: same DN", dn3, dn3, true},
+		{"TestCase: different DNs", dn1, dn3, false},
+		{"TestCase: escaped value", dn4, dn5, false},
+		{"TestCase: SerialNumber case exact", DN{RDN{AttributeTypeAndValue{Type: SerialNumber, Value: AttributeValue{PrintableString, "AB12"}}}},
+			DN{RDN{AttributeTypeAndValue{Type: SerialNumber, Value: AttributeValue{PrintableString, "ab12"}}}}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			k1, k2 := tt.d1.DedupKey(), tt.d2.DedupKey()
+			if got := k1 == k2; got != tt.want {
+				t.Errorf("DedupKey() = %v, %v, want same %v", k1, k2, tt.want)
+			}
+			if got := tt.d1.Equal(tt.d2); got != tt.want {
+				t.Errorf("Equal() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDN_CanonicalHashInput(t *testing.T) {
+	dn1 := DN{
+		RDN{AttributeTypeAndValue{Type: CountryName, Value: AttributeValue{PrintableString, "JP"}}},
+		RDN{AttributeTypeAndValue{Type: OrganizationName, Value: AttributeValue{PrintableString, "Example  Inc"}}},
+		RDN{
+			AttributeTypeAndValue{Type: CommonName, Value: AttributeValue{UTF8String, "Mike"}},
+			AttributeTypeAndValue{Type: DomainComponent, Value: AttributeValue{IA5String, "example"}},
+		},
+	}
+	dn2 := DN{
+		RDN{AttributeTypeAndValue{Type: CountryName, Value: AttributeValue{UTF8String, "jp"}}},
+		RDN{AttributeTypeAndValue{Type: Generic, Oid: "2.5.4.10", Value: AttributeValue{UTF8String, " example inc "}}},
+		RDN{
+			AttributeTypeAndValue{Type: DomainComponent, Value: AttributeValue{UTF8String, "EXAMPLE"}},
+			AttributeTypeAndValue{Type: CommonName, Value: AttributeValue{PrintableString, "MIKE"}},
+		},
+	}
+	dn3 := DN{
+		RDN{AttributeTypeAndValue{Type: CountryName, Value: AttributeValue{PrintableString, "JP"}}},
+		RDN{AttributeTypeAndValue{Type: OrganizationName, Value: AttributeValue{PrintableString, "Example Corp"}}},
+	}
+	dn4 := DN{
+		RDN{AttributeTypeAndValue{Type: Generic, Oid: "1.2.3", Value: AttributeValue{OIDValue, "1.2.3.4"}}},
+	}
+	dn5 := DN{
+		RDN{AttributeTypeAndValue{Type: Generic, Oid: "1.2.3", Value: AttributeValue{UTF8String, "1.2.3.4"}}},
+	}
+	tests := []struct {
+		name string
+		d1   DN
+		d2   DN
+		want bool
+	}{
+		{"TestCase: different encodings, case and spaces", dn1, dn2, true},
+		{"TestCase: different values", dn1, dn3, false},
+		{"TestCase: OIDValue and UTF8String", dn4, dn5, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b1, b2 := tt.d1.CanonicalHashInput(), tt.d2.CanonicalHashInput()
+			if got := bytes.Equal(b1, b2); got != tt.want {
+				t.Errorf("CanonicalHashInput() = %x, %x, want same %v", b1, b2, tt.want)
+			}
+			if got := tt.d1.Equal(tt.d2); got != tt.want {
+				t.Errorf("Equal() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDN_CanonicalHashInput_Bytes(t *testing.T) {
+	tests := []struct {
+		name string
+		d    DN
+		want string
+	}{
+		{"TestCase: empty DN", DN{}, "3000"},
+		{"TestCase: PrintableString is encoded as UTF8String", DN{
+			RDN{AttributeTypeAndValue{Type: CommonName, Value: AttributeValue{PrintableString, "Mike"}}},
+		}, "300f310d300b06035504030c046d696b65"},
+		{"TestCase: invalid oid", DN{
+			RDN{AttributeTypeAndValue{Type: Generic, Oid: "a.b", Value: AttributeValue{UTF8String, "x"}}},
+		}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := hex.EncodeToString(tt.d.CanonicalHashInput()); got != tt.want {
+				t.Errorf("CanonicalHashInput() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDN_Canonicalize(t *testing.T) {
+	dn1 := DN{
+		RDN{AttributeTypeAndValue{Type: Generic, Oid: "2.5.4.6", Value: AttributeValue{PrintableString, "JP"}}},
+		RDN{
+			AttributeTypeAndValue{Type: OrganizationalUnit, Value: AttributeValue{UTF8String, "Sales"}},
+			AttributeTypeAndValue{Type: CommonName, Value: AttributeValue{UTF8String, "Mike"}},
+			AttributeTypeAndValue{Type: Generic, Oid: "1.2.3.4", Value: AttributeValue{PrintableString, "a"}},
+		},
+		RDN{
+			AttributeTypeAndValue{Type: CommonName, Value: AttributeValue{UTF8String, "bb"}},
+			AttributeTypeAndValue{Type: CommonName, Value: AttributeValue{UTF8String, "a"}},
+		},
+	}
+	want1 := DN{
+		RDN{AttributeTypeAndValue{Type: CountryName, Value: AttributeValue{PrintableString, "JP"}}},
+		RDN{
+			AttributeTypeAndValue{Type: Generic, Oid: "1.2.3.4", Value: AttributeValue{PrintableString, "a"}},
+			AttributeTypeAndValue{Type: CommonName, Value: AttributeValue{UTF8String, "Mike"}},
+			AttributeTypeAndValue{Type: OrganizationalUnit, Value: AttributeValue{UTF8String, "Sales"}},
+		},
+		RDN{
+			AttributeTypeAndValue{Type: CommonName, Value: AttributeValue{UTF8String, "a"}},
+			AttributeTypeAndValue{Type: CommonName, Value: AttributeValue{UTF8String, "bb"}},
+		},
+	}
+	tests := []struct {
+		name string
+		d    DN
+		want DN
+	}{
+		{"TestCase: multi-valued RDNs and Generic with known oid", dn1, want1},
+		{"TestCase: canonical DN", want1, want1},
+		{"TestCase: empty DN", DN{}, DN{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.d.Canonicalize()
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Canonicalize() = %v, want %v", got, tt.want)
+			}
+			b, err := MarshalDN(tt.d)
+			if err != nil {
+				t.Fatalf("MarshalDN() error = %v", err)
+			}
+			parsed, err := ParseDERDN(b)
+			if err != nil {
+				t.Fatalf("ParseDERDN() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, parsed) {
+				t.Errorf("Canonicalize() = %v, MarshalDN->ParseDERDN = %v", got, parsed)
+			}
+		})
+	}
+}
+
+func Test_isValidAttributeType(t *testing.T) {
+	type args struct {
+		at AttributeType
+	}
+	tests := []struct {
+		name        string
+		args        args
+		wantIsValid bool
+		wantErr     bool
+	}{
+		{"TestCase: CountryName", args{CountryName}, true, false},
+		{"TestCase: OrganizationName", args{OrganizationName}, true, false},
+		{"TestCase: OrganizationalUnit", args{OrganizationalUnit}, true, false},
+		{"TestCase: DnQualifier", args{DnQualifier}, true, false},
+		{"TestCase: StateOrProvinceName", args{StateOrProvinceName}, true, false},
+		{"TestCase: CommonName", args{CommonName}, true, false},
+		{"TestCase: SerialNumber", args{SerialNumber}, true, false},
+		{"TestCase: LocalityName", args{LocalityName}, true, false},
+		{"TestCase: Title", args{Title}, true, false},
+		{"TestCase: Surname", args{Surname}, true, false},
+		{"TestCase: GivenName", args{GivenName}, true, false},
+		{"TestCase: Initials", args{Initials}, true, false},
+		{"TestCase: Pseudonym", args{Pseudonym}, true, false},
+		{"TestCase: GenerationQualifier", args{GenerationQualifier}, true, false},
+		{"TestCase: ElectronicMailAddress", args{ElectronicMailAddress}, true, false},
+		{"TestCase: DomainComponent", args{DomainComponent}, true, false},
+		{"TestCase: UnstructuredName", args{UnstructuredName}, true, false},
+		{"TestCase: UnstructuredAddress", args{UnstructuredAddress}, true, false},
+		{"TestCase: StreetAddress", args{StreetAddress}, true, false},
+		{"TestCase: PostalCode", args{PostalCode}, true, false},
+		{"TestCase: UserID", args{UserID}, true, false},
+		{"TestCase: the other", args{999}, false, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotIsValid, err := isValidAttributeType(tt.args.at)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("isValidAttributeType() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if gotIsValid != tt.wantIsValid {
+				t.Errorf("isValidAttributeType() gotIsValid = %v, want %v", gotIsValid, tt.wantIsValid)
+			}
+		})
+	}
+}
+
+func TestAttributeTypeGroups(t *testing.T) {
+	tests := []struct {
+		name                string
+		at                  AttributeType
+		wantDirectoryString bool
+		wantPrintableOnly   bool
+		wantIA5             bool
+	}{
+		{"TestCase: CountryName", CountryName, false, true, false},
+		{"TestCase: OrganizationName", OrganizationName, true, false, false},
+		{"TestCase: OrganizationalUnit", OrganizationalUnit, true, false, false},
+		{"TestCase: DnQualifier", DnQualifier, false, true, false},
+		{"TestCase: StateOrProvinceName", StateOrProvinceName, true, false, false},
+		{"TestCase: CommonName", CommonName, true, false, false},
+		{"TestCase: SerialNumber", SerialNumber, false, true, false},
+		{"TestCase: LocalityName", LocalityName, true, false, false},
+		{"TestCase: Title", Title, true, false, false},
+		{"TestCase: Surname", Surname, true, false, false},
+		{"TestCase: GivenName", GivenName, true, false, false},
+		{"TestCase: Initials", Initials, true, false, false},
+		{"TestCase: Pseudonym", Pseudonym, true, false, false},
+		{"TestCase: GenerationQualifier", GenerationQualifier, true, false, false},
+		{"TestCase: ElectronicMailAddress", ElectronicMailAddress, false, false, true},
+		{"TestCase: DomainComponent", DomainComponent, false, false, true},
+		{"TestCase: UnstructuredName", UnstructuredName, false, false, true},
+		{"TestCase: UnstructuredAddress", UnstructuredAddress, true, false, false},
+		{"TestCase: StreetAddress", StreetAddress, true, false, false},
+		{"TestCase: PostalCode", PostalCode, true, false, false},
+		{"TestCase: UserID", UserID, true, false, true},
+		{"TestCase: Generic", Generic, false, false, false},
+		{"TestCase: not supported AttributeType", AttributeType(0), false, false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsDirectoryStringType(tt.at); got != tt.wantDirectoryString {
+				t.Errorf("IsDirectoryStringType() = %v, want %v", got, tt.wantDirectoryString)
+			}
+			if got := IsPrintableOnlyType(tt.at); got != tt.wantPrintableOnly {
+				t.Errorf("IsPrintableOnlyType() = %v, want %v", got, tt.wantPrintableOnly)
+			}
+			if got := IsIA5Type(tt.at); got != tt.wantIA5 {
+				t.Errorf("IsIA5Type() = %v, want %v", got, tt.wantIA5)
+			}
+		})
+	}
+}
+
+func Test_isValidAttributeTypeAndAttributeValueComb(t *testing.T) {
+	type args struct {
+		at AttributeType
+		av AttributeValue
+	}
+	tests := []struct {
+		name        string
+		args        args
+		wantIsValid bool
+		wantErr     bool
+	}{
+		{"TestCase: CountryName, PrintableString", args{CountryName, AttributeValue{Encoding: PrintableString}}, true, false},
+		{"TestCase: CountryName, the other", args{CountryName, AttributeValue{Encoding: UTF8String}}, false, true},
+		{"TestCase: CountryName, BMPString", args{CountryName, AttributeValue{Encoding: BMPString}}, false, true},
+		{"TestCase: CountryName, TeletexString", args{CountryName, AttributeValue{Encoding: TeletexString}}, false, true},
+
+		{"TestCase: OrganizationName, PrintableString", args{OrganizationName, AttributeValue{Encoding: PrintableString}}, true, false},
+		{"TestCase: OrganizationName, UTF8String", args{OrganizationName, AttributeValue{Encoding: UTF8String}}, true, false},
+		{"TestCase: OrganizationName, TeletexString", args{OrganizationName, AttributeValue{Encoding: TeletexString}}, true, false},
+		{"TestCase: OrganizationName, the other", args{OrganizationName, AttributeValue{Encoding: IA5String}}, false, true},
+
+		{"TestCase: OrganizationalUnit, PrintableString", args{OrganizationalUnit, AttributeValue{Encoding: PrintableString}}, true, false},
+		{"TestCase: OrganizationalUnit, UTF8String", args{OrganizationalUnit, AttributeValue{Encoding: UTF8String}}, true, false},
+		{"TestCase: OrganizationalUnit, the other", args{OrganizationalUnit, AttributeValue{Encoding: IA5String}}, false, true},
+
+		{"TestCase: DnQualifier, PrintableString", args{DnQualifier, AttributeValue{Encoding: PrintableString}}, true, false},
+		{"TestCase: DnQualifier, the other", args{DnQualifier, AttributeValue{Encoding: UTF8String}}, false, true},
+
+		{"TestCase: StateOrProvinceName, PrintableString", args{StateOrProvinceName, AttributeValue{Encoding: PrintableString}}, true, false},
+		{"TestCase: StateOrProvinceName, UTF8String", args{StateOrProvinceName, AttributeValue{Encoding: UTF8String}}, true, false},
+		{"TestCase: StateOrProvinceName, the other", args{StateOrProvinceName, AttributeValue{Encoding: IA5String}}, false, true},
+
+		{"TestCase: CommonNam, PrintableString", args{CommonName, AttributeValue{Encoding: PrintableString}}, true, false},
+		{"TestCase: CommonName, UTF8String", args{CommonName, AttributeValue{Encoding: UTF8String}}, true, false},
+		{"TestCase: CommonName, BMPString", args{CommonName, AttributeValue{Encoding: BMPString}}, true, false},
+		{"TestCase: CommonName, TeletexString", args{CommonName, AttributeValue{Encoding: TeletexString}}, true, false},
+		{"TestCase: CommonName, the other", args{CommonName, AttributeValue{Encoding: IA5String}}, false, true},
+		{"TestCase: CommonName, OIDValue", args{CommonName, AttributeValue{Encoding: OIDValue}}, false, true},
+
+		{"TestCase: SerialNumber, PrintableString", args{SerialNumber, AttributeValue{Encoding: PrintableString}}, true, false},
+		{"TestCase: SerialNumber, the other", args{SerialNumber, AttributeValue{Encoding: UTF8String}}, false, true},
+
+		{"TestCase: LocalityName, PrintableString", args{LocalityName, AttributeValue{Encoding: PrintableString}}, true, false},
+		{"TestCase: LocalityName, UTF8String", args{LocalityName, AttributeValue{Encoding: UTF8String}}, true, false},
+		{"TestCase: LocalityName, the other", args{LocalityName, AttributeValue{Encoding: IA5String}}, false, true},
+
+		{"TestCase: Title, PrintableString", args{Title, AttributeValue{Encoding: PrintableString}}, true, false},
+		{"TestCase: Title, UTF8String", args{Title, AttributeValue{Encoding: UTF8String}}, true, false},
+		{"TestCase: Title, the other", args{Title, AttributeValue{Encoding: IA5String}}, false, true},
+
+		{"TestCase: Surname, PrintableString", args{Surname, AttributeValue{Encoding: PrintableString}}, true, false},
+		{"TestCase: Surname, UTF8String", args{Surname, AttributeValue{Encoding: UTF8String}}, true, false},
+		{"TestCase: Surname, the other", args{Surname, AttributeValue{Encoding: IA5String}}, false, true},
+
+		{"TestCase: GivenName, PrintableString", args{GivenName, AttributeValue{Encoding: PrintableString}}, true, false},
+		{"TestCase: GivenName, UTF8String", args{GivenName, AttributeValue{Encoding: UTF8String}}, true, false},
+		{"TestCase: GivenName, the other", args{GivenName, AttributeValue{Encoding: IA5String}}, false, true},
+
+		{"TestCase: Initials, PrintableString", args{Initials, AttributeValue{Encoding: PrintableString}}, true, false},
+		{"TestCase: Initials, UTF8String", args{Initials, AttributeValue{Encoding: UTF8String}}, true, false},
+		{"TestCase: Initials, the other", args{Initials, AttributeValue{Encoding: IA5String}}, false, true},
+
+		{"TestCase: Pseudonym, PrintableString", args{Pseudonym, AttributeValue{Encoding: PrintableString}}, true, false},
+		{"TestCase: Pseudonym, UTF8String", args{Pseudonym, AttributeValue{Encoding: UTF8String}}, true, false},
+		{"TestCase: Pseudonym, the other", args{Pseudonym, AttributeValue{Encoding: IA5String}}, false, true},
+
+		{"TestCase: GenerationQualifier, PrintableString", args{GenerationQualifier, AttributeValue{Encoding: PrintableString}}, true, false},
+		{"TestCase: GenerationQualifier, UTF8String", args{GenerationQualifier, AttributeValue{Encoding: UTF8String}}, true, false},
+		{"TestCase: GenerationQualifier, the other", args{GenerationQualifier, AttributeValue{Encoding: IA5String}}, false, true},
+
+		{"TestCase: ElectronicMailAddress, IA5String", args{ElectronicMailAddress, AttributeValue{Encoding: IA5String}}, true, false},
+		{"TestCase: ElectronicMailAddress, the other", args{ElectronicMailAddress, AttributeValue{Encoding: UTF8String}}, false, true},
+
+		{"TestCase: DomainComponent, IA5String", args{DomainComponent, AttributeValue{Encoding: IA5String}}, true, false},
+		{"TestCase: DomainComponent, the other", args{DomainComponent, AttributeValue{Encoding: UTF8String}}, false, true},
+
+		{"TestCase: UnstructuredName, IA5String", args{UnstructuredName, AttributeValue{Encoding: IA5String}}, true, false},
+		{"TestCase: UnstructuredName, UTF8String", args{UnstructuredName, AttributeValue{Encoding: UTF8String}}, true, false},
+		{"TestCase: UnstructuredName, the other", args{UnstructuredName, AttributeValue{Encoding: PrintableString}}, false, true},
+
+		{"TestCase: UnstructuredAddress, PrintableString", args{UnstructuredAddress, AttributeValue{Encoding: PrintableString}}, true, false},
+		{"TestCase: UnstructuredAddress, UTF8String", args{UnstructuredAddress, AttributeValue{Encoding: UTF8String}}, true, false},
+		{"TestCase: UnstructuredAddress, the other", args{UnstructuredAddress, AttributeValue{Encoding: IA5String}}, false, true},
+
+		{"TestCase: StreetAddress, UTF8String", args{StreetAddress, AttributeValue{Encoding: UTF8String}}, true, false},
+		{"TestCase: StreetAddress, BMPString", args{StreetAddress, AttributeValue{Encoding: BMPString}}, true, false},
+		{"TestCase: StreetAddress, the other", args{StreetAddress, AttributeValue{Encoding: IA5String}}, false, true},
+
+		{"TestCase: PostalCode, PrintableString", args{PostalCode, AttributeValue{Encoding: PrintableString}}, true, false},
+		{"TestCase: PostalCode, TeletexString", args{PostalCode, AttributeValue{Encoding: TeletexString}}, true, false},
+		{"TestCase: PostalCode, the other", args{PostalCode, AttributeValue{Encoding: IA5String}}, false, true},
+
+		{"TestCase: UserID, IA5String", args{UserID, AttributeValue{Encoding: IA5String}}, true, false},
+		{"TestCase: UserID, UTF8String", args{UserID, AttributeValue{Encoding: UTF8String}}, true, false},
+		{"TestCase: UserID, PrintableString", args{UserID, AttributeValue{Encoding: PrintableString}}, true, false},
+		{"TestCase: UserID, the other", args{UserID, AttributeValue{Encoding: OIDValue}}, false, true},
+
+		{"TestCase: Generic, IA5String", args{Generic, AttributeValue{Encoding: IA5String}}, true, false},
+		{"TestCase: Generic, UTF8String", args{Generic, AttributeValue{Encoding: UTF8String}}, true, false},
+		{"TestCase: Generic, PrintableString", args{Generic, AttributeValue{Encoding: PrintableString}}, true, false},
+		{"TestCase: Generic, OIDValue", args{Generic, AttributeValue{Encoding: OIDValue}}, true, false},
+		{"TestCase: Generic, BMPString", args{Generic, AttributeValue{Encoding: BMPString}}, true, false},
+		{"TestCase: Generic, TeletexString", args{Generic, AttributeValue{Encoding: TeletexString}}, true, false},
+		{"TestCase: Generic, the other", args{Generic, AttributeValue{Encoding: 999}}, false, true},
+
+		{"TestCase: UnKnown, UTF8String", args{999, AttributeValue{Encoding: UTF8String}}, false, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotIsValid, err := isValidAttributeTypeAndAttributeValueComb(tt.args.at, tt.args.av)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("isValidAttributeTypeAndAttributeValueComb() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if gotIsValid != tt.wantIsValid {
+				t.Errorf("isValidAttributeTypeAndAttributeValueComb() gotIsValid = %v, want %v", gotIsValid, tt.wantIsValid)
+			}
+		})
+	}
+}
+
+func Test_isPrintableStringEncoding(t *testing.T) {
+	type args struct {
+		e Encoding
+	}
+	tests := []struct {
+		name   string
+		args   args
+		wantOk bool
+	}{
+		{"TestCase: PrintableString", args{PrintableString}, true},
+		{"TestCase: UTF8String", args{UTF8String}, false},
+		{"TestCase: IA5String", args{IA5String}, false},
+		{"TestCase: The other", args{9999}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if gotOk := isPrintableStringEncoding(tt.args.e); gotOk != tt.wantOk {
+				t.Errorf("isPrintableStringEncoding() = %v, want %v", gotOk, tt.wantOk)
+			}
+		})
+	}
+}
+
+func Test_isIA5StringEncoding(t *testing.T) {
+	type args struct {
+		e Encoding
+	}
+	tests := []struct {
+		name   string
+		args   args
+		wantOk bool
+	}{
+		{"TestCase: IA5String", args{IA5String}, true},
+		{"TestCase: UTF8String", args{UTF8String}, false},
+		{"TestCase: The other", args{9999}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if gotOk := isIA5StringEncoding(tt.args.e); gotOk != tt.wantOk {
+				t.Errorf("isIA5StringEncoding() = %v, want %v", gotOk, tt.wantOk)
+			}
+		})
+	}
+}
+
+func Test_isDirectoryStringEncoding(t *testing.T) {
+	type args struct {
+		e Encoding
+	}
+	tests := []struct {
+		name   string
+		args   args
+		wantOk bool
+	}{
+		{"TestCase: PrintableString", args{PrintableString}, true},
+		{"TestCase: UTF8String", args{UTF8String}, true},
+		{"TestCase: BMPString", args{BMPString}, true},
+		{"TestCase: TeletexString", args{TeletexString}, true},
+		{"TestCase: IA5String", args{IA5String}, false},
+		{"TestCase: OIDValue", args{OIDValue}, false},
+		{"TestCase: The other", args{9999}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if gotOk := isDirectoryStringEncoding(tt.args.e); gotOk != tt.wantOk {
+				t.Errorf("isDirectoryStringEncoding() = %v, want %v", gotOk, tt.wantOk)
+			}
+		})
+	}
+}
+
+func Test_isPrintableStringOrUTF8StringOrIA5StringEncoding(t *testing.T) {
+	type args struct {
+		e Encoding
+	}
+	tests := []struct {
+		name   string
+		args   args
+		wantOk bool
+	}{
+		{"TestCase: PrintableString", args{PrintableString}, true},
+		{"TestCase: UTF8String", args{UTF8String}, true},
+		{"TestCase: IA5String", args{IA5String}, true},
+		{"TestCase: The other", args{9999}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if gotOk := isPrintableStringOrUTF8StringOrIA5StringEncoding(tt.args.e); gotOk != tt.wantOk {
+				t.Errorf("isPrintableStringOrUTF8StringOrIA5StringEncoding() = %v, want %v", gotOk, tt.wantOk)
+			}
+		})
+	}
+}
+
+func Test_isValidAttributeValueEncoding(t *testing.T) {
+	type args struct {
+		av AttributeValue
+	}
+	tests := []struct {
+		name        string
+		args        args
+		wantIsValid bool
+		wantErr     bool
+	}{
+		{"TestCase: PrintableString", args{AttributeValue{Encoding: PrintableString}}, true, false},
+		{"TestCase: UTF8String", args{AttributeValue{Encoding: UTF8String}}, true, false},
+		{"TestCase: IA5String", args{AttributeValue{Encoding: IA5String}}, true, false},
+		{"TestCase: OIDValue", args{AttributeValue{Encoding: OIDValue, Value: "1.2.3"}}, true, false},
+		{"TestCase: BMPString", args{AttributeValue{Encoding: BMPString}}, true, false},
+		{"TestCase: TeletexString", args{AttributeValue{Encoding: TeletexString}}, true, false},
+		{"TestCase: OIDValue, broken oid", args{AttributeValue{Encoding: OIDValue, Value: "a.b"}}, false, true},
+		{"TestCase: The other", args{AttributeValue{Encoding: 999}}, false, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotIsValid, err := isValidAttributeValueEncoding(tt.args.av)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("isValidAttributeValueEncoding() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if gotIsValid != tt.wantIsValid {
+				t.Errorf("isValidAttributeValueEncoding() gotIsValid = %v, want %v", gotIsValid, tt.wantIsValid)
+			}
+		})
+	}
+}
+
+func Test_isValidAttributeTypeAndValue(t *testing.T) {
+	type args struct {
+		atv       AttributeTypeAndValue
+		checkComb bool
+	}
+	tests := []struct {
+		name        string
+		args        args
+		wantIsValid bool
+		wantErr     bool
+	}{
+		{"TestCase: CountryName, PrintableString", args{AttributeTypeAndValue{Type: CountryName, Value: AttributeValue{Encoding: PrintableString, Value: "JP"}}, true}, true, false},
+		{"TestCase: CountryName, 3 characters, length is not checked", args{AttributeTypeAndValue{Type: CountryName, Value: AttributeValue{Encoding: PrintableString, Value: "JPN"}}, true}, true, false},
+		{"TestCase: CommonName, 65 characters, length is not checked", args{AttributeTypeAndValue{Type: CommonName, Value: AttributeValue{Encoding: UTF8String, Value: strings.Repeat("a", 65)}}, true}, true, false},
+		{"TestCase: The other, PrintableString", args{AttributeTypeAndValue{Type: 999, Value: AttributeValue{Encoding: PrintableString}}, true}, false, true},
+		{"TestCase: CountryName, The other", args{AttributeTypeAndValue{Type: CountryName, Value: AttributeValue{Encoding: 999}}, true}, false, true},
+		{"TestCase: CountryName, UTF8String", args{AttributeTypeAndValue{Type: CountryName, Value: AttributeValue{Encoding: UTF8String}}, true}, false, true},
+		{"TestCase: Generic, PrintableString", args{AttributeTypeAndValue{Type: Generic, Oid: "1.2", Value: AttributeValue{Encoding: PrintableString}}, true}, true, false},
+		{"TestCase: Generic, The other", args{AttributeTypeAndValue{Type: Generic, Oid: "1.2", Value: AttributeValue{Encoding: 999}}, true}, false, true},
+		{"TestCase: Generic(CountryName), UTF8String", args{AttributeTypeAndValue{Type: Generic, Oid: "2.5.4.6", Value: AttributeValue{Encoding: UTF8String}}, true}, false, true},
+		{"TestCase: CountryName, UTF8String without checkComb", args{AttributeTypeAndValue{Type: CountryName, Value: AttributeValue{Encoding: UTF8String}}, false}, true, false},
+		{"TestCase: Generic(CountryName), UTF8String without checkComb", args{AttributeTypeAndValue{Type: Generic, Oid: "2.5.4.6", Value: AttributeValue{Encoding: UTF8String}}, false}, true, false},
+		{"TestCase: CountryName, The other without checkComb", args{AttributeTypeAndValue{Type: CountryName, Value: AttributeValue{Encoding: 999}}, false}, false, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotIsValid, err := isValidAttributeTypeAndValue(tt.args.atv, tt.args.checkComb)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("isValidAttributeTypeAndValue() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if gotIsValid != tt.wantIsValid {
+				t.Errorf("isValidAttributeTypeAndValue() gotIsValid = %v, want %v", gotIsValid, tt.wantIsValid)
+			}
+		})
+	}
+}
+
+func Test_isValidAttributeValueLength(t *testing.T) {
+	type args struct {
+		at AttributeType
+		av AttributeValue
+	}
+	tests := []struct {
+		name        string
+		args        args
+		wantIsValid bool
+		wantErr     string
+	}{
+		{"TestCase: CountryName 2 characters", args{CountryName, AttributeValue{PrintableString, "JP"}}, true, ""},
+		{"TestCase: CountryName 0 characters", args{CountryName, AttributeValue{PrintableString, ""}}, false, "CountryName’s value should be 2 characters, but 0 characters"},
+		{"TestCase: CountryName 3 characters", args{CountryName, AttributeValue{PrintableString, "JPN"}}, false, "CountryName’s value should be 2 characters, but 3 characters"},
+		{"TestCase: CommonName 64 characters", args{CommonName, AttributeValue{UTF8String, strings.Repeat("a", 64)}}, true, ""},
+		{"TestCase: CommonName 64 multibyte characters", args{CommonName, AttributeValue{UTF8String, strings.Repeat("日", 64)}}, true, ""},
+		{"TestCase: CommonName 65 characters", args{CommonName, AttributeValue{UTF8String, strings.Repeat("a", 65)}}, false, "CommonName’s value should be at most 64 characters, but 65 characters"},
+		{"TestCase: CommonName 0 characters", args{CommonName, AttributeValue{UTF8String, ""}}, true, ""},
+		{"TestCase: LocalityName 129 characters", args{LocalityName, AttributeValue{UTF8String, strings.Repeat("a", 129)}}, false, "LocalityName’s value should be at most 128 characters, but 129 characters"},
+		{"TestCase: SerialNumber 65 characters", args{SerialNumber, AttributeValue{PrintableString, strings.Repeat("1", 65)}}, false, "SerialNumber’s value should be at most 64 characters, but 65 characters"},
+		{"TestCase: ElectronicMailAddress 256 characters", args{ElectronicMailAddress, AttributeValue{IA5String, strings.Repeat("a", 256)}}, false, "ElectronicMailAddress’s value should be at most 255 characters, but 256 characters"},
+		{"TestCase: DomainComponent has no bound", args{DomainComponent, AttributeValue{IA5String, strings.Repeat("a", 1000)}}, true, ""},
+		{"TestCase: Generic has no bound", args{Generic, AttributeValue{UTF8String, strings.Repeat("a", 1000)}}, true, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotIsValid, err := isValidAttributeValueLength(tt.args.at, tt.args.av)
+			if gotIsValid != tt.wantIsValid {
+				t.Errorf("isValidAttributeValueLength() gotIsValid = %v, want %v", gotIsValid, tt.wantIsValid)
+			}
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("isValidAttributeValueLength() error = %v, want nil", err)
+				}
+			} else if err == nil || err.Error() != tt.wantErr {
+				t.Errorf("isValidAttributeValueLength() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_isValidRDN(t *testing.T) {
+	atv1 := AttributeTypeAndValue{Type: CountryName, Value: AttributeValue{Encoding: PrintableString, Value: "JP"}}
+	atv2 := AttributeTypeAndValue{Type: Generic, Oid: "1.2.3", Value: AttributeValue{Encoding: UTF8String}}
+	atv3 := AttributeTypeAndValue{Type: CountryName, Value: AttributeValue{Encoding: UTF8String}}
+	atv4 := AttributeTypeAndValue{Type: ElectronicMailAddress, Value: AttributeValue{Encoding: UTF8String}}
+	type args struct {
+		r         RDN
+		checkComb bool
+	}
+	tests := []struct {
+		name        string
+		args        args
+		wantIsValid bool
+		wantErr     bool
+	}{
+		{"TestCase: 0 AttributeTypeAndValue element", args{RDN{}, true}, false, true},
+		{"TestCase: 1 AttributeTypeAndValue element", args{RDN{atv1}, true}, true, false},
+		{"TestCase: 2 AttributeTypeAndValue element", args{RDN{atv1, atv2}, true}, true, false},
+		{"TestCase: 1 invalid AttributeTypeAndValue element", args{RDN{atv3}, true}, false, true},
+		{"TestCase: 2 invalid AttributeTypeAndValue element", args{RDN{atv3, atv4}, true}, false, true},
+		{"TestCase: 2 mismatched AttributeTypeAndValue element without checkComb", args{RDN{atv3, atv4}, false}, true, false},
+		{"TestCase: 0 AttributeTypeAndValue element without checkComb", args{RDN{}, false}, false, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotIsValid, err := isValidRDN(tt.args.r, tt.args.checkComb)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("isValidRDN() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if gotIsValid != tt.wantIsValid {
+				t.Errorf("isValidRDN() gotIsValid = %v, want %v", gotIsValid, tt.wantIsValid)
+			}
+		})
+	}
+}
+
+func Test_isValidDN(t *testing.T) {
+	atv1 := AttributeTypeAndValue{Type: CountryName, Value: AttributeValue{Encoding: PrintableString, Value: "JP"}}
+	atv2 := AttributeTypeAndValue{Type: Generic, Oid: "1.2.3", Value: AttributeValue{Encoding: UTF8String}}
+	atv3 := AttributeTypeAndValue{Type: CountryName, Value: AttributeValue{Encoding: UTF8String}}
+	atv4 := AttributeTypeAndValue{Type: ElectronicMailAddress, Value: AttributeValue{Encoding: UTF8String}}
+	rdn1 := RDN{atv1, atv2}
+	rdn2 := RDN{atv2}
+	rdn3 := RDN{atv1, atv3}
+	rdn4 := RDN{atv4}
+
+	type args struct {
+		d DN
+	}
+	tests := []struct {
+		name        string
+		args        args
+		wantIsValid bool
+		wantErr     bool
+	}{
+		{"TestCase: 0 RDN element", args{DN{}}, true, false},
+		{"TestCase: 1 RDN element", args{DN{rdn1}}, true, false},
+		{"TestCase: 2 RDN element", args{DN{rdn1, rdn2}}, true, false},
+		{"TestCase: 1 invalid RDN element", args{DN{rdn3}}, false, true},
+		{"TestCase: 2 invalid RDN element", args{DN{rdn3, rdn4}}, false, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotIsValid, err := isValidDN(tt.args.d)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("isValidDN() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if gotIsValid != tt.wantIsValid {
+				t.Errorf("isValidDN() gotIsValid = %v, want %v", gotIsValid, tt.wantIsValid)
+			}
+		})
+	}
+}
+
+func Test_isValidDNIgnoringEncoding(t *testing.T) {
+	atv1 := AttributeTypeAndValue{Type: CountryName, Value: AttributeValue{Encoding: PrintableString}}
+	atv2 := AttributeTypeAndValue{Type: CountryName, Value: AttributeValue{Encoding: UTF8String}}
+	atv3 := AttributeTypeAndValue{Type: Generic, Oid: "1..2", Value: AttributeValue{Encoding: UTF8String}}
+	tests := []struct {
+		name        string
+		d           DN
+		wantIsValid bool
+		wantErr     bool
+	}{
+		{"TestCase: 0 RDN", DN{}, true, false},
+		{"TestCase: valid", DN{RDN{atv1}}, true, false},
+		{"TestCase: mismatched encoding", DN{RDN{atv1}, RDN{atv2}}, true, false},
+		{"TestCase: invalid oid", DN{RDN{atv3}}, false, true},
+		{"TestCase: empty RDN", DN{RDN{}}, false, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotIsValid, err := isValidDNIgnoringEncoding(tt.d)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("isValidDNIgnoringEncoding() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if gotIsValid != tt.wantIsValid {
+				t.Errorf("isValidDNIgnoringEncoding() gotIsValid = %v, want %v", gotIsValid, tt.wantIsValid)
+			}
+		})
+	}
+}
+
+func TestDN_CountAttributeType(t *testing.T) {
+	c := AttributeTypeAndValue{Type: CountryName, Value: AttributeValue{PrintableString, "JP"}}
+	gc := AttributeTypeAndValue{Type: Generic, Oid: "2.5.4.6", Value: AttributeValue{PrintableString, "JP"}}
+	ou := AttributeTypeAndValue{Type: OrganizationalUnit, Value: AttributeValue{UTF8String, "Sales"}}
+	g := AttributeTypeAndValue{Type: Generic, Oid: "1.2.3.4", Value: AttributeValue{UTF8String, "x"}}
+	type args struct {
+		at AttributeType
+	}
+	tests := []struct {
+		name string
+		d    DN
+		args args
+		want int
+	}{
+		{"TestCase: 0 RDN", DN{}, args{CountryName}, 0},
+		{"TestCase: 1 CountryName", DN{RDN{c}, RDN{ou}}, args{CountryName}, 1},
+		{"TestCase: CountryName and Generic(CountryName)", DN{RDN{c}, RDN{gc}}, args{CountryName}, 2},
+		{"TestCase: OUs in multi value RDN", DN{RDN{c}, RDN{ou, ou}, RDN{ou}}, args{OrganizationalUnit}, 3},
+		{"TestCase: Generic", DN{RDN{gc}, RDN{g}}, args{Generic}, 1},
+		{"TestCase: not found", DN{RDN{c}}, args{CommonName}, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.d.CountAttributeType(tt.args.at); got != tt.want {
+				t.Errorf("CountAttributeType() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDN_CountOid(t *testing.T) {
+	c := AttributeTypeAndValue{Type: CountryName, Value: AttributeValue{PrintableString, "JP"}}
+	gc := AttributeTypeAndValue{Type: Generic, Oid: "2.5.4.6", Value: AttributeValue{PrintableString, "JP"}}
+	g := AttributeTypeAndValue{Type: Generic, Oid: "1.2.3.4", Value: AttributeValue{UTF8String, "x"}}
+	type args struct {
+		oid string
+	}
+	tests := []struct {
+		name string
+		d    DN
+		args args
+		want int
+	}{
+		{"TestCase: 0 RDN", DN{}, args{"2.5.4.6"}, 0},
+		{"TestCase: CountryName and Generic(CountryName)", DN{RDN{c}, RDN{gc}}, args{"2.5.4.6"}, 2},
+		{"TestCase: Generic in multi value RDN", DN{RDN{c}, RDN{g, g}}, args{"1.2.3.4"}, 2},
+		{"TestCase: not found", DN{RDN{c}}, args{"1.2.3.4"}, 0},
+		{"TestCase: invalid oid", DN{RDN{c}}, args{"2.5.a"}, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.d.CountOid(tt.args.oid); got != tt.want {
+				t.Errorf("CountOid() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDN_HasAttributeType(t *testing.T) {
+	c := AttributeTypeAndValue{Type: CountryName, Value: AttributeValue{PrintableString, "JP"}}
+	gcn := AttributeTypeAndValue{Type: Generic, Oid: "2.5.4.3", Value: AttributeValue{UTF8String, "Mike"}}
+	email := AttributeTypeAndValue{Type: ElectronicMailAddress, Value: AttributeValue{IA5String, "mike@example.org"}}
+	type args struct {
+		at AttributeType
+	}
+	tests := []struct {
+		name string
+		d    DN
+		args args
+		want bool
+	}{
+		{"TestCase: 0 RDN", DN{}, args{CountryName}, false},
+		{"TestCase: found", DN{RDN{c}}, args{CountryName}, true},
+		{"TestCase: found in multi value RDN", DN{RDN{c}, RDN{gcn, email}}, args{ElectronicMailAddress}, true},
+		{"TestCase: Generic(CommonName)", DN{RDN{c}, RDN{gcn}}, args{CommonName}, true},
+		{"TestCase: not found", DN{RDN{c}, RDN{email}}, args{CommonName}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.d.HasAttributeType(tt.args.at); got != tt.want {
+				t.Errorf("HasAttributeType() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDN_HasOid(t *testing.T) {
+	c := AttributeTypeAndValue{Type: CountryName, Value: AttributeValue{PrintableString, "JP"}}
+	cn := AttributeTypeAndValue{Type: CommonName, Value: AttributeValue{UTF8String, "Mike"}}
+	g := AttributeTypeAndValue{Type: Generic, Oid: "1.2.3.4", Value: AttributeValue{UTF8String, "x"}}
+	type args struct {
+		oid string
+	}
+	tests := []struct {
+		name string
+		d    DN
+		args args
+		want bool
+	}{
+		{"TestCase: 0 RDN", DN{}, args{"2.5.4.6"}, false},
+		{"TestCase: CountryName", DN{RDN{c}, RDN{cn}}, args{"2.5.4.6"}, true},
+		{"TestCase: Generic in multi value RDN", DN{RDN{c}, RDN{cn, g}}, args{"1.2.3.4"}, true},
+		{"TestCase: not found", DN{RDN{c}}, args{"2.5.4.3"}, false},
+		{"TestCase: invalid oid", DN{RDN{c}}, args{"2.5.a"}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.d.HasOid(tt.args.oid); got != tt.want {
+				t.Errorf("HasOid() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDN_AttributeTypes(t *testing.T) {
+	c := AttributeTypeAndValue{Type: CountryName, Value: AttributeValue{PrintableString, "JP"}}
+	ou := AttributeTypeAndValue{Type: OrganizationalUnit, Value: AttributeValue{UTF8String, "Dev"}}
+	gou := AttributeTypeAndValue{Type: Generic, Oid: "2.5.4.11", Value: AttributeValue{UTF8String, "Sales"}}
+	g := AttributeTypeAndValue{Type: Generic, Oid: "1.2.3.4", Value: AttributeValue{UTF8String, "x"}}
+	cn := AttributeTypeAndValue{Type: CommonName, Value: AttributeValue{UTF8String, "Mike"}}
+	tests := []struct {
+		name string
+		d    DN
+		want []AttributeType
+	}{
+		{"TestCase: 0 RDN", DN{}, []AttributeType{}},
+		{"TestCase: nil DN", nil, []AttributeType{}},
+		{"TestCase: duplicated types", DN{RDN{c}, RDN{ou}, RDN{gou}, RDN{cn}}, []AttributeType{CountryName, OrganizationalUnit, OrganizationalUnit, CommonName}},
+		{"TestCase: Generic and multi value RDN", DN{RDN{c}, RDN{cn, g}}, []AttributeType{CountryName, CommonName, Generic}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.d.AttributeTypes(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("AttributeTypes() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDN_LeafLabel(t *testing.T) {
+	c := RDN{AttributeTypeAndValue{Type: CountryName, Value: AttributeValue{PrintableString, "JP"}}}
+	ou := RDN{
+		AttributeTypeAndValue{Type: OrganizationalUnit, Value: AttributeValue{UTF8String, "Dev"}},
+		AttributeTypeAndValue{Type: OrganizationalUnit, Value: AttributeValue{UTF8String, "Sales"}},
+	}
+	cn := RDN{AttributeTypeAndValue{Type: CommonName, Value: AttributeValue{UTF8String, "Smith, John"}}}
+	tests := []struct {
+		name string
+		d    DN
+		want string
+	}{
+		{"TestCase: 0 RDN", DN{}, ""},
+		{"TestCase: 1 RDN", DN{c}, "C=JP"},
+		{"TestCase: leaf CN", DN{c, ou, cn}, "CN=Smith\\, John"},
+		{"TestCase: leaf multi value RDN", DN{c, ou}, "OU=Dev+OU=Sales"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.d.LeafLabel(); got != tt.want {
+				t.Errorf("LeafLabel() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDN_RetrieveRDN(t *testing.T) {
+	atv1 := AttributeTypeAndValue{Type: CountryName, Value: AttributeValue{Encoding: PrintableString}}
+	atv2 := AttributeTypeAndValue{Type: CommonName, Value: AttributeValue{Encoding: UTF8String}}
+	type args struct {
+		index int
+	}
+	tests := []struct {
+		name    string
+		d       DN
+		args    args
+		wantRdn RDN
+		wantErr bool
+	}{
+		{"TestCase: 0 RDN element index 0", DN{}, args{0}, RDN{}, true},
+		{"TestCase: 1 RDN element index 0", DN{RDN{atv1}}, args{0}, RDN{atv1}, false},
+		{"TestCase: 1 RDN element index 1", DN{RDN{atv1}}, args{1}, RDN{}, true},
+		{"TestCase: 2 RDN element index -1", DN{RDN{atv1}, RDN{atv2}}, args{-1}, RDN{}, true},
+		{"TestCase: 2 RDN element index 0", DN{RDN{atv1}, RDN{atv2}}, args{0}, RDN{atv1}, false},
+		{"TestCase: 2 RDN element index 1", DN{RDN{atv1}, RDN{atv2}}, args{1}, RDN{atv2}, false},
+		{"TestCase: 2 RDN element index 2", DN{RDN{atv1}, RDN{atv2}}, args{2}, RDN{}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotRdn, err := tt.d.RetrieveRDN(tt.args.index)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("RetrieveRDN() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(gotRdn, tt.wantRdn) {
+				t.Errorf("RetrieveRDN() gotRdn = %v, want %v", gotRdn, tt.wantRdn)
+			}
+		})
+	}
+}
+
+func TestDN_RemoveRDN(t *testing.T) {
+	rdn1 := RDN{AttributeTypeAndValue{Type: CountryName, Value: AttributeValue{PrintableString, "JP"}}}
+	rdn2 := RDN{AttributeTypeAndValue{Type: OrganizationName, Value: AttributeValue{UTF8String, "Example"}}}
+	rdn3 := RDN{AttributeTypeAndValue{Type: CommonName, Value: AttributeValue{UTF8String, "Mike"}}}
+	type args struct {
+		index int
+	}
+	tests := []struct {
+		name    string
+		d       DN
+		args    args
+		want    DN
+		wantErr bool
+	}{
+		{"TestCase: 0 RDN element index 0", DN{}, args{0}, DN{}, true},
+		{"TestCase: 1 RDN element index 0", DN{rdn1}, args{0}, DN{}, false},
+		{"TestCase: 3 RDN element index -1", DN{rdn1, rdn2, rdn3}, args{-1}, DN{}, true},
+		{"TestCase: 3 RDN element index 0", DN{rdn1, rdn2, rdn3}, args{0}, DN{rdn2, rdn3}, false},
+		{"TestCase: 3 RDN element index 1", DN{rdn1, rdn2, rdn3}, args{1}, DN{rdn1, rdn3}, false},
+		{"TestCase: 3 RDN element index 2", DN{rdn1, rdn2, rdn3}, args{2}, DN{rdn1, rdn2}, false},
+		{"TestCase: 3 RDN element index 3", DN{rdn1, rdn2, rdn3}, args{3}, DN{}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			org := tt.d.String()
+			got, err := tt.d.RemoveRDN(tt.args.index)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("RemoveRDN() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("RemoveRDN() = %v, want %v", got, tt.want)
+			}
+			if tt.d.String() != org {
+				t.Errorf("RemoveRDN() modified the DN: %v, want %v", tt.d, org)
+			}
+		})
+	}
+}
+
+func TestDN_InsertRDN(t *testing.T) {
+	rdn1 := RDN{AttributeTypeAndValue{Type: CountryName, Value: AttributeValue{PrintableString, "JP"}}}
+	rdn2 := RDN{AttributeTypeAndValue{Type: OrganizationName, Value: AttributeValue{UTF8String, "Example"}}}
+	rdn3 := RDN{AttributeTypeAndValue{Type: CommonName, Value: AttributeValue{UTF8String, "Mike"}}}
+	invalid := RDN{AttributeTypeAndValue{Type: CountryName, Value: AttributeValue{UTF8String, "JP"}}}
+	type args struct {
+		index int
+		rdn   RDN
+	}
+	tests := []struct {
+		name    string
+		d       DN
+		args    args
+		want    DN
+		wantErr bool
+	}{
+		{"TestCase: 0 RDN element index 0", DN{}, args{0, rdn1}, DN{rdn1}, false},
+		{"TestCase: 0 RDN element index 1", DN{}, args{1, rdn1}, DN{}, true},
+		{"TestCase: 2 RDN element index -1", DN{rdn2, rdn3}, args{-1, rdn1}, DN{}, true},
+		{"TestCase: 2 RDN element index 0", DN{rdn2, rdn3}, args{0, rdn1}, DN{rdn1, rdn2, rdn3}, false},
+		{"TestCase: 2 RDN element index 1", DN{rdn1, rdn3}, args{1, rdn2}, DN{rdn1, rdn2, rdn3}, false},
+		{"TestCase: 2 RDN element index 2", DN{rdn1, rdn2}, args{2, rdn3}, DN{rdn1, rdn2, rdn3}, false},
+		{"TestCase: 2 RDN element index 3", DN{rdn1, rdn2}, args{3, rdn3}, DN{}, true},
+		{"TestCase: empty RDN", DN{rdn2, rdn3}, args{0, RDN{}}, DN{}, true},
+		{"TestCase: invalid RDN", DN{rdn2, rdn3}, args{0, invalid}, DN{}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			org := tt.d.String()
+			got, err := tt.d.InsertRDN(tt.args.index, tt.args.rdn)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("InsertRDN() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("InsertRDN() = %v, want %v", got, tt.want)
+			}
+			if tt.d.String() != org {
+				t.Errorf("InsertRDN() modified the DN: %v, want %v", tt.d, org)
+			}
+		})
+	}
+}
+
+func TestRDN_RemoveAttributeTypeAndValue(t *testing.T) {
+	atv1 := AttributeTypeAndValue{Type: CommonName, Value: AttributeValue{UTF8String, "Mike"}}
+	atv2 := AttributeTypeAndValue{Type: ElectronicMailAddress, Value: AttributeValue{IA5String, "mike@example.org"}}
+	type args struct {
+		index int
+	}
+	tests := []struct {
+		name    string
+		r       RDN
+		args    args
+		want    RDN
+		wantErr bool
+	}{
+		{"TestCase: 0 AttributeTypeAndValue index 0", RDN{}, args{0}, RDN{}, true},
+		{"TestCase: 2 AttributeTypeAndValue index -1", RDN{atv1, atv2}, args{-1}, RDN{}, true},
+		{"TestCase: 2 AttributeTypeAndValue index 0", RDN{atv1, atv2}, args{0}, RDN{atv2}, false},
+		{"TestCase: 2 AttributeTypeAndValue index 1", RDN{atv1, atv2}, args{1}, RDN{atv1}, false},
+		{"TestCase: 2 AttributeTypeAndValue index 2", RDN{atv1, atv2}, args{2}, RDN{}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			org := tt.r.String()
+			got, err := tt.r.RemoveAttributeTypeAndValue(tt.args.index)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("RemoveAttributeTypeAndValue() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("RemoveAttributeTypeAndValue() = %v, want %v", got, tt.want)
+			}
+			if tt.r.String() != org {
+				t.Errorf("RemoveAttributeTypeAndValue() modified the RDN: %v, want %v", tt.r, org)
+			}
+		})
+	}
+}
+
+func TestDN_RetrieveRDNsByAttributeTypes(t *testing.T) {
+	atv1 := AttributeTypeAndValue{Type: CountryName, Value: AttributeValue{Encoding: PrintableString}}
+	atv2 := AttributeTypeAndValue{Type: CommonName, Value: AttributeValue{Encoding: UTF8String}}
+	atv3 := AttributeTypeAndValue{Type: OrganizationName, Value: AttributeValue{Encoding: UTF8String}}
+	atv4 := AttributeTypeAndValue{Type: OrganizationalUnit, Value: AttributeValue{Encoding: UTF8String}}
+	atv5 := AttributeTypeAndValue{Type: Generic, Oid: "1.2", Value: AttributeValue{Encoding: UTF8String}}
+	type args struct {
+		ats []AttributeType
+	}
+	tests := []struct {
+		name     string
+		d        DN
+		args     args
+		wantRdns []RDN
+	}{
+		{"TestCase: DN has 0 RDN, 0 AttributeType, not matched", DN{}, args{[]AttributeType{}}, []RDN{}},
+		{"TestCase: DN has 0 RDN, 1 AttributeType, not matched", DN{}, args{[]AttributeType{CountryName}}, []RDN{}},
+		{"TestCase: DN has 1 RDN, the RDN has 1 Attribute , 0 AttributeType, not matched", DN{RDN{atv1}}, args{[]AttributeType{}}, []RDN{}},
+		{"TestCase: DN has 1 RDN, the RDN has 1 Attribute , 1 AttributeType, matched", DN{RDN{atv1}}, args{[]AttributeType{CountryName}}, []RDN{RDN{atv1}}},
+		{"TestCase: DN has 1 RDN, the RDN has 1 Attribute , 1 Generic AttributeType, not matched", DN{RDN{atv1}}, args{[]AttributeType{Generic}}, []RDN{}},
+		{"TestCase: DN has 1 RDN, the RDN has 1 Attribute , 1 Generic AttributeType, matched", DN{RDN{atv5}}, args{[]AttributeType{Generic}}, []RDN{RDN{atv5}}},
+		{"TestCase: DN has 1 RDN, the RDN has 1 Attribute , 1 AttributeType, not matched", DN{RDN{atv1}}, args{[]AttributeType{OrganizationName}}, []RDN{}},
+		{"TestCase: DN has 2 RDN, the RDN has 1 Attribute , 1 AttributeType, 1 matched", DN{RDN{atv1}, RDN{atv2}}, args{[]AttributeType{CountryName}}, []RDN{RDN{atv1}}},
+		{"TestCase: DN has 2 RDN, the RDN has 2 Attribute , 2 AttributeType, 2 matched", DN{RDN{atv1, atv2}, RDN{atv1, atv2}}, args{[]AttributeType{CountryName, CommonName}}, []RDN{RDN{atv1, atv2}, RDN{atv1, atv2}}},
+		{"TestCase: DN has 2 RDN, the RDN has 2 Attribute , 2 AttributeType, 1 matched", DN{RDN{atv1, atv2}, RDN{atv3, atv4}}, args{[]AttributeType{CountryName, CommonName}}, []RDN{RDN{atv1, atv2}}},
+		{"TestCase: DN has 2 RDN, the RDN has 2 Attribute , 2 Generic AttributeType, 2 matched", DN{RDN{atv5, atv5}, RDN{atv5, atv5}}, args{[]AttributeType{Generic, Generic}}, []RDN{RDN{atv5, atv5}, RDN{atv5, atv5}}},
+		{"TestCase: DN has 2 RDN, the RDN has 2 Attribute , 2 Generic AttributeType, 1 matched", DN{RDN{atv1, atv2}, RDN{atv5, atv2}}, args{[]AttributeType{Generic, CommonName}}, []RDN{RDN{atv5, atv2}}},
+		{"TestCase: DN has 2 RDN, the RDN has 2 Attribute , 2 AttributeType, not matched", DN{RDN{atv1, atv2}, RDN{atv3, atv4}}, args{[]AttributeType{OrganizationName, CommonName}}, []RDN{}},
+		{"TestCase: DN has 2 RDN, the RDN has 2 Attribute , 3 AttributeType, not matched", DN{RDN{atv1, atv2}, RDN{atv3, atv4}}, args{[]AttributeType{CountryName, OrganizationName, CommonName}}, []RDN{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if gotRdns := tt.d.RetrieveRDNsByAttributeTypes(tt.args.ats); !reflect.DeepEqual(gotRdns, tt.wantRdns) {
+				t.Errorf("RetrieveRDNsByAttributeTypes() = %v, want %v", gotRdns, tt.wantRdns)
+			}
+		})
+	}
+}
+
+func TestDN_RetrieveRDNsByOids(t *testing.T) {
+	atv1 := AttributeTypeAndValue{Type: CountryName, Value: AttributeValue{Encoding: PrintableString}}
+	atv2 := AttributeTypeAndValue{Type: CommonName, Value: AttributeValue{Encoding: UTF8String}}
+	atv3 := AttributeTypeAndValue{Type: OrganizationName, Value: AttributeValue{Encoding: UTF8String}}
+	atv4 := AttributeTypeAndValue{Type: OrganizationalUnit, Value: AttributeValue{Encoding: UTF8String}}
+	atv5 := AttributeTypeAndValue{Type: Generic, Oid: "1.2", Value: AttributeValue{Encoding: UTF8String}}
+	atv6 := AttributeTypeAndValue{Type: Generic, Oid: "1.2.3", Value: AttributeValue{Encoding: UTF8String}}
+	type args struct {
+		oids []string
+	}
+	tests := []struct {
+		name     string
+		d        DN
+		args     args
+		wantRdns []RDN
+	}{
+		{"TestCase: DN has 0 RDN, 0 AttributeType, not matched", DN{}, args{[]string{}}, []RDN{}},
+		{"TestCase: DN has 0 RDN, 1 AttributeType, not matched", DN{}, args{[]string{"2.5.4.6"}}, []RDN{}},
+		{"TestCase: DN has 1 RDN, the RDN has 1 Attribute , 0 AttributeType, not matched", DN{RDN{atv1}}, args{[]string{}}, []RDN{}},
+		{"TestCase: DN has 1 RDN, the RDN has 1 Attribute , 1 AttributeType, matched", DN{RDN{atv1}}, args{[]string{"2.5.4.6"}}, []RDN{RDN{atv1}}},
+		{"TestCase: DN has 1 RDN, the RDN has 1 Attribute , 1 AttributeType, not matched", DN{RDN{atv1}}, args{[]string{"2.5.4.11"}}, []RDN{}},
+		{"TestCase: DN has 2 RDN, the RDN has 1 Attribute , 1 AttributeType, 1 matched", DN{RDN{atv1}, RDN{atv2}}, args{[]string{"2.5.4.6"}}, []RDN{RDN{atv1}}},
+		{"TestCase: DN has 2 RDN, the RDN has 2 Attribute , 2 AttributeType, 2 matched", DN{RDN{atv5, atv6}, RDN{atv6, atv5}}, args{[]string{"1.2", "1.2.3"}}, []RDN{RDN{atv5, atv6}, RDN{atv6, atv5}}},
+		{"TestCase: DN has 2 RDN, the RDN has 2 Attribute , 2 AttributeType, 1 matched", DN{RDN{atv5, atv6}, RDN{atv5, atv5}}, args{[]string{"1.2", "1.2.3"}}, []RDN{RDN{atv5, atv6}}},
+		{"TestCase: DN has 2 RDN, the RDN has 2 Attribute , 2 AttributeType, not matched", DN{RDN{atv1, atv2}, RDN{atv3, atv4}}, args{[]string{"2.5.4.10", "2.5.4.6"}}, []RDN{}},
+		{"TestCase: DN has 2 RDN, the RDN has 2 Attribute , 3 AttributeType, not matched", DN{RDN{atv1, atv2}, RDN{atv3, atv4}}, args{[]string{"2.5.4.6", "2.5.4.10", "2.5.4.6"}}, []RDN{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if gotRdns := tt.d.RetrieveRDNsByOids(tt.args.oids); !reflect.DeepEqual(gotRdns, tt.wantRdns) {
+				t.Errorf("RetrieveRDNsByOids() = %v, want %v", gotRdns, tt.wantRdns)
+			}
+		})
+	}
+}
+
+func TestDN_RetrieveRDNsByAttributeValue(t *testing.T) {
+	c := RDN{AttributeTypeAndValue{Type: CountryName, Value: AttributeValue{PrintableString, "JP"}}}
+	ou1 := RDN{AttributeTypeAndValue{Type: OrganizationalUnit, Value: AttributeValue{UTF8String, "Engineering"}}}
+	ou2 := RDN{AttributeTypeAndValue{Type: OrganizationalUnit, Value: AttributeValue{UTF8String, "Sales"}}}
+	ou3 := RDN{AttributeTypeAndValue{Type: OrganizationalUnit, Value: AttributeValue{PrintableString, " ENGINEERING "}}}
+	ou4 := RDN{AttributeTypeAndValue{Type: Generic, Oid: "2.5.4.11", Value: AttributeValue{UTF8String, "engineering"}}}
+	cn := RDN{
+		AttributeTypeAndValue{Type: CommonName, Value: AttributeValue{UTF8String, "Mike"}},
+		AttributeTypeAndValue{Type: OrganizationalUnit, Value: AttributeValue{UTF8String, "Engineering"}},
+	}
+	g := RDN{AttributeTypeAndValue{Type: Generic, Oid: "1.2.3", Value: AttributeValue{UTF8String, "Abc"}}}
+	sn := RDN{AttributeTypeAndValue{Type: SerialNumber, Value: AttributeValue{PrintableString, "AB12"}}}
+	type args struct {
+		at    AttributeType
+		value string
+	}
+	tests := []struct {
+		name     string
+		d        DN
+		args     args
+		wantRdns []RDN
+	}{
+		{"TestCase: DN has 0 RDN", DN{}, args{OrganizationalUnit, "Engineering"}, []RDN{}},
+		{"TestCase: multiple OUs, 1 matched", DN{c, ou1, ou2}, args{OrganizationalUnit, "Engineering"}, []RDN{ou1}},
+		{"TestCase: multiple OUs, 2 matched regardless of case, spaces and encoding", DN{c, ou1, ou2, ou3}, args{OrganizationalUnit, "engineering"}, []RDN{ou1, ou3}},
+		{"TestCase: Generic with known oid matched", DN{c, ou4, ou2}, args{OrganizationalUnit, "Engineering"}, []RDN{ou4}},
+		{"TestCase: multi-valued RDN matched", DN{c, ou2, cn}, args{OrganizationalUnit, "Engineering"}, []RDN{cn}},
+		{"TestCase: value of other AttributeType not matched", DN{c, ou1, cn}, args{CommonName, "Engineering"}, []RDN{}},
+		{"TestCase: not matched", DN{c, ou1, ou2}, args{OrganizationalUnit, "Marketing"}, []RDN{}},
+		{"TestCase: Generic matched exactly", DN{c, g}, args{Generic, "Abc"}, []RDN{g}},
+		{"TestCase: Generic not matched with different case", DN{c, g}, args{Generic, "abc"}, []RDN{}},
+		{"TestCase: SerialNumber matched with spaces", DN{c, sn}, args{SerialNumber, " AB12 "}, []RDN{sn}},
+		{"TestCase: SerialNumber not matched with different case", DN{c, sn}, args{SerialNumber, "ab12"}, []RDN{}},
+		{"TestCase: CountryName matched with different case", DN{c, sn}, args{CountryName, "jp"}, []RDN{c}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if gotRdns := tt.d.RetrieveRDNsByAttributeValue(tt.args.at, tt.args.value); !reflect.DeepEqual(gotRdns, tt.wantRdns) {
+				t.Errorf("RetrieveRDNsByAttributeValue() = %v, want %v", gotRdns, tt.wantRdns)
+			}
+		})
+	}
+}
+
+func TestDN_RetrieveValuesByAttributeType(t *testing.T) {
+	c := AttributeTypeAndValue{Type: CountryName, Value: AttributeValue{PrintableString, "JP"}}
+	ou1 := AttributeTypeAndValue{Type: OrganizationalUnit, Value: AttributeValue{UTF8String, "Engineering"}}
+	ou2 := AttributeTypeAndValue{Type: OrganizationalUnit, Value: AttributeValue{PrintableString, "Backend"}}
+	gou := AttributeTypeAndValue{Type: Generic, Oid: "2.5.4.11", Value: AttributeValue{UTF8String, "Sales"}}
+	cn := AttributeTypeAndValue{Type: CommonName, Value: AttributeValue{UTF8String, "Mike"}}
+	type args struct {
+		at AttributeType
+	}
+	tests := []struct {
+		name string
+		d    DN
+		args args
+		want []string
+	}{
+		{"TestCase: 0 RDN", DN{}, args{OrganizationalUnit}, []string{}},
+		{"TestCase: not found", DN{RDN{c}, RDN{cn}}, args{OrganizationalUnit}, []string{}},
+		{"TestCase: single value", DN{RDN{c}, RDN{cn}}, args{CommonName}, []string{"Mike"}},
+		{"TestCase: multiple RDNs and multi value RDN", DN{RDN{c}, RDN{ou1}, RDN{cn, ou2}}, args{OrganizationalUnit}, []string{"Engineering", "Backend"}},
+		{"TestCase: Generic(OrganizationalUnit)", DN{RDN{c}, RDN{ou1}, RDN{gou}}, args{OrganizationalUnit}, []string{"Engineering", "Sales"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.d.RetrieveValuesByAttributeType(tt.args.at); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("RetrieveValuesByAttributeType() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDN_RetrieveValuesByOid(t *testing.T) {
+	c := AttributeTypeAndValue{Type: CountryName, Value: AttributeValue{PrintableString, "JP"}}
+	ou := AttributeTypeAndValue{Type: OrganizationalUnit, Value: AttributeValue{UTF8String, "Engineering"}}
+	gou := AttributeTypeAndValue{Type: Generic, Oid: "2.5.4.11", Value: AttributeValue{UTF8String, "Sales"}}
+	g1 := AttributeTypeAndValue{Type: Generic, Oid: "1.2.3.4", Value: AttributeValue{UTF8String, "x"}}
+	g2 := AttributeTypeAndValue{Type: Generic, Oid: "1.2.3.4", Value: AttributeValue{UTF8String, "y"}}
+	type args struct {
+		oid string
+	}
+	tests := []struct {
+		name string
+		d    DN
+		args args
+		want []string
+	}{
+		{"TestCase: 0 RDN", DN{}, args{"2.5.4.11"}, []string{}},
+		{"TestCase: OrganizationalUnit and Generic(OrganizationalUnit)", DN{RDN{c}, RDN{ou}, RDN{gou}}, args{"2.5.4.11"}, []string{"Engineering", "Sales"}},
+		{"TestCase: Generic in multi value RDN", DN{RDN{c}, RDN{g1, g2}}, args{"1.2.3.4"}, []string{"x", "y"}},
+		{"TestCase: not found", DN{RDN{c}}, args{"1.2.3.4"}, []string{}},
+		{"TestCase: invalid oid", DN{RDN{c}}, args{"2.5.a"}, []string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.d.RetrieveValuesByOid(tt.args.oid); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("RetrieveValuesByOid() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDN_AttributesWithEncoding(t *testing.T) {
+	atv1 := AttributeTypeAndValue{Type: CountryName, Value: AttributeValue{Encoding: PrintableString, Value: "JP"}}
+	atv2 := AttributeTypeAndValue{Type: OrganizationName, Value: AttributeValue{Encoding: PrintableString, Value: "example"}}
+	atv3 := AttributeTypeAndValue{Type: CommonName, Value: AttributeValue{Encoding: UTF8String, Value: "ex"}}
+	atv4 := AttributeTypeAndValue{Type: ElectronicMailAddress, Value: AttributeValue{Encoding: IA5String, Value: "ex@example.com"}}
+	type args struct {
+		enc Encoding
+	}
+	tests := []struct {
+		name     string
+		d        DN
+		args     args
+		wantAtvs []AttributeTypeAndValue
+	}{
+		{"TestCase: 0 RDN", DN{}, args{PrintableString}, []AttributeTypeAndValue{}},
+		{"TestCase: PrintableString, 2 matched", DN{RDN{atv1}, RDN{atv2}, RDN{atv3, atv4}}, args{PrintableString}, []AttributeTypeAndValue{atv1, atv2}},
+		{"TestCase: UTF8String, 1 matched in multi value RDN", DN{RDN{atv1}, RDN{atv2}, RDN{atv3, atv4}}, args{UTF8String}, []AttributeTypeAndValue{atv3}},
+		{"TestCase: IA5String, 1 matched in multi value RDN", DN{RDN{atv1}, RDN{atv2}, RDN{atv3, atv4}}, args{IA5String}, []AttributeTypeAndValue{atv4}},
+		{"TestCase: IA5String, not matched", DN{RDN{atv1}, RDN{atv2}}, args{IA5String}, []AttributeTypeAndValue{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if gotAtvs := tt.d.AttributesWithEncoding(tt.args.enc); !reflect.DeepEqual(gotAtvs, tt.wantAtvs) {
+				t.Errorf("AttributesWithEncoding() = %v, want %v", gotAtvs, tt.wantAtvs)
+			}
+		})
+	}
+}
+
+func TestDN_OrgPath(t *testing.T) {
+	c := AttributeTypeAndValue{Type: CountryName, Value: AttributeValue{Encoding: PrintableString, Value: "JP"}}
+	o := AttributeTypeAndValue{Type: OrganizationName, Value: AttributeValue{Encoding: UTF8String, Value: "Example"}}
+	ou1 := AttributeTypeAndValue{Type: OrganizationalUnit, Value: AttributeValue{Encoding: UTF8String, Value: "Engineering"}}
+	ou2 := AttributeTypeAndValue{Type: OrganizationalUnit, Value: AttributeValue{Encoding: UTF8String, Value: "Backend"}}
+	gou := AttributeTypeAndValue{Type: Generic, Oid: "2.5.4.11", Value: AttributeValue{Encoding: UTF8String, Value: "Backend"}}
+	cn := AttributeTypeAndValue{Type: CommonName, Value: AttributeValue{Encoding: UTF8String, Value: "Mike"}}
+	tests := []struct {
+		name string
+		d    DN
+		want string
+	}{
+		{"TestCase: 0 RDN", DN{}, ""},
+		{"TestCase: O and OUs", DN{RDN{c}, RDN{o}, RDN{ou1}, RDN{ou2}, RDN{cn}}, "Example/Engineering/Backend"},
+		{"TestCase: no OU", DN{RDN{c}, RDN{o}, RDN{cn}}, "Example"},
+		{"TestCase: no O and no OU", DN{RDN{c}, RDN{cn}}, ""},
+		{"TestCase: OU in multi value RDN", DN{RDN{o}, RDN{ou1, cn}}, "Example/Engineering"},
+		{"TestCase: Generic with OU oid", DN{RDN{o}, RDN{gou}}, "Example/Backend"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.d.OrgPath(); got != tt.want {
+				t.Errorf("OrgPath() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDN_EmailAddresses(t *testing.T) {
+	c := AttributeTypeAndValue{Type: CountryName, Value: AttributeValue{Encoding: PrintableString, Value: "JP"}}
+	cn := AttributeTypeAndValue{Type: CommonName, Value: AttributeValue{Encoding: UTF8String, Value: "Mike"}}
+	email1 := AttributeTypeAndValue{Type: ElectronicMailAddress, Value: AttributeValue{Encoding: IA5String, Value: "mike@example.com"}}
+	email2 := AttributeTypeAndValue{Type: ElectronicMailAddress, Value: AttributeValue{Encoding: IA5String, Value: "mike@example.org"}}
+	gemail := AttributeTypeAndValue{Type: Generic, Oid: "1.2.840.113549.1.9.1", Value: AttributeValue{Encoding: IA5String, Value: "mike@example.net"}}
+	tests := []struct {
+		name string
+		d    DN
+		want []string
+	}{
+		{"TestCase: 0 RDN", DN{}, []string{}},
+		{"TestCase: no email", DN{RDN{c}, RDN{cn}}, []string{}},
+		{"TestCase: 2 emails in different RDNs", DN{RDN{c}, RDN{email1}, RDN{cn, email2}}, []string{"mike@example.com", "mike@example.org"}},
+		{"TestCase: Generic with email oid", DN{RDN{email2}, RDN{gemail}}, []string{"mike@example.org", "mike@example.net"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.d.EmailAddresses(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("EmailAddresses() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDN_SMIMEEmail(t *testing.T) {
+	cn := AttributeTypeAndValue{Type: CommonName, Value: AttributeValue{UTF8String, "Mike"}}
+	e1 := AttributeTypeAndValue{Type: ElectronicMailAddress, Value: AttributeValue{IA5String, "mike@example.com"}}
+	e2 := AttributeTypeAndValue{Type: ElectronicMailAddress, Value: AttributeValue{IA5String, "bob@example.com"}}
+	invalid := AttributeTypeAndValue{Type: ElectronicMailAddress, Value: AttributeValue{IA5String, "mike"}}
+	tests := []struct {
+		name   string
+		d      DN
+		want   string
+		wantOk bool
+	}{
+		{"TestCase: empty DN", DN{}, "", false},
+		{"TestCase: absent", DN{RDN{cn}}, "", false},
+		{"TestCase: present", DN{RDN{cn}, RDN{e1}}, "mike@example.com", true},
+		{"TestCase: first of multiple", DN{RDN{e2}, RDN{cn, e1}}, "bob@example.com", true},
+		{"TestCase: not an email", DN{RDN{invalid}, RDN{e1}}, "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := tt.d.SMIMEEmail()
+			if got != tt.want || ok != tt.wantOk {
+				t.Errorf("SMIMEEmail() = %v, %v, want %v, %v", got, ok, tt.want, tt.wantOk)
+			}
+		})
+	}
+}
+
+func Test_looksLikeEmail(t *testing.T) {
+	tests := []struct {
+		name string
+		s    string
+		want bool
+	}{
+		{"TestCase: valid", "mike@example.com", true},
+		{"TestCase: subaddress", "mike+tag@mail.example.com", true},
+		{"TestCase: blank", "", false},
+		{"TestCase: no @", "mike.example.com", false},
+		{"TestCase: two @", "mike@ex@ample.com", false},
+		{"TestCase: empty local part", "@example.com", false},
+		{"TestCase: empty domain part", "mike@", false},
+		{"TestCase: leading dot domain", "mike@.example.com", false},
+		{"TestCase: trailing dot domain", "mike@example.com.", false},
+		{"TestCase: consecutive dots domain", "mike@example..com", false},
+		{"TestCase: space", "mi ke@example.com", false},
+		{"TestCase: control character", "mike@example.com\n", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := looksLikeEmail(tt.s); got != tt.want {
+				t.Errorf("looksLikeEmail() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDN_SubjectSerialNumber(t *testing.T) {
+	cn := AttributeTypeAndValue{Type: CommonName, Value: AttributeValue{Encoding: UTF8String, Value: "device"}}
+	sn1 := AttributeTypeAndValue{Type: SerialNumber, Value: AttributeValue{Encoding: PrintableString, Value: "SN001"}}
+	sn2 := AttributeTypeAndValue{Type: SerialNumber, Value: AttributeValue{Encoding: PrintableString, Value: "SN002"}}
+	genericSn := AttributeTypeAndValue{Type: Generic, Oid: "2.5.4.5", Value: AttributeValue{Encoding: PrintableString, Value: "SN003"}}
+	tests := []struct {
+		name   string
+		d      DN
+		want   string
+		wantOk bool
+	}{
+		{"TestCase: empty DN", DN{}, "", false},
+		{"TestCase: no SerialNumber", DN{RDN{cn}}, "", false},
+		{"TestCase: one SerialNumber", DN{RDN{cn}, RDN{sn1}}, "SN001", true},
+		{"TestCase: first of multiple SerialNumbers", DN{RDN{sn2}, RDN{cn, sn1}}, "SN002", true},
+		{"TestCase: Generic with SerialNumber oid", DN{RDN{cn, genericSn}}, "SN003", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := tt.d.SubjectSerialNumber()
+			if got != tt.want || ok != tt.wantOk {
+				t.Errorf("SubjectSerialNumber() = %v, %v, want %v, %v", got, ok, tt.want, tt.wantOk)
+			}
+		})
+	}
+}
+
+func TestDN_OIDStrings(t *testing.T) {
+	c := AttributeTypeAndValue{Type: CountryName, Value: AttributeValue{Encoding: PrintableString, Value: "JP"}}
+	o := AttributeTypeAndValue{Type: OrganizationName, Value: AttributeValue{Encoding: UTF8String, Value: "Example"}}
+	ou1 := AttributeTypeAndValue{Type: OrganizationalUnit, Value: AttributeValue{Encoding: UTF8String, Value: "Engineering"}}
+	ou2 := AttributeTypeAndValue{Type: OrganizationalUnit, Value: AttributeValue{Encoding: UTF8String, Value: "Backend"}}
+	gou := AttributeTypeAndValue{Type: Generic, Oid: "2.5.4.11", Value: AttributeValue{Encoding: UTF8String, Value: "Sales"}}
+	g := AttributeTypeAndValue{Type: Generic, Oid: "1.2.3.4", Value: AttributeValue{Encoding: UTF8String, Value: "x"}}
+	cn := AttributeTypeAndValue{Type: CommonName, Value: AttributeValue{Encoding: UTF8String, Value: "Mike"}}
+	tests := []struct {
+		name string
+		d    DN
+		want []string
+	}{
+		{"TestCase: 0 RDN", DN{}, []string{}},
+		{"TestCase: named types", DN{RDN{c}, RDN{o}, RDN{cn}}, []string{"2.5.4.6", "2.5.4.10", "2.5.4.3"}},
+		{"TestCase: duplicated types", DN{RDN{c}, RDN{ou1}, RDN{ou2}, RDN{gou}}, []string{"2.5.4.6", "2.5.4.11"}},
+		{"TestCase: Generic and multi value RDN", DN{RDN{c}, RDN{cn, g}}, []string{"2.5.4.6", "2.5.4.3", "1.2.3.4"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.d.OIDStrings(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("OIDStrings() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDN_IsValidIssuer(t *testing.T) {
+	c := AttributeTypeAndValue{Type: CountryName, Value: AttributeValue{Encoding: PrintableString, Value: "JP"}}
+	o := AttributeTypeAndValue{Type: OrganizationName, Value: AttributeValue{Encoding: UTF8String, Value: "Example CA"}}
+	cn := AttributeTypeAndValue{Type: CommonName, Value: AttributeValue{Encoding: UTF8String, Value: "Example Root"}}
+	genericCN := AttributeTypeAndValue{Type: Generic, Oid: "2.5.4.3", Value: AttributeValue{Encoding: UTF8String, Value: "Example Root"}}
+	tests := []struct {
+		name    string
+		d       DN
+		want    bool
+		wantErr bool
+	}{
+		{"TestCase: empty DN", DN{}, false, true},
+		{"TestCase: nil DN", nil, false, true},
+		{"TestCase: C only", DN{RDN{c}}, false, true},
+		{"TestCase: C and O", DN{RDN{c}, RDN{o}}, true, false},
+		{"TestCase: C and CN", DN{RDN{c}, RDN{cn}}, true, false},
+		{"TestCase: multi-valued RDN with CN", DN{RDN{c, cn}}, true, false},
+		{"TestCase: Generic with CommonName oid", DN{RDN{genericCN}}, true, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.d.IsValidIssuer()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("IsValidIssuer() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("IsValidIssuer() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_removeAttributeTypeAndValue(t *testing.T) {
+	atv1 := AttributeTypeAndValue{Type: CountryName, Value: AttributeValue{Encoding: PrintableString}}
+	atv2 := AttributeTypeAndValue{Type: CommonName, Value: AttributeValue{Encoding: UTF8String}}
+	atv3 := AttributeTypeAndValue{Type: OrganizationName, Value: AttributeValue{Encoding: UTF8String}}
+	type args struct {
+		index int
+		r     RDN
+	}
+	tests := []struct {
+		name     string
+		args     args
+		wantRest RDN
+	}{
+		{"TestCase: 1 Attributes index 0", args{0, RDN{atv1}}, RDN{}},
+		{"TestCase: 2 Attributes index 0", args{0, RDN{atv1, atv2}}, RDN{atv2}},
+		{"TestCase: 2 Attributes index 1", args{1, RDN{atv1, atv2}}, RDN{atv1}},
+		{"TestCase: 3 Attributes index 0", args{0, RDN{atv1, atv2, atv3}}, RDN{atv2, atv3}},
+		{"TestCase: 3 Attributes index 1", args{1, RDN{atv1, atv2, atv3}}, RDN{atv1, atv3}},
+		{"TestCase: 3 Attributes index 2", args{2, RDN{atv1, atv2, atv3}}, RDN{atv1, atv2}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if gotRest := removeAttributeTypeAndValue(tt.args.index, tt.args.r); !reflect.DeepEqual(gotRest, tt.wantRest) {
+				t.Errorf("removeAttributeTypeAndValue() = %v, want %v", gotRest, tt.wantRest)
+			}
+		})
+	}
+}
+
+func Test_isMatchedRDN(t *testing.T) {
+	atv1 := AttributeTypeAndValue{Type: CountryName, Value: AttributeValue{Encoding: PrintableString}}
+	atv2 := AttributeTypeAndValue{Type: CommonName, Value: AttributeValue{Encoding: UTF8String}}
+	atv3 := AttributeTypeAndValue{Type: OrganizationName, Value: AttributeValue{Encoding: UTF8String}}
+	atv4 := AttributeTypeAndValue{Type: Generic, Oid: "1.2.3", Value: AttributeValue{Encoding: UTF8String}}
+	type args struct {
+		r   RDN
+		ats []AttributeType
+	}
+	tests := []struct {
+		name          string
+		args          args
+		wantIsMatched bool
+	}{
+		{"TestCase: 1 Attributes matched", args{RDN{atv1}, []AttributeType{CountryName}}, true},
+		{"TestCase: 1 Attributes matched (Generic)", args{RDN{atv4}, []AttributeType{Generic}}, true},
+		{"TestCase: 2 Attributes matched", args{RDN{atv1, atv2}, []AttributeType{CountryName, CommonName}}, true},
+		{"TestCase: 2 Attributes matched (Generic)", args{RDN{atv4, atv2}, []AttributeType{Generic, CommonName}}, true},
+		{"TestCase: 2 Attributes(Revers order) matched", args{RDN{atv1, atv2}, []AttributeType{CommonName, CountryName}}, true},
+		{"TestCase: 2 Attributes(Generic, Revers order) matched", args{RDN{atv4, atv2}, []AttributeType{CommonName, Generic}}, true},
+		{"TestCase: 3 Attributes not matched", args{RDN{atv1, atv2, atv3}, []AttributeType{CommonName, CountryName, CommonName}}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if gotIsMatched := isMatchedRDN(tt.args.r, tt.args.ats); gotIsMatched != tt.wantIsMatched {
+				t.Errorf("isMatchedRDN() = %v, want %v", gotIsMatched, tt.wantIsMatched)
+			}
+		})
+	}
+}
+
+func Test_isMatchedRDNByOids(t *testing.T) {
+	atv1 := AttributeTypeAndValue{Type: CountryName, Value: AttributeValue{Encoding: PrintableString}}
+	atv2 := AttributeTypeAndValue{Type: CommonName, Value: AttributeValue{Encoding: UTF8String}}
+	atv3 := AttributeTypeAndValue{Type: OrganizationName, Value: AttributeValue{Encoding: UTF8String}}
+	atv4 := AttributeTypeAndValue{Type: Generic, Oid: "1.2.3", Value: AttributeValue{Encoding: UTF8String}}
+	type args struct {
+		r    RDN
+		oids []string
+	}
+	tests := []struct {
+		name          string
+		args          args
+		wantIsMatched bool
+	}{
+		{"TestCase: 1 Attributes matched", args{RDN{atv1}, []string{"2.5.4.6"}}, true},
+		{"TestCase: 1 Attributes matched (Generic)", args{RDN{atv4}, []string{"1.2.3"}}, true},
+		{"TestCase: 2 Attributes matched", args{RDN{atv1, atv2}, []string{"2.5.4.6", "2.5.4.3"}}, true},
+		{"TestCase: 2 Attributes matched (Generic)", args{RDN{atv4, atv2}, []string{"1.2.3", "2.5.4.3"}}, true},
+		{"TestCase: 2 Attributes(Revers order) matched", args{RDN{atv1, atv2}, []string{"2.5.4.3", "2.5.4.6"}}, true},
+		{"TestCase: 2 Attributes(Generic, Revers order) matched", args{RDN{atv4, atv2}, []string{"2.5.4.3", "1.2.3"}}, true},
+		{"TestCase: 3 Attributes not matched", args{RDN{atv1, atv2, atv3}, []string{"2.5.4.3", "2.5.4.6", "2.5.4.3"}}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if gotIsMatched := isMatchedRDNByOids(tt.args.r, tt.args.oids); gotIsMatched != tt.wantIsMatched {
+				t.Errorf("isMatchedRDNByOids() = %v, want %v", gotIsMatched, tt.wantIsMatched)
+			}
+		})
+	}
+}
+
+func Test_findMatchedAttributeTypeIndex(t *testing.T) {
+	atv1 := AttributeTypeAndValue{Type: CountryName, Value: AttributeValue{Encoding: PrintableString}}
+	atv2 := AttributeTypeAndValue{Type: CommonName, Value: AttributeValue{Encoding: UTF8String}}
+	atv3 := AttributeTypeAndValue{Type: OrganizationName, Value: AttributeValue{Encoding: UTF8String}}
+	atv4 := AttributeTypeAndValue{Type: Generic, Oid: "1.2.3", Value: AttributeValue{Encoding: UTF8String}}
+	type args struct {
+		r  RDN
+		at AttributeType
+	}
+	tests := []struct {
+		name      string
+		args      args
+		wantIndex int
+	}{
+		{"TestCase: 0 Attributes  not matched", args{RDN{}, CountryName}, -1},
+		{"TestCase: 3 Attributes  matched index 0", args{RDN{atv1, atv2, atv3}, CountryName}, 0},
+		{"TestCase: 3 Attributes  matched index 1", args{RDN{atv1, atv2, atv3}, CommonName}, 1},
+		{"TestCase: 3 Attributes  matched index 2", args{RDN{atv1, atv2, atv3}, OrganizationName}, 2},
+		{"TestCase: 3 Attributes  not matched", args{RDN{atv1, atv2, atv3}, OrganizationalUnit}, -1},
+		{"TestCase: 4 Attributes  matched index 1", args{RDN{atv1, atv4, atv2, atv3}, Generic}, 1},
+		{"TestCase: 3 Attributes  not matched", args{RDN{atv1, atv4, atv2, atv3}, OrganizationalUnit}, -1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if gotIndex := findMatchedAttributeTypeIndex(tt.args.r, tt.args.at); gotIndex != tt.wantIndex {
+				t.Errorf("findMatchedAttributeTypeIndex() = %v, want %v", gotIndex, tt.wantIndex)
+			}
+		})
+	}
+}
+
+func Test_findMatchedOidIndex(t *testing.T) {
+	atv1 := AttributeTypeAndValue{Type: CountryName, Value: AttributeValue{Encoding: PrintableString}}
+	atv2 := AttributeTypeAndValue{Type: CommonName, Value: AttributeValue{Encoding: UTF8String}}
+	atv3 := AttributeTypeAndValue{Type: OrganizationName, Value: AttributeValue{Encoding: UTF8String}}
+	atv4 := AttributeTypeAndValue{Type: Generic, Oid: "1.2.3", Value: AttributeValue{Encoding: UTF8String}}
+	type args struct {
+		r   RDN
+		oid string
+	}
+	tests := []struct {
+		name      string
+		args      args
+		wantIndex int
+	}{
+		{"TestCase: 0 Attributes  not matched", args{RDN{}, "2.5.4.6"}, -1},
+		{"TestCase: 3 Attributes  matched index 0", args{RDN{atv1, atv2, atv3}, "2.5.4.6"}, 0},
+		{"TestCase: 3 Attributes  matched index 1", args{RDN{atv1, atv2, atv3}, "2.5.4.3"}, 1},
+		{"TestCase: 3 Attributes  matched index 2", args{RDN{atv1, atv2, atv3}, "2.5.4.10"}, 2},
+		{"TestCase: 3 Attributes  not matched", args{RDN{atv1, atv2, atv3}, "2.5.4.11"}, -1},
+		{"TestCase: 4 Attributes  matched index 1", args{RDN{atv1, atv4, atv2, atv3}, "1.2.3"}, 1},
+		{"TestCase: 3 Attributes  not matched", args{RDN{atv1, atv4, atv2, atv3}, "2.5.4.11"}, -1},
+		{"TestCase: not supported AttributeType  not matched by blank oid", args{RDN{AttributeTypeAndValue{Type: AttributeType(100)}}, ""}, -1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if gotIndex := findMatchedOidIndex(tt.args.r, tt.args.oid); gotIndex != tt.wantIndex {
+				t.Errorf("findMatchedOidIndex() = %v, want %v", gotIndex, tt.wantIndex)
+			}
+		})
+	}
+}
+
+func Test_oidStringTable(t *testing.T) {
+	for at := CountryName; at <= UserID; at++ {
+		oid, err := ReferOid(at)
+		want := oid.String()
+		if err != nil {
+			want = ""
+		}
+		if got := oidStringTable[at]; got != want {
+			t.Errorf("oidStringTable[%v] = %v, want %v", at, got, want)
+		}
+	}
+	if _, ok := oidStringTable[Generic]; ok {
+		t.Errorf("oidStringTable has Generic")
+	}
+}
+
+func Test_needEscaping(t *testing.T) {
+	type args struct {
+		r rune
+	}
+	tests := []struct {
+		name string
+		args args
+		want bool
+	}{
+		{"TestCase: U+0022", args{rune('\U00000022')}, true},
+		{"TestCase: U+002B", args{rune('\U0000002B')}, true},
+		{"TestCase: U+002C", args{rune('\U0000002C')}, true},
+		{"TestCase: U+003B", args{rune('\U0000003B')}, true},
+		{"TestCase: U+003C", args{rune('\U0000003C')}, true},
+		{"TestCase: U+003E", args{rune('\U0000003E')}, true},
+		{"TestCase: U+005C", args{rune('\U0000005C')}, true},
+		{"TestCase: U+0000", args{rune('\U00000000')}, true},
+		{"TestCase: A ", args{rune('\U00000041')}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := needEscaping(tt.args.r); got != tt.want {
+				t.Errorf("needEscaping() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_escapeAttributeValue(t *testing.T) {
+	type args struct {
+		s string
+	}
+	tests := []struct {
+		name string
+		args args
+		want string
+	}{
+		{"TestCase: AAA", args{"AAA"}, "AAA"},
+		{"TestCase:  AAA", args{" AAA"}, "\\ AAA"},
+		{"TestCase: #AAA", args{"#AAA"}, "\\#AAA"},
+		{"TestCase:  AAA#", args{" AAA"}, "\\ AAA"},
+		{"TestCase: #AAA ", args{"#AAA "}, "\\#AAA\\ "},
+		{"TestCase:  AAA ", args{" AAA "}, "\\ AAA\\ "},
+		{"TestCase:  A A A ", args{" A A A "}, "\\ A A A\\ "},
+		{"TestCase: あ い う", args{" あ い う "}, "\\ あ い う\\ "},
+		{"TestCase: あ(U+0022)い+う,え;お ", args{" あ\"い+う,え;お "}, "\\ あ\\\"い\\+う\\,え\\;お\\ "},
+		{"TestCase: あ(U+003C)い(U+003E)う(U+005C)え ", args{" あ<い>う\\え "}, "\\ あ\\<い\\>う\\\\え\\ "},
+		{"TestCase: James (U+0022)Jim(U+0022), III", args{"James \"Jim\" Smith, III"}, "James \\\"Jim\\\" Smith\\, III"},
+		{"TestCase: blank", args{""}, ""},
+		{"TestCase: 1 space", args{" "}, "\\ "},
+		{"TestCase: 2 spaces", args{"  "}, "\\ \\ "},
+		{"TestCase: 3 spaces", args{"   "}, "\\  \\ "},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := escapeAttributeValue(tt.args.s); got != tt.want {
+				t.Errorf("escapeAttributeValue() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAttributeValue_ToRFC4514FormatString(t *testing.T) {
+	type fields struct {
+		Encoding Encoding
+		Value    string
+	}
+	tests := []struct {
+		name   string
+		fields fields
+		want   string
+	}{
+		{"TestCase: AAA", fields{2, "AAA"}, "AAA"},
+		{"TestCase:  AAA", fields{2, " AAA"}, "\\ AAA"},
+		{"TestCase: #AAA", fields{2, "#AAA"}, "\\#AAA"},
+		{"TestCase:  AAA#", fields{2, " AAA"}, "\\ AAA"},
+		{"TestCase: #AAA ", fields{2, "#AAA "}, "\\#AAA\\ "},
+		{"TestCase:  AAA ", fields{2, " AAA "}, "\\ AAA\\ "},
+		{"TestCase:  A A A ", fields{2, " A A A "}, "\\ A A A\\ "},
+		{"TestCase: あ い う", fields{2, " あ い う "}, "\\ あ い う\\ "},
+		{"TestCase: あ(U+0022)い+う,え;お ", fields{2, " あ\"い+う,え;お "}, "\\ あ\\\"い\\+う\\,え\\;お\\ "},
+		{"TestCase: あ(U+003C)い(U+003E)う(U+005C)え ", fields{2, " あ<い>う\\え "}, "\\ あ\\<い\\>う\\\\え\\ "},
+		{"TestCase: James (U+0022)Jim(U+0022) Smith, III", fields{2, "James \"Jim\" Smith, III"}, "James \\\"Jim\\\" Smith\\, III"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			av := AttributeValue{
+				Encoding: tt.fields.Encoding,
+				Value:    tt.fields.Value,
+			}
+			if got := av.ToRFC4514FormatString(); got != tt.want {
+				t.Errorf("ToRFC4514FormatString() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAttributeValue_TitleCase(t *testing.T) {
+	tests := []struct {
+		name string
+		av   AttributeValue
+		want AttributeValue
+	}{
+		{"TestCase: empty", AttributeValue{UTF8String, ""}, AttributeValue{UTF8String, ""}},
+		{"TestCase: upper case", AttributeValue{PrintableString, "JOHN SMITH"}, AttributeValue{PrintableString, "John Smith"}},
+		{"TestCase: lower case", AttributeValue{UTF8String, "john smith"}, AttributeValue{UTF8String, "John Smith"}},
+		{"TestCase: hyphen and apostrophe", AttributeValue{UTF8String, "JOHN o'neil-SMITH"}, AttributeValue{UTF8String, "John O'neil-Smith"}},
+		{"TestCase: accented", AttributeValue{UTF8String, "ÉLODIE  ÅSTRÖM"}, AttributeValue{UTF8String, "Élodie  Åström"}},
+		{"TestCase: title case digraph", AttributeValue{UTF8String, "\u01c6emal"}, AttributeValue{UTF8String, "\u01c5emal"}},
+		{"TestCase: CJK", AttributeValue{UTF8String, "山田 太郎"}, AttributeValue{UTF8String, "山田 太郎"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.av.TitleCase(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("TitleCase() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAttributeValue_String(t *testing.T) {
+	type fields struct {
+		Encoding Encoding
+		Value    string
+	}
+	tests := []struct {
+		name   string
+		fields fields
+		want   string
+	}{
+		{"TestCase: AAA", fields{2, "AAA"}, "AAA"},
+		{"TestCase:  AAA", fields{2, " AAA"}, " AAA"},
+		{"TestCase: #AAA", fields{2, "#AAA"}, "#AAA"},
+		{"TestCase:  AAA#", fields{2, " AAA#"}, " AAA#"},
+		{"TestCase: #AAA ", fields{2, "#AAA "}, "#AAA "},
+		{"TestCase:  AAA ", fields{2, " AAA "}, " AAA "},
+		{"TestCase:  A A A ", fields{2, " A A A "}, " A A A "},
+		{"TestCase: あ い う", fields{2, " あ い う "}, " あ い う "},
+		{"TestCase: あ(U+0022)い+う,え;お ", fields{2, " あ\"い+う,え;お "}, " あ\"い+う,え;お "},
+		{"TestCase: あ(U+003C)い(U+003E)う(U+005C)え ", fields{2, " あ<い>う\\え "}, " あ<い>う\\え "},
+		{"TestCase: James (U+0022)Jim(U+0022) Smith, III", fields{2, "James \"Jim\" Smith, III"}, "James \"Jim\" Smith, III"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			av := AttributeValue{
+				Encoding: tt.fields.Encoding,
+				Value:    tt.fields.Value,
+			}
+			if got := av.String(); got != tt.want {
+				t.Errorf("String() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAttributeTypeAndValue_ToRFC4514FormatString(t *testing.T) {
+	type fields struct {
+		Type  AttributeType
+		Value AttributeValue
+		Oid   string
+	}
+	tests := []struct {
+		name   string
+		fields fields
+		want   string
+	}{
+		{"TestCase: OrganizationName AAA", fields{Type: OrganizationName, Value: AttributeValue{UTF8String, "AAA"}}, "O=AAA"},
+		{"TestCase: Generic OID=1.2.3.4 AAA", fields{Type: Generic, Oid: "1.2.3.4", Value: AttributeValue{UTF8String, "AAA"}}, "1.2.3.4=AAA"},
+		{"TestCase: Generic OID=2.5.4.10(OrganizationName) AAA", fields{Type: Generic, Oid: "2.5.4.10", Value: AttributeValue{UTF8String, "AAA"}}, "O=AAA"},
+		{"TestCase: DnQualifier AAA", fields{Type: DnQualifier, Value: AttributeValue{UTF8String, "AAA"}}, "DNQUALIFIER=AAA"},
+		{"TestCase: LocalityName  AAA", fields{Type: LocalityName, Value: AttributeValue{UTF8String, " AAA"}}, "L=\\ AAA"},
+		{"TestCase: CommonName James (U+0022)Jim(U+0022) Smith, III", fields{Type: CommonName, Value: AttributeValue{UTF8String, "James \"Jim\" Smith, III"}}, "CN=James \\\"Jim\\\" Smith\\, III"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			atv := AttributeTypeAndValue{
+				Type:  tt.fields.Type,
+				Value: tt.fields.Value,
+				Oid:   tt.fields.Oid,
+			}
+			if got := atv.ToRFC4514FormatString(); got != tt.want {
+				t.Errorf("ToRFC4514FormatString() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAttributeTypeAndValue_String(t *testing.T) {
+	type fields struct {
+		Type  AttributeType
+		Value AttributeValue
+		Oid   string
+	}
+	tests := []struct {
+		name   string
+		fields fields
+		want   string
+	}{
+		{"TestCase: OrganizationName AAA", fields{Type: OrganizationName, Value: AttributeValue{UTF8String, "AAA"}}, "O=AAA"},
+		{"TestCase: DnQualifier AAA", fields{Type: DnQualifier, Value: AttributeValue{UTF8String, "AAA"}}, "DNQUALIFIER=AAA"},
+		{"TestCase: Generic Oid=1.2.3 AAA", fields{Type: Generic, Value: AttributeValue{UTF8String, "AAA"}, Oid: "1.2.3"}, "1.2.3=AAA"},
+		{"TestCase: Generic Oid=1.2.3.4 foo", fields{Type: Generic, Value: AttributeValue{PrintableString, "foo"}, Oid: "1.2.3.4"}, "1.2.3.4=foo"},
+		{"TestCase: Generic Oid=2.5.4.3 foo", fields{Type: Generic, Value: AttributeValue{UTF8String, "foo"}, Oid: "2.5.4.3"}, "CN=foo"},
+		{"TestCase: Generic without Oid foo", fields{Type: Generic, Value: AttributeValue{UTF8String, "foo"}}, "UNKNOWN=foo"},
+		{"TestCase: LocalityName  AAA", fields{Type: LocalityName, Value: AttributeValue{UTF8String, " AAA"}}, "L= AAA"},
+		{"TestCase: CommonName James (U+0022)Jim(U+0022) Smith, III", fields{Type: CommonName, Value: AttributeValue{UTF8String, "James \"Jim\" Smith, III"}}, "CN=James \"Jim\" Smith, III"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			atv := AttributeTypeAndValue{
+				Type:  tt.fields.Type,
+				Value: tt.fields.Value,
+				Oid:   tt.fields.Oid,
+			}
+			if got := atv.String(); got != tt.want {
+				t.Errorf("String() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRDN_ToRFC4514FormatString(t *testing.T) {
+	tests := []struct {
+		name string
+		r    RDN
+		want string
+	}{
+		{"TestCase: 0 RDN", RDN{}, ""},
+		{"TestCase: single RDN with leading SPACE",
+			RDN{AttributeTypeAndValue{Type: OrganizationName, Value: AttributeValue{UTF8String, " AAA"}}}, "O=\\ AAA"},
+		{"TestCase: single RDN with leading #",
+			RDN{AttributeTypeAndValue{Type: OrganizationName, Value: AttributeValue{UTF8String, "#AAA"}}}, "O=\\#AAA"},
+		{"TestCase: 2 RDN",
+			RDN{
+				AttributeTypeAndValue{Type: OrganizationName, Value: AttributeValue{UTF8String, "AAA"}},
+				AttributeTypeAndValue{Type: OrganizationName, Value: AttributeValue{UTF8String, "BBB"}},
+			},
+			"O=AAA+O=BBB"},
+		{"TestCase: 2 RDN with leading SPACE",
+			RDN{
+				AttributeTypeAndValue{Type: OrganizationName, Value: AttributeValue{UTF8String, " AAA"}},
+				AttributeTypeAndValue{Type: OrganizationName, Value: AttributeValue{UTF8String, " BBB"}},
+			},
+			"O=\\ AAA+O=\\ BBB"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.r.ToRFC4514FormatString(); got != tt.want {
+				t.Errorf("ToRFC4514FormatString() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRDN_String(t *testing.T) {
+	tests := []struct {
+		name string
+		rdn  RDN
+		want string
+	}{
+		{"TestCase: 0 RDN", RDN{}, ""},
+		{"TestCase: single RDN",
+			RDN{AttributeTypeAndValue{Type: OrganizationName, Value: AttributeValue{UTF8String, "AAA"}}},
+			"O=AAA"},
+		{"TestCase: single RDN with leading SPACE",
+			RDN{AttributeTypeAndValue{Type: OrganizationName, Value: AttributeValue{UTF8String, " AAA"}}}, "O= AAA"},
+		{"TestCase: single RDN with leading #",
+			RDN{AttributeTypeAndValue{Type: OrganizationName, Value: AttributeValue{UTF8String, "#AAA"}}}, "O=#AAA"},
+
+		{"TestCase: 2 RDN",
+			RDN{
+				AttributeTypeAndValue{Type: OrganizationName, Value: AttributeValue{UTF8String, "AAA"}},
+				AttributeTypeAndValue{Type: OrganizationName, Value: AttributeValue{UTF8String, "BBB"}},
+			},
+			"O=AAA+O=BBB"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.rdn.String(); got != tt.want {
+				t.Errorf("String() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDN_Validate(t *testing.T) {
+	c := RDN{AttributeTypeAndValue{Type: CountryName, Value: AttributeValue{PrintableString, "JP"}}}
+	cn := RDN{AttributeTypeAndValue{Type: CommonName, Value: AttributeValue{UTF8String, "Mike"}}}
+	tests := []struct {
+		name    string
+		d       DN
+		wantErr string
+	}{
+		{"TestCase: 0 RDN", DN{}, ""},
+		{"TestCase: valid DN", DN{c, cn}, ""},
+		{"TestCase: empty RDN", DN{c, RDN{}}, "1 th RDN element validating error: RDN should have at least one AttributeTypeAndValue"},
+		{"TestCase: CountryName in UTF8String", DN{RDN{AttributeTypeAndValue{Type: CountryName, Value: AttributeValue{UTF8String, "JP"}}}},
+			"0 th RDN element validating error: 0 th AttributeTypeAndValue element validating error: AttributeTypeAndValue error: CountryName’s value should be PrintableString"},
+		{"TestCase: not PrintableString value", DN{c, RDN{AttributeTypeAndValue{Type: CommonName, Value: AttributeValue{PrintableString, "a@b"}}}}, "1 th RDN element validating error"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.d.Validate()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Validate() error = %v, want nil", err)
+				}
+			} else if err == nil || !strings.HasPrefix(err.Error(), tt.wantErr) {
+				t.Errorf("Validate() error = %v, want %v", err, tt.wantErr)
+			}
+			if _, merr := MarshalDN(tt.d); (merr != nil) != (err != nil) {
+				t.Errorf("Validate() error = %v, but MarshalDN() error = %v", err, merr)
+			}
+		})
+	}
+}
+
+func TestRDN_Validate(t *testing.T) {
+	cn := AttributeTypeAndValue{Type: CommonName, Value: AttributeValue{UTF8String, "Mike"}}
+	email := AttributeTypeAndValue{Type: ElectronicMailAddress, Value: AttributeValue{IA5String, "mike@example.org"}}
+	tests := []struct {
+		name    string
+		r       RDN
+		wantErr bool
+	}{
+		{"TestCase: 0 AttributeTypeAndValue", RDN{}, true},
+		{"TestCase: valid multi-valued RDN", RDN{cn, email}, false},
+		{"TestCase: invalid second AttributeTypeAndValue", RDN{cn, AttributeTypeAndValue{Type: ElectronicMailAddress, Value: AttributeValue{UTF8String, "a"}}}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.r.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestAttributeTypeAndValue_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		atv     AttributeTypeAndValue
+		wantErr bool
+	}{
+		{"TestCase: valid", AttributeTypeAndValue{Type: CommonName, Value: AttributeValue{UTF8String, "Mike"}}, false},
+		{"TestCase: Generic", AttributeTypeAndValue{Type: Generic, Oid: "1.2.3.4", Value: AttributeValue{UTF8String, "a"}}, false},
+		{"TestCase: Generic without Oid", AttributeTypeAndValue{Type: Generic, Value: AttributeValue{UTF8String, "a"}}, true},
+		{"TestCase: Generic with known oid and invalid Encoding", AttributeTypeAndValue{Type: Generic, Oid: "2.5.4.6", Value: AttributeValue{UTF8String, "JP"}}, true},
+		{"TestCase: not supported AttributeType", AttributeTypeAndValue{Type: AttributeType(999), Value: AttributeValue{UTF8String, "a"}}, true},
+		{"TestCase: not supported Encoding", AttributeTypeAndValue{Type: CommonName, Value: AttributeValue{Encoding(999), "a"}}, true},
+		{"TestCase: not IA5String value", AttributeTypeAndValue{Type: ElectronicMailAddress, Value: AttributeValue{IA5String, "日本"}}, true},
+		{"TestCase: OIDValue not oid", AttributeTypeAndValue{Type: Generic, Oid: "1.2.3.4", Value: AttributeValue{OIDValue, "a"}}, true},
+		{"TestCase: TeletexString not Latin-1", AttributeTypeAndValue{Type: CommonName, Value: AttributeValue{TeletexString, "日本"}}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.atv.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestNewDN(t *testing.T) {
+	c := RDN{AttributeTypeAndValue{Type: CountryName, Value: AttributeValue{PrintableString, "JP"}}}
+	o := RDN{AttributeTypeAndValue{Type: OrganizationName, Value: AttributeValue{UTF8String, "Example"}}}
+	tests := []struct {
+		name string
+		rdns []RDN
+		want DN
+	}{
+		{"TestCase: 0 RDN", nil, DN{}},
+		{"TestCase: c,o", []RDN{c, o}, DN{c, o}},
+		{"TestCase: empty RDN is not validated", []RDN{c, {}}, DN{c, RDN{}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NewDN(tt.rdns...); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("NewDN() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewDNChecked(t *testing.T) {
+	c := RDN{AttributeTypeAndValue{Type: CountryName, Value: AttributeValue{PrintableString, "JP"}}}
+	o := RDN{AttributeTypeAndValue{Type: OrganizationName, Value: AttributeValue{UTF8String, "Example"}}}
+	tests := []struct {
+		name    string
+		rdns    []RDN
+		want    DN
+		wantErr bool
+	}{
+		{"TestCase: 0 RDN", nil, DN{}, false},
+		{"TestCase: c,o", []RDN{c, o}, DN{c, o}, false},
+		{"TestCase: empty RDN", []RDN{c, {}}, DN{}, true},
+		{"TestCase: CountryName in UTF8String", []RDN{{AttributeTypeAndValue{Type: CountryName, Value: AttributeValue{UTF8String, "JP"}}}, o}, DN{}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewDNChecked(tt.rdns...)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("NewDNChecked() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("NewDNChecked() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDN_AppendRDN(t *testing.T) {
+	c := RDN{AttributeTypeAndValue{Type: CountryName, Value: AttributeValue{PrintableString, "JP"}}}
+	o := RDN{AttributeTypeAndValue{Type: OrganizationName, Value: AttributeValue{UTF8String, "Example"}}}
+	cn := RDN{AttributeTypeAndValue{Type: CommonName, Value: AttributeValue{UTF8String, "Mike"}}}
+	d := make(DN, 1, 2)
+	d[0] = c
+	got1 := d.AppendRDN(o)
+	got2 := d.AppendRDN(cn)
+	if !reflect.DeepEqual(got1, DN{c, o}) {
+		t.Errorf("AppendRDN() = %v, want %v", got1, DN{c, o})
+	}
+	if !reflect.DeepEqual(got2, DN{c, cn}) {
+		t.Errorf("AppendRDN() = %v, want %v", got2, DN{c, cn})
+	}
+	if !reflect.DeepEqual(d, DN{c}) {
+		t.Errorf("AppendRDN() modified the DN: %v", d)
+	}
+	r := RDN{AttributeTypeAndValue{Type: CommonName, Value: AttributeValue{UTF8String, "Mike"}}}
+	got3 := DN{}.AppendRDN(r)
+	r[0].Value.Value = "Bob"
+	if !reflect.DeepEqual(got3, DN{cn}) {
+		t.Errorf("AppendRDN() shares the RDN: %v", got3)
+	}
+}
+
+func TestDN_AppendRDNChecked(t *testing.T) {
+	c := RDN{AttributeTypeAndValue{Type: CountryName, Value: AttributeValue{PrintableString, "JP"}}}
+	o := RDN{AttributeTypeAndValue{Type: OrganizationName, Value: AttributeValue{UTF8String, "Example"}}}
+	type args struct {
+		rdn RDN
+	}
+	tests := []struct {
+		name    string
+		d       DN
+		args    args
+		want    DN
+		wantErr bool
+	}{
+		{"TestCase: valid RDN", DN{c}, args{o}, DN{c, o}, false},
+		{"TestCase: empty RDN", DN{c}, args{RDN{}}, DN{}, true},
+		{"TestCase: CountryName in UTF8String", DN{}, args{RDN{AttributeTypeAndValue{Type: CountryName, Value: AttributeValue{UTF8String, "JP"}}}}, DN{}, true},
+		{"TestCase: Generic without Oid", DN{}, args{RDN{AttributeTypeAndValue{Type: Generic, Value: AttributeValue{UTF8String, "a"}}}}, DN{}, true},
+		{"TestCase: not supported Encoding", DN{}, args{RDN{AttributeTypeAndValue{Type: CommonName, Value: AttributeValue{Encoding(999), "a"}}}}, DN{}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.d.AppendRDNChecked(tt.args.rdn)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("AppendRDNChecked() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("AppendRDNChecked() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDN_ReverseDnOrder(t *testing.T) {
+	rdn1 := RDN{AttributeTypeAndValue{Type: OrganizationName, Value: AttributeValue{UTF8String, "AAA"}}}
+	rdn2 := RDN{AttributeTypeAndValue{Type: CountryName, Value: AttributeValue{UTF8String, "JP"}}}
+	atv1 := AttributeTypeAndValue{Type: CommonName, Value: AttributeValue{UTF8String, "Mike"}}
+	atv2 := AttributeTypeAndValue{Type: ElectronicMailAddress, Value: AttributeValue{IA5String, "Mike@example.org"}}
+	rdn3 := RDN{atv1, atv2}
+	rdn4 := RDN{AttributeTypeAndValue{Type: OrganizationName, Value: AttributeValue{UTF8String, "#株式会社Example"}}}
+
+	tests := []struct {
+		name string
+		d    DN
+		want DN
+	}{
+		{"TestCase: 0 RDN", DN{}, DN{}},
+		{"TestCase: o,c", DN{rdn1}, DN{rdn1}},
+		{"TestCase: o,c", DN{rdn1, rdn2}, DN{rdn2, rdn1}},
+		{"TestCase: cn+email,c", DN{rdn3, rdn2}, DN{rdn2, rdn3}},
+		{"TestCase: escaped o,c", DN{rdn4, rdn2}, DN{rdn2, rdn4}},
+		{"TestCase: cn+email,o,c", DN{rdn3, rdn1, rdn2}, DN{rdn2, rdn1, rdn3}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.d.ReverseDnOrder(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ReverseDnOrder() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDN_MapRDNs(t *testing.T) {
+	c := AttributeTypeAndValue{Type: CountryName, Value: AttributeValue{PrintableString, "JP"}}
+	o := AttributeTypeAndValue{Type: OrganizationName, Value: AttributeValue{UTF8String, "Example"}}
+	cn := AttributeTypeAndValue{Type: CommonName, Value: AttributeValue{UTF8String, "Mike"}}
+	email := AttributeTypeAndValue{Type: ElectronicMailAddress, Value: AttributeValue{IA5String, "mike@example.com"}}
+	dropEmail := func(r RDN) RDN {
+		if i := findMatchedAttributeTypeIndex(r, ElectronicMailAddress); i != -1 {
+			return removeAttributeTypeAndValue(i, r)
+		}
+		return r
+	}
+	reverse := func(r RDN) RDN {
+		for i, j := 0, len(r)-1; i < j; i, j = i+1, j-1 {
+			r[i], r[j] = r[j], r[i]
+		}
+		return r
+	}
+	type args struct {
+		fn func(RDN) RDN
+	}
+	tests := []struct {
+		name string
+		d    DN
+		args args
+		want DN
+	}{
+		{"TestCase: 0 RDN", DN{}, args{dropEmail}, DN{}},
+		{"TestCase: remove email from every RDN", DN{RDN{c}, RDN{o, email}, RDN{cn, email}}, args{dropEmail}, DN{RDN{c}, RDN{o}, RDN{cn}}},
+		{"TestCase: no email", DN{RDN{c}, RDN{o}}, args{dropEmail}, DN{RDN{c}, RDN{o}}},
+		{"TestCase: modify RDN in place", DN{RDN{c}, RDN{cn, email}}, args{reverse}, DN{RDN{c}, RDN{email, cn}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			org := tt.d.String()
+			if got := tt.d.MapRDNs(tt.args.fn); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("MapRDNs() = %v, want %v", got, tt.want)
+			}
+			if tt.d.String() != org {
+				t.Errorf("MapRDNs() modified the DN: %v, want %v", tt.d, org)
+			}
+		})
+	}
+}
+
+func TestDN_SortedForDisplay(t *testing.T) {
+	c := RDN{AttributeTypeAndValue{Type: CountryName, Value: AttributeValue{PrintableString, "JP"}}}
+	st := RDN{AttributeTypeAndValue{Type: StateOrProvinceName, Value: AttributeValue{UTF8String, "Tokyo"}}}
+	l := RDN{AttributeTypeAndValue{Type: LocalityName, Value: AttributeValue{UTF8String, "Chiyoda"}}}
+	o := RDN{AttributeTypeAndValue{Type: OrganizationName, Value: AttributeValue{UTF8String, "example"}}}
+	ou1 := RDN{AttributeTypeAndValue{Type: OrganizationalUnit, Value: AttributeValue{UTF8String, "Sales"}}}
+	ou2 := RDN{AttributeTypeAndValue{Type: OrganizationalUnit, Value: AttributeValue{UTF8String, "Dev"}}}
+	cn := RDN{AttributeTypeAndValue{Type: CommonName, Value: AttributeValue{UTF8String, "Mike"}}}
+	gc := RDN{AttributeTypeAndValue{Type: Generic, Oid: "2.5.4.6", Value: AttributeValue{PrintableString, "JP"}}}
+	dc := RDN{AttributeTypeAndValue{Type: DomainComponent, Value: AttributeValue{IA5String, "example"}}}
+	title := RDN{AttributeTypeAndValue{Type: Title, Value: AttributeValue{UTF8String, "Engineer"}}}
+	multi := RDN{title[0], o[0]}
+	tests := []struct {
+		name string
+		d    DN
+		want DN
+	}{
+		{"TestCase: 0 RDN", DN{}, DN{}},
+		{"TestCase: already sorted", DN{c, st, l, o, ou1, cn}, DN{c, st, l, o, ou1, cn}},
+		{"TestCase: reversed", DN{cn, ou1, o, l, st, c}, DN{c, st, l, o, ou1, cn}},
+		{"TestCase: same priority keeps order", DN{ou1, cn, ou2, o}, DN{o, ou1, ou2, cn}},
+		{"TestCase: others last", DN{title, dc, cn, gc}, DN{gc, cn, title, dc}},
+		{"TestCase: multi-valued RDN", DN{cn, multi, c}, DN{c, multi, cn}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			orig := append(DN{}, tt.d...)
+			if got := tt.d.SortedForDisplay(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("SortedForDisplay() = %v, want %v", got, tt.want)
+			}
+			if !reflect.DeepEqual(tt.d, orig) {
+				t.Errorf("SortedForDisplay() modified the DN: %v, want %v", tt.d, orig)
+			}
+		})
+	}
+}
+
+func TestDN_IsFlat(t *testing.T) {
+	c := AttributeTypeAndValue{Type: CountryName, Value: AttributeValue{PrintableString, "JP"}}
+	ou1 := AttributeTypeAndValue{Type: OrganizationalUnit, Value: AttributeValue{UTF8String, "Dev"}}
+	ou2 := AttributeTypeAndValue{Type: OrganizationalUnit, Value: AttributeValue{UTF8String, "Sales"}}
+	tests := []struct {
+		name string
+		d    DN
+		want bool
+	}{
+		{"TestCase: 0 RDN", DN{}, true},
+		{"TestCase: flat", DN{RDN{c}, RDN{ou1}}, true},
+		{"TestCase: mixed", DN{RDN{c}, RDN{ou1, ou2}}, false},
+		{"TestCase: empty RDN", DN{RDN{c}, RDN{}}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.d.IsFlat(); got != tt.want {
+				t.Errorf("IsFlat() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDN_Flatten(t *testing.T) {
+	c := AttributeTypeAndValue{Type: CountryName, Value: AttributeValue{PrintableString, "JP"}}
+	ou1 := AttributeTypeAndValue{Type: OrganizationalUnit, Value: AttributeValue{UTF8String, "Dev"}}
+	ou2 := AttributeTypeAndValue{Type: OrganizationalUnit, Value: AttributeValue{UTF8String, "Sales"}}
+	cn := AttributeTypeAndValue{Type: CommonName, Value: AttributeValue{UTF8String, "Mike"}}
+	tests := []struct {
+		name string
+		d    DN
+		want DN
+	}{
+		{"TestCase: 0 RDN", DN{}, DN{}},
+		{"TestCase: flat", DN{RDN{c}, RDN{cn}}, DN{RDN{c}, RDN{cn}}},
+		{"TestCase: mixed", DN{RDN{c}, RDN{ou1, ou2}, RDN{cn}}, DN{RDN{c}, RDN{ou1}, RDN{ou2}, RDN{cn}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.d.Flatten()
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Flatten() = %v, want %v", got, tt.want)
+			}
+			if !got.IsFlat() {
+				t.Errorf("Flatten().IsFlat() = false, want true")
+			}
+		})
+	}
+}
+
+func TestDN_ToRFC4514FormatString(t *testing.T) {
+	rdn1 := RDN{AttributeTypeAndValue{Type: OrganizationName, Value: AttributeValue{UTF8String, "AAA"}}}
+	rdn2 := RDN{AttributeTypeAndValue{Type: CountryName, Value: AttributeValue{UTF8String, "JP"}}}
+	atv1 := AttributeTypeAndValue{Type: CommonName, Value: AttributeValue{UTF8String, "Mike"}}
+	atv2 := AttributeTypeAndValue{Type: ElectronicMailAddress, Value: AttributeValue{IA5String, "Mike@example.org"}}
+	rdn3 := RDN{atv1, atv2}
+	rdn4 := RDN{AttributeTypeAndValue{Type: OrganizationName, Value: AttributeValue{UTF8String, "#株式会社Example"}}}
+	rdn5 := RDN{AttributeTypeAndValue{Type: Generic, Value: AttributeValue{UTF8String, "AAA"}, Oid: "1.2.3.4"}}
+	tests := []struct {
+		name string
+		d    DN
+		want string
+	}{
+		{"TestCase: 0 RDN", DN{}, ""},
+		{"TestCase: o,c", DN{rdn1, rdn2}, "C=JP,O=AAA"},
+		{"TestCase: 1.2.3.4,o,c", DN{rdn5, rdn1, rdn2}, "C=JP,O=AAA,1.2.3.4=AAA"},
+		{"TestCase: cn+email,c", DN{rdn3, rdn2}, "C=JP,CN=Mike+EMAIL=Mike@example.org"},
+		{"TestCase: escaped o,c", DN{rdn4, rdn2}, "C=JP,O=\\#株式会社Example"},
+		{"TestCase: cn+email,o,c", DN{rdn3, rdn1, rdn2}, "C=JP,O=AAA,CN=Mike+EMAIL=Mike@example.org"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.d.ToRFC4514FormatString(); got != tt.want {
+				t.Errorf("ToRFC4514FormatString() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAttributeTypeAndValue_ToRFC4514FormatStringHexValue(t *testing.T) {
+	tests := []struct {
+		name string
+		atv  AttributeTypeAndValue
+		want string
+	}{
+		{"TestCase: UTF8String", AttributeTypeAndValue{Type: CommonName, Value: AttributeValue{UTF8String, "Mike"}}, "CN=#0c044d696b65"},
+		{"TestCase: PrintableString", AttributeTypeAndValue{Type: CountryName, Value: AttributeValue{PrintableString, "JP"}}, "C=#13024a50"},
+		{"TestCase: IA5String", AttributeTypeAndValue{Type: ElectronicMailAddress, Value: AttributeValue{IA5String, "a@b"}}, "EMAIL=#1603614062"},
+		{"TestCase: BMPString", AttributeTypeAndValue{Type: CommonName, Value: AttributeValue{BMPString, "a"}}, "CN=#1e020061"},
+		{"TestCase: special characters", AttributeTypeAndValue{Type: CommonName, Value: AttributeValue{UTF8String, "a,b"}}, "CN=#0c03612c62"},
+		{"TestCase: Generic OIDValue", AttributeTypeAndValue{Type: Generic, Oid: "1.2.3.4", Value: AttributeValue{OIDValue, "1.2.3"}}, "1.2.3.4=#06022a03"},
+		{"TestCase: not encodable value", AttributeTypeAndValue{Type: CommonName, Value: AttributeValue{PrintableString, "a@b"}}, "CN=a@b"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.atv.ToRFC4514FormatStringHexValue(); got != tt.want {
+				t.Errorf("ToRFC4514FormatStringHexValue() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDN_ToRFC4514FormatStringWithOptions_HexValueRoundTrip(t *testing.T) {
+	d := DN{
+		RDN{AttributeTypeAndValue{Type: CountryName, Value: AttributeValue{PrintableString, "JP"}}},
+		RDN{AttributeTypeAndValue{Type: OrganizationName, Value: AttributeValue{BMPString, "example, Inc."}}},
+		RDN{
+			AttributeTypeAndValue{Type: CommonName, Value: AttributeValue{UTF8String, "Mike+\"M\""}},
+			AttributeTypeAndValue{Type: Generic, Oid: "1.2.3.4", Value: AttributeValue{OIDValue, "1.2.3"}},
+		},
+	}
+	s := d.ToRFC4514FormatStringWithOptions(RFC4514Options{HexValue: true})
+	got, err := ParseRFC4514DN(s)
+	if err != nil {
+		t.Fatalf("ParseRFC4514DN() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, d) {
+		t.Errorf("ParseRFC4514DN(%v) = %v, want %v", s, got, d)
+	}
+}
+
+func TestDN_ToRFC4514FormatStringWithOptions(t *testing.T) {
+	rdn1 := RDN{AttributeTypeAndValue{Type: CountryName, Value: AttributeValue{PrintableString, "JP"}}}
+	rdn2 := RDN{AttributeTypeAndValue{Type: OrganizationName, Value: AttributeValue{UTF8String, "example Co., Ltd"}}}
+	atv1 := AttributeTypeAndValue{Type: CommonName, Value: AttributeValue{UTF8String, "Mike"}}
+	atv2 := AttributeTypeAndValue{Type: GivenName, Value: AttributeValue{UTF8String, "Mike"}}
+	rdn3 := RDN{atv1, atv2}
+	rdn4 := RDN{AttributeTypeAndValue{Type: Generic, Value: AttributeValue{UTF8String, "AAA"}, Oid: "1.2.3.4"}}
+	rdn5 := RDN{AttributeTypeAndValue{Type: LocalityName, Value: AttributeValue{UTF8String, "Tokyo"}}}
+	rdn6 := RDN{atv2, AttributeTypeAndValue{Type: Surname, Value: AttributeValue{UTF8String, "Smith"}}}
+	type args struct {
+		o RFC4514Options
+	}
+	tests := []struct {
+		name string
+		d    DN
+		args args
+		want string
+	}{
+		{"TestCase: 0 RDN", DN{}, args{RFC4514Options{}}, ""},
+		{"TestCase: default", DN{rdn1, rdn2, rdn3}, args{RFC4514Options{}}, "CN=Mike+GIVENNAME=Mike,O=example Co.\\, Ltd,C=JP"},
+		{"TestCase: UpperCaseDescriptor", DN{rdn1, rdn2, rdn3}, args{RFC4514Options{DescriptorCase: UpperCaseDescriptor}}, "CN=Mike+GIVENNAME=Mike,O=example Co.\\, Ltd,C=JP"},
+		{"TestCase: LowerCaseDescriptor", DN{rdn1, rdn2, rdn3}, args{RFC4514Options{DescriptorCase: LowerCaseDescriptor}}, "cn=Mike+givenname=Mike,o=example Co.\\, Ltd,c=JP"},
+		{"TestCase: MixedCaseDescriptor", DN{rdn1, rdn2, rdn3}, args{RFC4514Options{DescriptorCase: MixedCaseDescriptor}}, "cn=Mike+givenName=Mike,o=example Co.\\, Ltd,c=JP"},
+		{"TestCase: MixedCaseDescriptor Generic", DN{rdn1, rdn4}, args{RFC4514Options{DescriptorCase: MixedCaseDescriptor}}, "1.2.3.4=AAA,c=JP"},
+		{"TestCase: UpperCaseDescriptor LocalityName", DN{rdn1, rdn5}, args{RFC4514Options{DescriptorCase: UpperCaseDescriptor}}, "L=Tokyo,C=JP"},
+		{"TestCase: LowerCaseDescriptor LocalityName", DN{rdn1, rdn5}, args{RFC4514Options{DescriptorCase: LowerCaseDescriptor}}, "l=Tokyo,c=JP"},
+		{"TestCase: MixedCaseDescriptor LocalityName", DN{rdn1, rdn5}, args{RFC4514Options{DescriptorCase: MixedCaseDescriptor}}, "l=Tokyo,c=JP"},
+		{"TestCase: MixedCaseDescriptor givenName and sn", DN{rdn1, rdn6}, args{RFC4514Options{DescriptorCase: MixedCaseDescriptor}}, "givenName=Mike+sn=Smith,c=JP"},
+		{"TestCase: UpperCaseDescriptor givenName and sn", DN{rdn1, rdn6}, args{RFC4514Options{DescriptorCase: UpperCaseDescriptor}}, "GIVENNAME=Mike+SN=Smith,C=JP"},
+		{"TestCase: SpaceAroundPlus", DN{rdn1, rdn2, rdn3}, args{RFC4514Options{SpaceAroundPlus: true}}, "CN=Mike + GIVENNAME=Mike,O=example Co.\\, Ltd,C=JP"},
+		{"TestCase: SpaceAfterComma", DN{rdn1, rdn2, rdn3}, args{RFC4514Options{SpaceAfterComma: true}}, "CN=Mike+GIVENNAME=Mike, O=example Co.\\, Ltd, C=JP"},
+		{"TestCase: SpaceAroundPlus and SpaceAfterComma", DN{rdn1, rdn2, rdn3}, args{RFC4514Options{DescriptorCase: LowerCaseDescriptor, SpaceAroundPlus: true, SpaceAfterComma: true}}, "cn=Mike + givenname=Mike, o=example Co.\\, Ltd, c=JP"},
+		{"TestCase: SpaceAfterComma 1 RDN", DN{rdn1}, args{RFC4514Options{SpaceAfterComma: true}}, "C=JP"},
+		{"TestCase: MostSignificantFirst false", DN{rdn1, rdn2, rdn3}, args{RFC4514Options{MostSignificantFirst: false}}, "CN=Mike+GIVENNAME=Mike,O=example Co.\\, Ltd,C=JP"},
+		{"TestCase: MostSignificantFirst true", DN{rdn1, rdn2, rdn3}, args{RFC4514Options{MostSignificantFirst: true}}, "C=JP,O=example Co.\\, Ltd,CN=Mike+GIVENNAME=Mike"},
+		{"TestCase: MostSignificantFirst and SpaceAfterComma", DN{rdn1, rdn2, rdn3}, args{RFC4514Options{MostSignificantFirst: true, SpaceAfterComma: true}}, "C=JP, O=example Co.\\, Ltd, CN=Mike+GIVENNAME=Mike"},
+		{"TestCase: MostSignificantFirst 1 RDN", DN{rdn1}, args{RFC4514Options{MostSignificantFirst: true}}, "C=JP"},
+		{"TestCase: MostSignificantFirst 0 RDN", DN{}, args{RFC4514Options{MostSignificantFirst: true}}, ""},
+		{"TestCase: HexValue", DN{rdn1, rdn4}, args{RFC4514Options{HexValue: true}}, "1.2.3.4=#0c03414141,C=#13024a50"},
+		{"TestCase: HexValue multi value RDN", DN{rdn1, rdn3}, args{RFC4514Options{HexValue: true, DescriptorCase: LowerCaseDescriptor}}, "cn=#0c044d696b65+givenname=#0c044d696b65,c=#13024a50"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.d.ToRFC4514FormatStringWithOptions(tt.args.o); got != tt.want {
+				t.Errorf("ToRFC4514FormatStringWithOptions() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDN_Direction(t *testing.T) {
+	d := DN{
+		RDN{AttributeTypeAndValue{Type: DomainComponent, Value: AttributeValue{IA5String, "com"}}},
+		RDN{AttributeTypeAndValue{Type: DomainComponent, Value: AttributeValue{IA5String, "example"}}},
+		RDN{AttributeTypeAndValue{Type: CommonName, Value: AttributeValue{UTF8String, "admin"}}},
+	}
+	tests := []struct {
+		name string
+		got  string
+		want string
+	}{
+		{"TestCase: String", d.String(), "DC=com,DC=example,CN=admin"},
+		{"TestCase: String reversed", d.ReverseDnOrder().String(), "CN=admin,DC=example,DC=com"},
+		{"TestCase: ToRFC4514FormatString", d.ToRFC4514FormatString(), "CN=admin,DC=example,DC=com"},
+		{"TestCase: ToRFC4514FormatStringWithOptions MostSignificantFirst", d.ToRFC4514FormatStringWithOptions(RFC4514Options{MostSignificantFirst: true}), "DC=com,DC=example,CN=admin"},
+		{"TestCase: ToLDAPString", d.ToLDAPString(), "cn=admin,dc=example,dc=com"},
+		{"TestCase: ToLDAPString MostSignificantFirst", d.ToRFC4514FormatStringWithOptions(RFC4514Options{DescriptorCase: LowerCaseDescriptor, MostSignificantFirst: true}), "dc=com,dc=example,cn=admin"},
+		{"TestCase: ToOpenSSLOnelineString", d.ToOpenSSLOnelineString(), "/DC=com/DC=example/CN=admin"},
+		{"TestCase: ToOpenSSLOnelineString reversed", d.ReverseDnOrder().ToOpenSSLOnelineString(), "/CN=admin/DC=example/DC=com"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.got != tt.want {
+				t.Errorf("got = %v, want %v", tt.got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDN_ToLDAPString(t *testing.T) {
+	rdn1 := RDN{AttributeTypeAndValue{Type: DomainComponent, Value: AttributeValue{IA5String, "com"}}}
+	rdn2 := RDN{AttributeTypeAndValue{Type: DomainComponent, Value: AttributeValue{IA5String, "example"}}}
+	rdn3 := RDN{AttributeTypeAndValue{Type: OrganizationalUnit, Value: AttributeValue{UTF8String, "people"}}}
+	rdn4 := RDN{AttributeTypeAndValue{Type: CommonName, Value: AttributeValue{UTF8String, "admin"}}}
+	rdn5 := RDN{AttributeTypeAndValue{Type: CommonName, Value: AttributeValue{UTF8String, "Smith, John"}}}
+	tests := []struct {
+		name string
+		d    DN
+		want string
+	}{
+		{"TestCase: 0 RDN", DN{}, ""},
+		{"TestCase: dc,dc", DN{rdn1, rdn2}, "dc=example,dc=com"},
+		{"TestCase: dc,dc,ou,cn", DN{rdn1, rdn2, rdn3, rdn4}, "cn=admin,ou=people,dc=example,dc=com"},
+		{"TestCase: escaped dc,dc,ou,cn", DN{rdn1, rdn2, rdn3, rdn5}, "cn=Smith\\, John,ou=people,dc=example,dc=com"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.d.ToLDAPString(); got != tt.want {
+				t.Errorf("ToLDAPString() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDN_ToOpenSSLOnelineString(t *testing.T) {
+	c := RDN{AttributeTypeAndValue{Type: CountryName, Value: AttributeValue{PrintableString, "JP"}}}
+	o := RDN{AttributeTypeAndValue{Type: OrganizationName, Value: AttributeValue{UTF8String, "example"}}}
+	cn := RDN{AttributeTypeAndValue{Type: CommonName, Value: AttributeValue{UTF8String, "foo"}}}
+	ou := RDN{
+		AttributeTypeAndValue{Type: OrganizationalUnit, Value: AttributeValue{UTF8String, "Dev"}},
+		AttributeTypeAndValue{Type: OrganizationalUnit, Value: AttributeValue{UTF8String, "Sales"}},
+	}
+	special := RDN{AttributeTypeAndValue{Type: CommonName, Value: AttributeValue{UTF8String, `a/b+c\d,e#f`}}}
+	g := RDN{AttributeTypeAndValue{Type: Generic, Oid: "1.2.3.4", Value: AttributeValue{UTF8String, "x"}}}
+	tests := []struct {
+		name string
+		d    DN
+		want string
+	}{
+		{"TestCase: 0 RDN", DN{}, ""},
+		{"TestCase: C=JP,O=example,CN=foo", DN{c, o, cn}, "/C=JP/O=example/CN=foo"},
+		{"TestCase: multi value RDN", DN{c, ou}, "/C=JP/OU=Dev+OU=Sales"},
+		{"TestCase: special characters", DN{special}, `/CN=a\/b\+c\\d,e#f`},
+		{"TestCase: Generic", DN{c, g}, "/C=JP/1.2.3.4=x"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.d.ToOpenSSLOnelineString(); got != tt.want {
+				t.Errorf("ToOpenSSLOnelineString() = %v, want %v", got, tt.want)
+			}
+			back, err := ParseOpenSSLOnelineDN(tt.want)
+			if err != nil || !reflect.DeepEqual(back, tt.d) {
+				t.Errorf("ParseOpenSSLOnelineDN(ToOpenSSLOnelineString()) = %v, %v, want %v", back, err, tt.d)
+			}
+		})
+	}
+}
+
+func TestDN_ToLDIFDN(t *testing.T) {
+	dc1 := RDN{AttributeTypeAndValue{Type: DomainComponent, Value: AttributeValue{IA5String, "com"}}}
+	dc2 := RDN{AttributeTypeAndValue{Type: DomainComponent, Value: AttributeValue{IA5String, "example"}}}
+	cn1 := RDN{AttributeTypeAndValue{Type: CommonName, Value: AttributeValue{UTF8String, "admin"}}}
+	cn2 := RDN{AttributeTypeAndValue{Type: CommonName, Value: AttributeValue{UTF8String, "やまだ"}}}
+	tests := []struct {
+		name string
+		d    DN
+		want string
+	}{
+		{"TestCase: 0 RDN", DN{}, "dn: "},
+		{"TestCase: ASCII", DN{dc1, dc2, cn1}, "dn: cn=admin,dc=example,dc=com"},
+		{"TestCase: non-ASCII", DN{dc1, dc2, cn2}, "dn:: Y24944KE44G+44GgLGRjPWV4YW1wbGUsZGM9Y29t"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.d.ToLDIFDN(); got != tt.want {
+				t.Errorf("ToLDIFDN() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_isLDIFSafeString(t *testing.T) {
+	type args struct {
+		s string
+	}
+	tests := []struct {
+		name string
+		args args
+		want bool
+	}{
+		{"TestCase: blank", args{""}, true},
+		{"TestCase: ASCII", args{"cn=admin"}, true},
+		{"TestCase: colon not at the beginning", args{"cn=a:b"}, true},
+		{"TestCase: leading space", args{" cn=admin"}, false},
+		{"TestCase: leading colon", args{":cn=admin"}, false},
+		{"TestCase: leading <", args{"<cn=admin"}, false},
+		{"TestCase: LF", args{"cn=a\nb"}, false},
+		{"TestCase: CR", args{"cn=a\rb"}, false},
+		{"TestCase: NUL", args{"cn=a\x00b"}, false},
+		{"TestCase: non-ASCII", args{"cn=あ"}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isLDIFSafeString(tt.args.s); got != tt.want {
+				t.Errorf("isLDIFSafeString() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDN_ToURLComponent(t *testing.T) {
+	c := RDN{AttributeTypeAndValue{Type: CountryName, Value: AttributeValue{PrintableString, "JP"}}}
+	o := RDN{AttributeTypeAndValue{Type: OrganizationName, Value: AttributeValue{UTF8String, "Example Inc"}}}
+	ou := RDN{AttributeTypeAndValue{Type: OrganizationalUnit, Value: AttributeValue{UTF8String, "R&D/Dev?x=1#a"}}}
+	cn := RDN{
+		AttributeTypeAndValue{Type: CommonName, Value: AttributeValue{UTF8String, "a+b,c"}},
+		AttributeTypeAndValue{Type: Generic, Oid: "1.2.3", Value: AttributeValue{UTF8String, "あ"}},
+	}
+	tests := []struct {
+		name string
+		d    DN
+		want string
+	}{
+		{"TestCase: 0 RDN", DN{}, ""},
+		{"TestCase: spaces", DN{c, o}, "O%3DExample%20Inc%2CC%3DJP"},
+		{"TestCase: slashes and reserved URL characters", DN{c, ou}, "OU%3DR%26D%2FDev%3Fx%3D1%5C%23a%2CC%3DJP"},
+		{"TestCase: escaped characters and non-ASCII", DN{cn}, "CN%3Da%5C%2Bb%5C%2Cc%2B1.2.3%3D%E3%81%82"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.d.ToURLComponent()
+			if got != tt.want {
+				t.Errorf("ToURLComponent() = %v, want %v", got, tt.want)
+			}
+			if u, err := url.PathUnescape(got); err != nil || u != tt.d.ToRFC4514FormatString() {
+				t.Errorf("url.PathUnescape() = %v, %v, want %v", u, err, tt.d.ToRFC4514FormatString())
+			}
+			if u, err := url.QueryUnescape(got); err != nil || u != tt.d.ToRFC4514FormatString() {
+				t.Errorf("url.QueryUnescape() = %v, %v, want %v", u, err, tt.d.ToRFC4514FormatString())
+			}
+		})
+	}
+}
+
+func Test_percentEncode(t *testing.T) {
+	tests := []struct {
+		name string
+		s    string
+		want string
+	}{
+		{"TestCase: blank", "", ""},
+		{"TestCase: unreserved", "AZaz09-._~", "AZaz09-._~"},
+		{"TestCase: reserved", ":/?#[]@!$&'()*+,;=", "%3A%2F%3F%23%5B%5D%40%21%24%26%27%28%29%2A%2B%2C%3B%3D"},
+		{"TestCase: space and percent", "a b%", "a%20b%25"},
+		{"TestCase: non-ASCII", "あ", "%E3%81%82"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := percentEncode(tt.s); got != tt.want {
+				t.Errorf("percentEncode() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDN_ToEnvPairs(t *testing.T) {
+	c := RDN{AttributeTypeAndValue{Type: CountryName, Value: AttributeValue{PrintableString, "JP"}}}
+	o := RDN{AttributeTypeAndValue{Type: OrganizationName, Value: AttributeValue{UTF8String, "Example Inc"}}}
+	ou1 := RDN{AttributeTypeAndValue{Type: OrganizationalUnit, Value: AttributeValue{UTF8String, "Sales"}}}
+	ou2 := RDN{AttributeTypeAndValue{Type: OrganizationalUnit, Value: AttributeValue{UTF8String, "Dev"}}}
+	cn := RDN{
+		AttributeTypeAndValue{Type: CommonName, Value: AttributeValue{UTF8String, "Mike's"}},
+		AttributeTypeAndValue{Type: OrganizationalUnit, Value: AttributeValue{UTF8String, "$HOME"}},
+	}
+	g := RDN{AttributeTypeAndValue{Type: Generic, Oid: "1.2.3", Value: AttributeValue{UTF8String, "x"}}}
+	type args struct {
+		prefix string
+	}
+	tests := []struct {
+		name string
+		d    DN
+		args args
+		want []string
+	}{
+		{"TestCase: 0 RDN", DN{}, args{"CERT"}, []string{}},
+		{"TestCase: single values", DN{c, o}, args{"CERT"}, []string{"CERT_C=JP", "CERT_O='Example Inc'"}},
+		{"TestCase: repeated OU", DN{c, ou1, ou2}, args{"CERT"}, []string{"CERT_C=JP", "CERT_OU_1=Sales", "CERT_OU_2=Dev"}},
+		{"TestCase: repeated OU in multi-valued RDN", DN{c, ou1, cn}, args{"CERT"}, []string{"CERT_C=JP", "CERT_OU_1=Sales", `CERT_CN='Mike'\''s'`, "CERT_OU_2='$HOME'"}},
+		{"TestCase: Generic", DN{g}, args{"CERT"}, []string{"CERT_OID_1_2_3=x"}},
+		{"TestCase: blank prefix", DN{c, ou1, ou2}, args{""}, []string{"C=JP", "OU_1=Sales", "OU_2=Dev"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.d.ToEnvPairs(tt.args.prefix); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ToEnvPairs() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAttributeTypeAndValue_envName(t *testing.T) {
+	tests := []struct {
+		name string
+		atv  AttributeTypeAndValue
+		want string
+	}{
+		{"TestCase: CommonName", AttributeTypeAndValue{Type: CommonName}, "CN"},
+		{"TestCase: GivenName", AttributeTypeAndValue{Type: GivenName}, "GIVENNAME"},
+		{"TestCase: Generic with known oid", AttributeTypeAndValue{Type: Generic, Oid: "2.5.4.3"}, "CN"},
+		{"TestCase: Generic with unknown oid", AttributeTypeAndValue{Type: Generic, Oid: "1.2.3.4"}, "OID_1_2_3_4"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.atv.envName(); got != tt.want {
+				t.Errorf("envName() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_shellQuote(t *testing.T) {
+	tests := []struct {
+		name string
+		s    string
+		want string
+	}{
+		{"TestCase: blank", "", "''"},
+		{"TestCase: safe", "mike@example.org", "mike@example.org"},
+		{"TestCase: space", "Example Inc", "'Example Inc'"},
+		{"TestCase: single quote", "it's", `'it'\''s'`},
+		{"TestCase: shell metacharacters", "a;b|c`d`$(e)", "'a;b|c`d`$(e)'"},
+		{"TestCase: non-ASCII", "やまだ", "'やまだ'"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := shellQuote(tt.s); got != tt.want {
+				t.Errorf("shellQuote() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDN_TLVDump(t *testing.T) {
+	cn := RDN{AttributeTypeAndValue{Type: CommonName, Value: AttributeValue{UTF8String, "abc"}}}
+	multi := RDN{
+		AttributeTypeAndValue{Type: CountryName, Value: AttributeValue{PrintableString, "JP"}},
+		AttributeTypeAndValue{Type: Generic, Oid: "1.2.3.4", Value: AttributeValue{IA5String, "x y"}},
+	}
+	tests := []struct {
+		name string
+		d    DN
+		want string
+	}{
+		{"TestCase: 0 RDN", DN{}, "SEQUENCE (len=0)\n"},
+		{"TestCase: CN=abc", DN{cn}, `SEQUENCE (len=14)
+  SET (len=12)
+    SEQUENCE (len=10)
+      OBJECT IDENTIFIER (len=3) 2.5.4.3 (CommonName)
+      UTF8String (len=3) "abc"
+`},
+		{"TestCase: multi value RDN and Generic", DN{multi, cn}, `SEQUENCE (len=39)
+  SET (len=23)
+    SEQUENCE (len=9)
+      OBJECT IDENTIFIER (len=3) 2.5.4.6 (CountryName)
+      PrintableString (len=2) "JP"
+    SEQUENCE (len=10)
+      OBJECT IDENTIFIER (len=3) 1.2.3.4
+      IA5String (len=3) "x y"
+  SET (len=12)
+    SEQUENCE (len=10)
+      OBJECT IDENTIFIER (len=3) 2.5.4.3 (CommonName)
+      UTF8String (len=3) "abc"
+`},
+		{"TestCase: invalid DN", DN{RDN{}}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.d.TLVDump(); got != tt.want {
+				t.Errorf("TLVDump() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_tagName(t *testing.T) {
+	type args struct {
+		r asn1.RawValue
+	}
+	tests := []struct {
+		name string
+		args args
+		want string
+	}{
+		{"TestCase: SEQUENCE", args{asn1.RawValue{Class: asn1.ClassUniversal, Tag: asn1.TagSequence}}, "SEQUENCE"},
+		{"TestCase: SET", args{asn1.RawValue{Class: asn1.ClassUniversal, Tag: asn1.TagSet}}, "SET"},
+		{"TestCase: TeletexString", args{asn1.RawValue{Class: asn1.ClassUniversal, Tag: asn1.TagT61String}}, "TeletexString"},
+		{"TestCase: BMPString", args{asn1.RawValue{Class: asn1.ClassUniversal, Tag: asn1.TagBMPString}}, "BMPString"},
+		{"TestCase: INTEGER", args{asn1.RawValue{Class: asn1.ClassUniversal, Tag: asn1.TagInteger}}, "[universal 2]"},
+		{"TestCase: context specific", args{asn1.RawValue{Class: asn1.ClassContextSpecific, Tag: 0}}, "[class 2, tag 0]"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tagName(tt.args.r); got != tt.want {
+				t.Errorf("tagName() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_primitiveValueString(t *testing.T) {
+	type args struct {
+		r asn1.RawValue
+	}
+	tests := []struct {
+		name string
+		args args
+		want string
+	}{
+		{"TestCase: known oid", args{asn1.RawValue{Class: asn1.ClassUniversal, Tag: asn1.TagOID, Bytes: []byte{0x55, 0x04, 0x03}, FullBytes: []byte{0x06, 0x03, 0x55, 0x04, 0x03}}}, "2.5.4.3 (CommonName)"},
+		{"TestCase: unknown oid", args{asn1.RawValue{Class: asn1.ClassUniversal, Tag: asn1.TagOID, Bytes: []byte{0x2a, 0x03, 0x04}, FullBytes: []byte{0x06, 0x03, 0x2a, 0x03, 0x04}}}, "1.2.3.4"},
+		{"TestCase: broken oid", args{asn1.RawValue{Class: asn1.ClassUniversal, Tag: asn1.TagOID, Bytes: []byte{0x80}, FullBytes: []byte{0x06, 0x01, 0x80}}}, "80"},
+		{"TestCase: UTF8String", args{asn1.RawValue{Class: asn1.ClassUniversal, Tag: asn1.TagUTF8String, Bytes: []byte("a\"b")}}, `"a\"b"`},
+		{"TestCase: INTEGER", args{asn1.RawValue{Class: asn1.ClassUniversal, Tag: asn1.TagInteger, Bytes: []byte{0x01, 0xff}}}, "01ff"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := primitiveValueString(tt.args.r); got != tt.want {
+				t.Errorf("primitiveValueString() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDN_String(t *testing.T) {
+	rdn1 := RDN{AttributeTypeAndValue{Type: OrganizationName, Value: AttributeValue{UTF8String, "AAA"}}}
+	rdn2 := RDN{AttributeTypeAndValue{Type: CountryName, Value: AttributeValue{UTF8String, "JP"}}}
+	atv1 := AttributeTypeAndValue{Type: CommonName, Value: AttributeValue{UTF8String, "Mike"}}
+	atv2 := AttributeTypeAndValue{Type: ElectronicMailAddress, Value: AttributeValue{IA5String, "Mike@example.org"}}
+	rdn3 := RDN{atv1, atv2}
+	rdn4 := RDN{AttributeTypeAndValue{Type: OrganizationName, Value: AttributeValue{UTF8String, "#株式会社Example"}}}
+
+	tests := []struct {
+		name string
+		d    DN
+		want string
+	}{
+		{"TestCase: 0 RDN", DN{}, ""},
+		{"TestCase: o,c", DN{rdn1, rdn2}, "O=AAA,C=JP"},
+		{"TestCase: cn+email,c", DN{rdn3, rdn2}, "CN=Mike+EMAIL=Mike@example.org,C=JP"},
+		{"TestCase: escaped o,c", DN{rdn4, rdn2}, "O=#株式会社Example,C=JP"},
+		{"TestCase: cn+email,o,c", DN{rdn3, rdn1, rdn2}, "CN=Mike+EMAIL=Mike@example.org,O=AAA,C=JP"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.d.String(); got != tt.want {
+				t.Errorf("String() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_convertToObjectIdentifier(t *testing.T) {
+	type args struct {
+		o string
+	}
+	tests := []struct {
+		name    string
+		args    args
+		wantOid asn1.ObjectIdentifier
+		wantErr bool
+	}{
+		{"TestCase: 1.2.3.4", args{"1.2.3.4"}, asn1.ObjectIdentifier{1, 2, 3, 4}, false},
+		{"TestCase: 1", args{"1"}, asn1.ObjectIdentifier{1}, false},
+		{"TestCase: 1.-3", args{"1.-3"}, nil, true},
+		{"TestCase: .", args{"."}, nil, true},
+		{"TestCase: 1.2.", args{"1.2."}, nil, true},
+		{"TestCase: 1.", args{"1."}, nil, true},
+		{"TestCase: ..", args{".."}, nil, true},
+		{"TestCase: 1.X.3", args{"1.X.3"}, nil, true},
+		{"TestCase: X.Y.Z", args{"X.Y.Z"}, nil, true},
+		{"TestCase: 1,2,3,4", args{"1,2,3,4"}, nil, true},
+		{"TestCase: blank", args{""}, nil, true},
+		{"TestCase: MaxOIDArcs arcs", args{strings.Repeat("1.", MaxOIDArcs-1) + "1"}, oidOfOnes(MaxOIDArcs), false},
+		{"TestCase: MaxOIDArcs+1 arcs", args{strings.Repeat("1.", MaxOIDArcs) + "1"}, nil, true},
+		{"TestCase: MaxOIDArcValue", args{"1.2." + strconv.Itoa(MaxOIDArcValue)}, asn1.ObjectIdentifier{1, 2, MaxOIDArcValue}, false},
+		{"TestCase: MaxOIDArcValue+1", args{"1.2." + strconv.FormatInt(int64(MaxOIDArcValue)+1, 10)}, nil, true},
+		{"TestCase: huge arc value", args{"1.2.99999999999999999999999999"}, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotOid, err := convertToObjectIdentifier(tt.args.o)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("convertToObjectIdentifier() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(gotOid, tt.wantOid) {
+				t.Errorf("convertToObjectIdentifier() gotOid = %v, want %v", gotOid, tt.wantOid)
+			}
+		})
+	}
+}
+
+func oidOfOnes(n int) asn1.ObjectIdentifier {
+	oid := asn1.ObjectIdentifier{}
+	for i := 0; i < n; i++ {
+		oid = append(oid, 1)
+	}
+	return oid
+}
+
+func Test_convertToObjectIdentifier_ConfiguredLimits(t *testing.T) {
+	defer func(arcs, value int) { MaxOIDArcs, MaxOIDArcValue = arcs, value }(MaxOIDArcs, MaxOIDArcValue)
+	MaxOIDArcs, MaxOIDArcValue = 3, 100
+	tests := []struct {
+		name    string
+		o       string
+		wantErr bool
+	}{
+		{"TestCase: at the limits", "1.2.100", false},
+		{"TestCase: beyond MaxOIDArcs", "1.2.3.4", true},
+		{"TestCase: beyond MaxOIDArcValue", "1.2.101", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := convertToObjectIdentifier(tt.o); (err != nil) != tt.wantErr {
+				t.Errorf("convertToObjectIdentifier() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestAttributeTypeAndValue_toShortName(t *testing.T) {
+	type fields struct {
+		Type  AttributeType
+		Value AttributeValue
+		Oid   string
+	}
+	tests := []struct {
+		name   string
+		fields fields
+		want   string
+	}{
+		{"TestCase:CountryName", fields{Type: CountryName, Value: AttributeValue{}}, "c"},
+		{"TestCase:OrganizationName", fields{Type: OrganizationName, Value: AttributeValue{}}, "o"},
+		{"TestCase:OrganizationalUnit", fields{Type: OrganizationalUnit, Value: AttributeValue{}}, "ou"},
+		{"TestCase:DnQualifier", fields{Type: DnQualifier, Value: AttributeValue{}}, "dnQualifier"},
+		{"TestCase:StateOrProvinceName", fields{Type: StateOrProvinceName, Value: AttributeValue{}}, "st"},
+		{"TestCase:CommonName", fields{Type: CommonName, Value: AttributeValue{}}, "cn"},
+		{"TestCase:SerialNumber", fields{Type: SerialNumber, Value: AttributeValue{}}, "serialNumber"},
+		{"TestCase:LocalityName", fields{Type: LocalityName, Value: AttributeValue{}}, "l"},
+		{"TestCase:Title", fields{Type: Title, Value: AttributeValue{}}, "title"},
+		{"TestCase:Surname", fields{Type: Surname, Value: AttributeValue{}}, "sn"},
+		{"TestCase:GivenName", fields{Type: GivenName, Value: AttributeValue{}}, "givenName"},
+		{"TestCase:Initials", fields{Type: Initials, Value: AttributeValue{}}, "initials"},
+		{"TestCase:Pseudonym", fields{Type: Pseudonym, Value: AttributeValue{}}, "pseudonym"},
+		{"TestCase:GenerationQualifier", fields{Type: GenerationQualifier, Value: AttributeValue{}}, "generationQualifier"},
+		{"TestCase:ElectronicMailAddress", fields{Type: ElectronicMailAddress, Value: AttributeValue{}}, "email"},
+		{"TestCase:DomainComponent", fields{Type: DomainComponent, Value: AttributeValue{}}, "DC"},
+		{"TestCase:UnstructuredName", fields{Type: UnstructuredName, Value: AttributeValue{}}, "unstructuredName"},
+		{"TestCase:UnstructuredAddress", fields{Type: UnstructuredAddress, Value: AttributeValue{}}, "unstructuredAddress"},
+		{"TestCase:StreetAddress", fields{Type: StreetAddress, Value: AttributeValue{}}, "street"},
+		{"TestCase:PostalCode", fields{Type: PostalCode, Value: AttributeValue{}}, "postalCode"},
+		{"TestCase:UserID", fields{Type: UserID, Value: AttributeValue{}}, "uid"},
+		{"TestCase:Generic", fields{Type: Generic, Oid: "1.2.3", Value: AttributeValue{}}, "1.2.3"},
+		{"TestCase:Generic(OrganizationName)", fields{Type: Generic, Oid: "2.5.4.10", Value: AttributeValue{}}, "o"},
+		{"TestCase:Generic(broken oid)", fields{Type: Generic, Oid: "broken oid", Value: AttributeValue{}}, "UnKnown"},
+		{"TestCase:UnKnownAttributeType", fields{Type: AttributeType(9999)}, "UnKnown"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := AttributeTypeAndValue{
+				Type:  tt.fields.Type,
+				Value: tt.fields.Value,
+				Oid:   tt.fields.Oid,
+			}
+			if got := a.toShortName(); got != tt.want {
+				t.Errorf("toShortName() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_toDefinedShortName(t *testing.T) {
+	type args struct {
+		a AttributeType
+	}
+	tests := []struct {
+		name string
+		args args
+		want string
+	}{
+		{"TestCase:CountryName", args{CountryName}, "c"},
+		{"TestCase:OrganizationName", args{OrganizationName}, "o"},
+		{"TestCase:OrganizationalUnit", args{OrganizationalUnit}, "ou"},
+		{"TestCase:DnQualifier", args{DnQualifier}, "dnQualifier"},
+		{"TestCase:StateOrProvinceName", args{StateOrProvinceName}, "st"},
+		{"TestCase:CommonName", args{CommonName}, "cn"},
+		{"TestCase:SerialNumber", args{SerialNumber}, "serialNumber"},
+		{"TestCase:LocalityName", args{LocalityName}, "l"},
+		{"TestCase:Title", args{Title}, "title"},
+		{"TestCase:Surname", args{Surname}, "sn"},
+		{"TestCase:GivenName", args{GivenName}, "givenName"},
+		{"TestCase:Initials", args{Initials}, "initials"},
+		{"TestCase:Pseudonym", args{Pseudonym}, "pseudonym"},
+		{"TestCase:GenerationQualifier", args{GenerationQualifier}, "generationQualifier"},
+		{"TestCase:ElectronicMailAddress", args{ElectronicMailAddress}, "email"},
+		{"TestCase:DomainComponent", args{DomainComponent}, "DC"},
+		{"TestCase:UnstructuredName", args{UnstructuredName}, "unstructuredName"},
+		{"TestCase:UnstructuredAddress", args{UnstructuredAddress}, "unstructuredAddress"},
+		{"TestCase:StreetAddress", args{StreetAddress}, "street"},
+		{"TestCase:PostalCode", args{PostalCode}, "postalCode"},
+		{"TestCase:UserID", args{UserID}, "uid"},
+		{"TestCase:Generic", args{Generic}, "Generic"},
+		{"TestCase:UnKnownAttributeType", args{AttributeType(9999)}, "UnKnown"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := toDefinedShortName(tt.args.a); got != tt.want {
+				t.Errorf("toDefinedShortName() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateCountryCode(t *testing.T) {
+	type args struct {
+		c string
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    bool
+		wantErr bool
+	}{
+		{"TestCase:valid country code", args{c: "JP"}, true, false},
+		{"TestCase:valid country code", args{c: "US"}, true, false},
+		{"TestCase:invalid country code JPN", args{c: "JPN"}, false, true},
+		{"TestCase:invalid country code XYZ", args{c: "XYZ"}, false, true},
+		{"TestCase:empty country code", args{c: ""}, false, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ValidateCountryCode(tt.args.c)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateCountryCode() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("ValidateCountryCode() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLintResult_String(t *testing.T) {
+	tests := []struct {
+		name string
+		l    LintResult
+		want string
+	}{
+		{"TestCase: AttributeTypeAndValue", LintResult{1, 2, LintEmptyValue, "AttributeValue is empty"}, "1 th RDN 2 th AttributeTypeAndValue: empty_value: AttributeValue is empty"},
+		{"TestCase: RDN", LintResult{1, -1, LintNonCanonicalSetOrder, "not sorted"}, "1 th RDN: non_canonical_set_order: not sorted"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.l.String(); got != tt.want {
+				t.Errorf("String() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDN_Lint(t *testing.T) {
+	atv1 := AttributeTypeAndValue{Type: CountryName, Value: AttributeValue{PrintableString, "JP"}}
+	atv2 := AttributeTypeAndValue{Type: CountryName, Value: AttributeValue{PrintableString, "XX"}}
+	atv3 := AttributeTypeAndValue{Type: CommonName, Value: AttributeValue{UTF8String, ""}}
+	atv4 := AttributeTypeAndValue{Type: CommonName, Value: AttributeValue{UTF8String, " Mike"}}
+	atv5 := AttributeTypeAndValue{Type: Generic, Oid: "2.5.4.3", Value: AttributeValue{UTF8String, "Mike"}}
+	atv6 := AttributeTypeAndValue{Type: Generic, Oid: "2.5.4.6", Value: AttributeValue{PrintableString, "XX"}}
+	tests := []struct {
+		name        string
+		d           DN
+		wantResults []LintResult
+	}{
+		{"TestCase: 0 RDN", DN{}, []LintResult{}},
+		{"TestCase: no issue", DN{RDN{atv1}}, []LintResult{}},
+		{"TestCase: invalid country code", DN{RDN{atv2}}, []LintResult{{0, 0, LintInvalidCountryCode, "XX is not ISO 3166 alpha-2 code"}}},
+		{"TestCase: empty value", DN{RDN{atv1}, RDN{atv3}}, []LintResult{{1, 0, LintEmptyValue, "AttributeValue is empty"}}},
+		{"TestCase: surrounding spaces", DN{RDN{atv1}, RDN{atv5, atv4}}, []LintResult{
+			{1, 0, LintGenericKnownOid, "CommonName should be used instead of Generic"},
+			{1, 1, LintSurroundingSpaces, "AttributeValue has leading or trailing spaces"},
+		}},
+		{"TestCase: Generic(CountryName)", DN{RDN{atv6}}, []LintResult{
+			{0, 0, LintInvalidCountryCode, "XX is not ISO 3166 alpha-2 code"},
+			{0, 0, LintGenericKnownOid, "CountryName should be used instead of Generic"},
+		}},
+		{"TestCase: mixed script", DN{RDN{atv1}, RDN{AttributeTypeAndValue{Type: CommonName, Value: AttributeValue{UTF8String, "p\u0430ypal.com"}}}}, []LintResult{
+			{1, 0, LintMixedScript, "AttributeValue mixes Latin and Cyrillic characters"},
+		}},
+		{"TestCase: mismatched encoding", DN{RDN{AttributeTypeAndValue{Type: CountryName, Value: AttributeValue{UTF8String, "JP"}}}}, []LintResult{
+			{0, 0, LintMismatchedEncoding, "UTF8String is not allowed for CountryName"},
+		}},
+		{"TestCase: Generic(CountryName) mismatched encoding", DN{RDN{AttributeTypeAndValue{Type: Generic, Oid: "2.5.4.6", Value: AttributeValue{UTF8String, "JP"}}}}, []LintResult{
+			{0, 0, LintGenericKnownOid, "CountryName should be used instead of Generic"},
+			{0, 0, LintMismatchedEncoding, "UTF8String is not allowed for CountryName"},
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if gotResults := tt.d.Lint(); !reflect.DeepEqual(gotResults, tt.wantResults) {
+				t.Errorf("Lint() = %v, want %v", gotResults, tt.wantResults)
+			}
+		})
+	}
+}
+
+func TestDN_FindConfusables(t *testing.T) {
+	c := RDN{AttributeTypeAndValue{Type: CountryName, Value: AttributeValue{PrintableString, "JP"}}}
+	latin := RDN{AttributeTypeAndValue{Type: CommonName, Value: AttributeValue{UTF8String, "paypal.com"}}}
+	cyrillicA := RDN{AttributeTypeAndValue{Type: CommonName, Value: AttributeValue{UTF8String, "p\u0430ypal.com"}}}
+	greekO := AttributeTypeAndValue{Type: OrganizationName, Value: AttributeValue{UTF8String, "G\u03bfogle"}}
+	cyrillic := RDN{AttributeTypeAndValue{Type: CommonName, Value: AttributeValue{UTF8String, "Москва"}}}
+	japanese := RDN{AttributeTypeAndValue{Type: CommonName, Value: AttributeValue{UTF8String, "山田 Taro"}}}
+	tests := []struct {
+		name        string
+		d           DN
+		wantResults []LintResult
+	}{
+		{"TestCase: 0 RDN", DN{}, []LintResult{}},
+		{"TestCase: Latin only", DN{c, latin}, []LintResult{}},
+		{"TestCase: Cyrillic only", DN{c, cyrillic}, []LintResult{}},
+		{"TestCase: not confusable script", DN{c, japanese}, []LintResult{}},
+		{"TestCase: Cyrillic a in Latin", DN{c, cyrillicA}, []LintResult{
+			{1, 0, LintMixedScript, "AttributeValue mixes Latin and Cyrillic characters"},
+		}},
+		{"TestCase: Greek o in multi value RDN", DN{c, RDN{latin[0], greekO}}, []LintResult{
+			{1, 1, LintMixedScript, "AttributeValue mixes Latin and Greek characters"},
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if gotResults := tt.d.FindConfusables(); !reflect.DeepEqual(gotResults, tt.wantResults) {
+				t.Errorf("FindConfusables() = %v, want %v", gotResults, tt.wantResults)
+			}
+		})
+	}
+}
+
+func Test_confusableScripts(t *testing.T) {
+	type args struct {
+		v string
+	}
+	tests := []struct {
+		name        string
+		args        args
+		wantScripts []string
+	}{
+		{"TestCase: blank", args{""}, nil},
+		{"TestCase: digits and punctuation", args{"1.2-3"}, nil},
+		{"TestCase: Latin", args{"abc"}, []string{"Latin"}},
+		{"TestCase: Cyrillic and Latin", args{"\u0430bc"}, []string{"Cyrillic", "Latin"}},
+		{"TestCase: Latin, Greek and Cyrillic", args{"a\u03bf\u0430"}, []string{"Latin", "Greek", "Cyrillic"}},
+		{"TestCase: Japanese", args{"やまだ"}, nil},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if gotOk := isIA5StringEncoding(tt.args.e); gotOk != tt.wantOk {
-				t.Errorf("isIA5StringEncoding() = %v, want %v", gotOk, tt.wantOk)
+			if gotScripts := confusableScripts(tt.args.v); !reflect.DeepEqual(gotScripts, tt.wantScripts) {
+				t.Errorf("confusableScripts() = %v, want %v", gotScripts, tt.wantScripts)
 			}
 		})
 	}
 }
 
-func Test_isPrintableStringOrUTF8StringEncoding(t *testing.T) {
+func TestParseDERDNWithReport(t *testing.T) {
+	dn1 := DN{
+		RDN{
+			AttributeTypeAndValue{Type: OrganizationalUnit, Value: AttributeValue{Encoding: PrintableString, Value: "a"}},
+			AttributeTypeAndValue{Type: OrganizationalUnit, Value: AttributeValue{Encoding: PrintableString, Value: "aa"}},
+		},
+	}
+	dn2 := DN{
+		RDN{
+			AttributeTypeAndValue{Type: OrganizationalUnit, Value: AttributeValue{Encoding: PrintableString, Value: "aa"}},
+			AttributeTypeAndValue{Type: OrganizationalUnit, Value: AttributeValue{Encoding: PrintableString, Value: "a"}},
+		},
+	}
+	dn3 := DN{RDN{AttributeTypeAndValue{Type: CountryName, Value: AttributeValue{Encoding: PrintableString, Value: "XX"}}}}
+	dn4 := DN{RDN{AttributeTypeAndValue{Type: CountryName, Value: AttributeValue{Encoding: UTF8String, Value: "JP"}}}}
 	type args struct {
-		e Encoding
+		dnBytes []byte
 	}
 	tests := []struct {
-		name   string
-		args   args
-		wantOk bool
+		name        string
+		args        args
+		wantDn      DN
+		wantResults []LintResult
+		wantErr     bool
 	}{
-		{"TestCase: PrintableString", args{PrintableString}, true},
-		{"TestCase: UTF8String", args{UTF8String}, true},
-		{"TestCase: IA5String", args{IA5String}, false},
-		{"TestCase: The other", args{9999}, false},
+		{"TestCase:OU=a+OU=aa", args{decode("301731153008060355040B1301613009060355040B13026161")}, dn1, []LintResult{}, false},
+		{"TestCase:OU=aa+OU=a non canonical order", args{decode("301731153009060355040B130261613008060355040B130161")}, dn2,
+			[]LintResult{{0, -1, LintNonCanonicalSetOrder, "AttributeTypeAndValues are not sorted in DER order"}}, false},
+		{"TestCase:C=XX", args{decode("300d310b3009060355040613025858")}, dn3,
+			[]LintResult{{0, 0, LintInvalidCountryCode, "XX is not ISO 3166 alpha-2 code"}}, false},
+		{"TestCase:C=JP in UTF8String", args{decode("300d310b300906035504060c024a50")}, dn4,
+			[]LintResult{{0, 0, LintMismatchedEncoding, "UTF8String is not allowed for CountryName"}}, false},
+		{"TestCase:Broken DER DN", args{decode("13016161")}, nil, nil, true},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if gotOk := isPrintableStringOrUTF8StringEncoding(tt.args.e); gotOk != tt.wantOk {
-				t.Errorf("isPrintableStringOrUTF8StringEncoding() = %v, want %v", gotOk, tt.wantOk)
+			gotDn, gotResults, err := ParseDERDNWithReport(tt.args.dnBytes)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseDERDNWithReport() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(gotDn, tt.wantDn) {
+				t.Errorf("ParseDERDNWithReport() gotDn = %v, want %v", gotDn, tt.wantDn)
+			}
+			if !reflect.DeepEqual(gotResults, tt.wantResults) {
+				t.Errorf("ParseDERDNWithReport() gotResults = %v, want %v", gotResults, tt.wantResults)
 			}
 		})
 	}
 }
 
-func Test_isPrintableStringOrUTF8StringOrIA5StringEncoding(t *testing.T) {
+func TestDN_ValidateProfile(t *testing.T) {
+	c := RDN{AttributeTypeAndValue{Type: CountryName, Value: AttributeValue{PrintableString, "JP"}}}
+	o := RDN{AttributeTypeAndValue{Type: OrganizationName, Value: AttributeValue{UTF8String, "Example"}}}
+	o2 := RDN{AttributeTypeAndValue{Type: Generic, Oid: "2.5.4.10", Value: AttributeValue{UTF8String, "Example2"}}}
+	ou := RDN{AttributeTypeAndValue{Type: OrganizationalUnit, Value: AttributeValue{UTF8String, "Sales"}}}
+	sn := RDN{AttributeTypeAndValue{Type: SerialNumber, Value: AttributeValue{PrintableString, "1234"}}}
+	cn := RDN{AttributeTypeAndValue{Type: CommonName, Value: AttributeValue{UTF8String, "www.example.com"}}}
+	cnPrintable := RDN{AttributeTypeAndValue{Type: CommonName, Value: AttributeValue{PrintableString, "www.example.com"}}}
+	cnLong := RDN{AttributeTypeAndValue{Type: CommonName, Value: AttributeValue{UTF8String, strings.Repeat("a", 65)}}}
+	cnEmpty := RDN{AttributeTypeAndValue{Type: CommonName, Value: AttributeValue{UTF8String, ""}}}
+	generic := RDN{AttributeTypeAndValue{Type: Generic, Oid: "2.5.4.15", Value: AttributeValue{UTF8String, "Private Organization"}}}
+	street := RDN{AttributeTypeAndValue{Type: StreetAddress, Value: AttributeValue{UTF8String, "1-1 Chiyoda"}}}
+	postalCode := RDN{AttributeTypeAndValue{Type: PostalCode, Value: AttributeValue{UTF8String, "100-0001"}}}
+	custom := Profile{
+		Name:             "custom",
+		Required:         []AttributeType{CommonName},
+		AllowedEncodings: map[AttributeType][]Encoding{CommonName: {UTF8String}},
+	}
 	type args struct {
-		e Encoding
+		p Profile
 	}
 	tests := []struct {
-		name   string
-		args   args
-		wantOk bool
+		name     string
+		d        DN
+		args     args
+		wantErrs int
 	}{
-		{"TestCase: PrintableString", args{PrintableString}, true},
-		{"TestCase: UTF8String", args{UTF8String}, true},
-		{"TestCase: IA5String", args{IA5String}, true},
-		{"TestCase: The other", args{9999}, false},
+		{"TestCase: empty Profile", DN{c, ou, ou, cnLong}, args{Profile{}}, 0},
+		{"TestCase: TLS server, valid", DN{c, o, cn}, args{TLSServerProfile()}, 0},
+		{"TestCase: TLS server, OU is forbidden", DN{c, o, ou, cn}, args{TLSServerProfile()}, 1},
+		{"TestCase: TLS server, O appears twice", DN{c, o, o2, cn}, args{TLSServerProfile()}, 1},
+		{"TestCase: TLS server, too long CN", DN{c, o, cnLong}, args{TLSServerProfile()}, 1},
+		{"TestCase: TLS server, empty CN", DN{c, o, cnEmpty}, args{TLSServerProfile()}, 1},
+		{"TestCase: EV, valid", DN{c, o, sn, cn}, args{EVProfile()}, 0},
+		{"TestCase: EV, serialNumber is missing", DN{c, o, cn}, args{EVProfile()}, 1},
+		{"TestCase: EV, all required are missing and OU", DN{ou, cn}, args{EVProfile()}, 4},
+		{"TestCase: TLS server, serialNumber is not allowed", DN{c, o, sn, cn}, args{TLSServerProfile()}, 1},
+		{"TestCase: TLS server, Generic is not allowed", DN{c, o, generic, cn}, args{TLSServerProfile()}, 1},
+		{"TestCase: EV, Generic is allowed", DN{c, o, sn, generic, cn}, args{EVProfile()}, 0},
+		{"TestCase: EV, street and postalCode are allowed", DN{c, postalCode, street, o, sn, generic, cn}, args{EVProfile()}, 0},
+		{"TestCase: allowed, valid", DN{c, cn}, args{Profile{Allowed: []AttributeType{CountryName, CommonName}}}, 0},
+		{"TestCase: allowed, O is not allowed", DN{c, o, cn}, args{Profile{Allowed: []AttributeType{CountryName, CommonName}}}, 1},
+		{"TestCase: allowed is empty", DN{c}, args{Profile{Allowed: []AttributeType{}}}, 1},
+		{"TestCase: custom, valid", DN{cn}, args{custom}, 0},
+		{"TestCase: custom, encoding is not allowed", DN{cnPrintable}, args{custom}, 1},
+		{"TestCase: custom, CN is missing", DN{c}, args{custom}, 1},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if gotOk := isPrintableStringOrUTF8StringOrIA5StringEncoding(tt.args.e); gotOk != tt.wantOk {
-				t.Errorf("isPrintableStringOrUTF8StringOrIA5StringEncoding() = %v, want %v", gotOk, tt.wantOk)
+			if got := tt.d.ValidateProfile(tt.args.p); len(got) != tt.wantErrs {
+				t.Errorf("ValidateProfile() = %v, want %d errors", got, tt.wantErrs)
 			}
 		})
 	}
 }
 
-func Test_isValidAttributeValueEncoding(t *testing.T) {
-	type args struct {
-		av AttributeValue
-	}
+func TestDN_CheckEncodingWhitelist(t *testing.T) {
+	allowed := map[AttributeType][]Encoding{
+		CommonName:            {UTF8String, PrintableString},
+		OrganizationName:      {UTF8String},
+		ElectronicMailAddress: {IA5String},
+	}
+	c := AttributeTypeAndValue{Type: CountryName, Value: AttributeValue{PrintableString, "JP"}}
+	oP := AttributeTypeAndValue{Type: OrganizationName, Value: AttributeValue{PrintableString, "Example"}}
+	oU := AttributeTypeAndValue{Type: OrganizationName, Value: AttributeValue{UTF8String, "Example"}}
+	cn := AttributeTypeAndValue{Type: CommonName, Value: AttributeValue{PrintableString, "Mike"}}
+	gO := AttributeTypeAndValue{Type: Generic, Oid: "2.5.4.10", Value: AttributeValue{PrintableString, "Example"}}
 	tests := []struct {
-		name        string
-		args        args
-		wantIsValid bool
-		wantErr     bool
+		name       string
+		d          DN
+		wantErrors []string
 	}{
-		{"TestCase: PrintableString", args{AttributeValue{Encoding: PrintableString}}, true, false},
-		{"TestCase: UTF8String", args{AttributeValue{Encoding: UTF8String}}, true, false},
-		{"TestCase: IA5String", args{AttributeValue{Encoding: IA5String}}, true, false},
-		{"TestCase: The other", args{AttributeValue{Encoding: 999}}, false, true},
+		{"TestCase: 0 RDN", DN{}, []string{}},
+		{"TestCase: no violation", DN{RDN{c}, RDN{oU}, RDN{cn}}, []string{}},
+		{"TestCase: violation", DN{RDN{c}, RDN{oP}, RDN{cn}}, []string{"1 th RDN 0 th AttributeTypeAndValue: PrintableString is not allowed for OrganizationName"}},
+		{"TestCase: multiple violations", DN{RDN{c}, RDN{cn, oP, gO}}, []string{
+			"1 th RDN 1 th AttributeTypeAndValue: PrintableString is not allowed for OrganizationName",
+			"1 th RDN 2 th AttributeTypeAndValue: PrintableString is not allowed for OrganizationName",
+		}},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			gotIsValid, err := isValidAttributeValueEncoding(tt.args.av)
-			if (err != nil) != tt.wantErr {
-				t.Errorf("isValidAttributeValueEncoding() error = %v, wantErr %v", err, tt.wantErr)
-				return
+			gotErrors := []string{}
+			for _, err := range tt.d.CheckEncodingWhitelist(allowed) {
+				gotErrors = append(gotErrors, err.Error())
 			}
-			if gotIsValid != tt.wantIsValid {
-				t.Errorf("isValidAttributeValueEncoding() gotIsValid = %v, want %v", gotIsValid, tt.wantIsValid)
+			if !reflect.DeepEqual(gotErrors, tt.wantErrors) {
+				t.Errorf("CheckEncodingWhitelist() = %v, want %v", gotErrors, tt.wantErrors)
 			}
 		})
 	}
 }
 
-func Test_isValidAttributeTypeAndValue(t *testing.T) {
-	type args struct {
-		atv AttributeTypeAndValue
-	}
+func TestValidateTLSServerSubject(t *testing.T) {
+	c := RDN{AttributeTypeAndValue{Type: CountryName, Value: AttributeValue{PrintableString, "JP"}}}
+	cUTF8 := RDN{AttributeTypeAndValue{Type: CountryName, Value: AttributeValue{UTF8String, "JP"}}}
+	cLong := RDN{AttributeTypeAndValue{Type: CountryName, Value: AttributeValue{PrintableString, "JPN"}}}
+	st := RDN{AttributeTypeAndValue{Type: StateOrProvinceName, Value: AttributeValue{UTF8String, "Tokyo"}}}
+	l := RDN{AttributeTypeAndValue{Type: LocalityName, Value: AttributeValue{UTF8String, "Chiyoda"}}}
+	street := RDN{AttributeTypeAndValue{Type: StreetAddress, Value: AttributeValue{UTF8String, "1-1 Chiyoda"}}}
+	postalCode := RDN{AttributeTypeAndValue{Type: PostalCode, Value: AttributeValue{UTF8String, "100-0001"}}}
+	o := RDN{AttributeTypeAndValue{Type: OrganizationName, Value: AttributeValue{UTF8String, "Example"}}}
+	ou := RDN{AttributeTypeAndValue{Type: OrganizationalUnit, Value: AttributeValue{UTF8String, "Sales"}}}
+	cn := RDN{AttributeTypeAndValue{Type: CommonName, Value: AttributeValue{UTF8String, "www.example.com"}}}
+	cn2 := RDN{AttributeTypeAndValue{Type: CommonName, Value: AttributeValue{UTF8String, "example.com"}}}
+	cnLong := RDN{AttributeTypeAndValue{Type: CommonName, Value: AttributeValue{UTF8String, strings.Repeat("a", 65)}}}
+	e := RDN{AttributeTypeAndValue{Type: ElectronicMailAddress, Value: AttributeValue{IA5String, "admin@example.com"}}}
 	tests := []struct {
-		name        string
-		args        args
-		wantIsValid bool
-		wantErr     bool
+		name       string
+		dn         DN
+		wantErrors []string
 	}{
-		{"TestCase: CountryName, PrintableString", args{AttributeTypeAndValue{Type: CountryName, Value: AttributeValue{Encoding: PrintableString}}}, true, false},
-		{"TestCase: The other, PrintableString", args{AttributeTypeAndValue{Type: 999, Value: AttributeValue{Encoding: PrintableString}}}, false, true},
-		{"TestCase: CountryName, The other", args{AttributeTypeAndValue{Type: CountryName, Value: AttributeValue{Encoding: 999}}}, false, true},
-		{"TestCase: CountryName, UTF8String", args{AttributeTypeAndValue{Type: CountryName, Value: AttributeValue{Encoding: UTF8String}}}, false, true},
-		{"TestCase: Generic, PrintableString", args{AttributeTypeAndValue{Type: Generic, Oid: "1.2", Value: AttributeValue{Encoding: PrintableString}}}, true, false},
-		{"TestCase: Generic, The other", args{AttributeTypeAndValue{Type: Generic, Oid: "1.2", Value: AttributeValue{Encoding: 999}}}, false, true},
-		{"TestCase: Generic(CountryName), UTF8String", args{AttributeTypeAndValue{Type: Generic, Oid: "2.5.4.6", Value: AttributeValue{Encoding: UTF8String}}}, false, true},
+		{"TestCase: empty DN", DN{}, []string{}},
+		{"TestCase: CN only", DN{cn}, []string{}},
+		{"TestCase: OV subject", DN{c, st, l, o, cn}, []string{}},
+		{"TestCase: OV subject with street and postalCode", DN{c, st, l, street, postalCode, o, cn}, []string{}},
+		{"TestCase: without CN", DN{c, st, l, o}, []string{}},
+		{"TestCase: OU", DN{c, o, ou, cn}, []string{"profile TLS server: OrganizationUnit is forbidden"}},
+		{"TestCase: E", DN{c, o, cn, e}, []string{"profile TLS server: 3 th RDN 0 th AttributeTypeAndValue: email is not allowed"}},
+		{"TestCase: C in UTF8String", DN{cUTF8, cn}, []string{"profile TLS server: 0 th RDN 0 th AttributeTypeAndValue: UTF8String is not allowed for CountryName"}},
+		{"TestCase: C of 3 characters", DN{cLong, cn}, []string{"profile TLS server: 0 th RDN 0 th AttributeTypeAndValue: length 3 of CountryName is out of bounds"}},
+		{"TestCase: too long CN", DN{c, cnLong}, []string{"profile TLS server: 1 th RDN 0 th AttributeTypeAndValue: length 65 of CommonName is out of bounds"}},
+		{"TestCase: 2 CNs", DN{c, cn, cn2}, []string{"profile TLS server: CommonName appears 2 times"}},
+		{"TestCase: 2 Cs", DN{c, c, cn}, []string{"profile TLS server: CountryName appears 2 times"}},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			gotIsValid, err := isValidAttributeTypeAndValue(tt.args.atv)
-			if (err != nil) != tt.wantErr {
-				t.Errorf("isValidAttributeTypeAndValue() error = %v, wantErr %v", err, tt.wantErr)
-				return
+			got := []string{}
+			for _, err := range ValidateTLSServerSubject(tt.dn) {
+				got = append(got, err.Error())
 			}
-			if gotIsValid != tt.wantIsValid {
-				t.Errorf("isValidAttributeTypeAndValue() gotIsValid = %v, want %v", gotIsValid, tt.wantIsValid)
+			if !reflect.DeepEqual(got, tt.wantErrors) {
+				t.Errorf("ValidateTLSServerSubject() = %v, want %v", got, tt.wantErrors)
 			}
 		})
 	}
 }
 
-func Test_isValidRDN(t *testing.T) {
-	atv1 := AttributeTypeAndValue{Type: CountryName, Value: AttributeValue{Encoding: PrintableString}}
-	atv2 := AttributeTypeAndValue{Type: Generic, Oid: "1.2.3", Value: AttributeValue{Encoding: UTF8String}}
-	atv3 := AttributeTypeAndValue{Type: CountryName, Value: AttributeValue{Encoding: UTF8String}}
-	atv4 := AttributeTypeAndValue{Type: ElectronicMailAddress, Value: AttributeValue{Encoding: UTF8String}}
+func Test_containsAttributeType(t *testing.T) {
 	type args struct {
-		r RDN
+		ats []AttributeType
+		at  AttributeType
 	}
 	tests := []struct {
-		name        string
-		args        args
-		wantIsValid bool
-		wantErr     bool
+		name string
+		args args
+		want bool
 	}{
-		{"TestCase: 0 AttributeTypeAndValue element", args{RDN{}}, false, true},
-		{"TestCase: 1 AttributeTypeAndValue element", args{RDN{atv1}}, true, false},
-		{"TestCase: 2 AttributeTypeAndValue element", args{RDN{atv1, atv2}}, true, false},
-		{"TestCase: 1 invalid AttributeTypeAndValue element", args{RDN{atv3}}, false, true},
-		{"TestCase: 2 invalid AttributeTypeAndValue element", args{RDN{atv3, atv4}}, false, true},
+		{"TestCase: contained", args{[]AttributeType{CountryName, CommonName}, CommonName}, true},
+		{"TestCase: not contained", args{[]AttributeType{CountryName}, CommonName}, false},
+		{"TestCase: empty", args{[]AttributeType{}, CommonName}, false},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			gotIsValid, err := isValidRDN(tt.args.r)
-			if (err != nil) != tt.wantErr {
-				t.Errorf("isValidRDN() error = %v, wantErr %v", err, tt.wantErr)
-				return
-			}
-			if gotIsValid != tt.wantIsValid {
-				t.Errorf("isValidRDN() gotIsValid = %v, want %v", gotIsValid, tt.wantIsValid)
+			if got := containsAttributeType(tt.args.ats, tt.args.at); got != tt.want {
+				t.Errorf("containsAttributeType() = %v, want %v", got, tt.want)
 			}
 		})
 	}
 }
 
-func Test_isValidDN(t *testing.T) {
-	atv1 := AttributeTypeAndValue{Type: CountryName, Value: AttributeValue{Encoding: PrintableString}}
-	atv2 := AttributeTypeAndValue{Type: Generic, Oid: "1.2.3", Value: AttributeValue{Encoding: UTF8String}}
-	atv3 := AttributeTypeAndValue{Type: CountryName, Value: AttributeValue{Encoding: UTF8String}}
-	atv4 := AttributeTypeAndValue{Type: ElectronicMailAddress, Value: AttributeValue{Encoding: UTF8String}}
-	rdn1 := RDN{atv1, atv2}
-	rdn2 := RDN{atv2}
-	rdn3 := RDN{atv1, atv3}
-	rdn4 := RDN{atv4}
-
+func Test_containsEncoding(t *testing.T) {
 	type args struct {
-		d DN
+		encs []Encoding
+		e    Encoding
 	}
 	tests := []struct {
-		name        string
-		args        args
-		wantIsValid bool
-		wantErr     bool
+		name string
+		args args
+		want bool
 	}{
-		{"TestCase: 0 RDN element", args{DN{}}, true, false},
-		{"TestCase: 1 RDN element", args{DN{rdn1}}, true, false},
-		{"TestCase: 2 RDN element", args{DN{rdn1, rdn2}}, true, false},
-		{"TestCase: 1 invalid RDN element", args{DN{rdn3}}, false, true},
-		{"TestCase: 2 invalid RDN element", args{DN{rdn3, rdn4}}, false, true},
+		{"TestCase: contained", args{[]Encoding{PrintableString, UTF8String}, UTF8String}, true},
+		{"TestCase: not contained", args{[]Encoding{PrintableString}, UTF8String}, false},
+		{"TestCase: empty", args{[]Encoding{}, UTF8String}, false},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			gotIsValid, err := isValidDN(tt.args.d)
-			if (err != nil) != tt.wantErr {
-				t.Errorf("isValidDN() error = %v, wantErr %v", err, tt.wantErr)
-				return
-			}
-			if gotIsValid != tt.wantIsValid {
-				t.Errorf("isValidDN() gotIsValid = %v, want %v", gotIsValid, tt.wantIsValid)
+			if got := containsEncoding(tt.args.encs, tt.args.e); got != tt.want {
+				t.Errorf("containsEncoding() = %v, want %v", got, tt.want)
 			}
 		})
 	}
 }
 
-func TestDN_RetrieveRDN(t *testing.T) {
-	atv1 := AttributeTypeAndValue{Type: CountryName, Value: AttributeValue{Encoding: PrintableString}}
-	atv2 := AttributeTypeAndValue{Type: CommonName, Value: AttributeValue{Encoding: UTF8String}}
+func TestParseRFC4514DN(t *testing.T) {
+	c := RDN{AttributeTypeAndValue{Type: CountryName, Value: AttributeValue{PrintableString, "JP"}}}
+	o := RDN{AttributeTypeAndValue{Type: OrganizationName, Value: AttributeValue{UTF8String, "example"}}}
+	ou := RDN{
+		AttributeTypeAndValue{Type: OrganizationalUnit, Value: AttributeValue{UTF8String, "Sales"}},
+		AttributeTypeAndValue{Type: OrganizationalUnit, Value: AttributeValue{UTF8String, "Dev"}},
+	}
+	cn := func(v string) RDN {
+		return RDN{AttributeTypeAndValue{Type: CommonName, Value: AttributeValue{UTF8String, v}}}
+	}
 	type args struct {
-		index int
+		s string
 	}
 	tests := []struct {
 		name    string
-		d       DN
 		args    args
-		wantRdn RDN
+		wantDn  DN
 		wantErr bool
 	}{
-		{"TestCase: 0 RDN element index 0", DN{}, args{0}, RDN{}, true},
-		{"TestCase: 1 RDN element index 0", DN{RDN{atv1}}, args{0}, RDN{atv1}, false},
-		{"TestCase: 1 RDN element index 1", DN{RDN{atv1}}, args{1}, RDN{}, true},
-		{"TestCase: 2 RDN element index -1", DN{RDN{atv1}, RDN{atv2}}, args{-1}, RDN{}, true},
-		{"TestCase: 2 RDN element index 0", DN{RDN{atv1}, RDN{atv2}}, args{0}, RDN{atv1}, false},
-		{"TestCase: 2 RDN element index 1", DN{RDN{atv1}, RDN{atv2}}, args{1}, RDN{atv2}, false},
-		{"TestCase: 2 RDN element index 2", DN{RDN{atv1}, RDN{atv2}}, args{2}, RDN{}, true},
+		{"TestCase: empty", args{""}, DN{}, false},
+		{"TestCase: escaped quotes and comma, multi-valued RDN", args{`CN=James \"Jim\" Smith\, III,OU=Sales+OU=Dev,O=example,C=JP`}, DN{c, o, ou, cn(`James "Jim" Smith, III`)}, false},
+		{"TestCase: escaped plus sign", args{`CN=a\+b`}, DN{cn("a+b")}, false},
+		{"TestCase: escaped leading space and number sign", args{`CN=\ \#a`}, DN{cn(" #a")}, false},
+		{"TestCase: escaped trailing space", args{`CN=a\ `}, DN{cn("a ")}, false},
+		{"TestCase: hex pairs", args{`CN=\E3\81\82\2C`}, DN{cn("あ,")}, false},
+		{"TestCase: lower case descriptors", args{`cn=a,dc=example,dc=com`}, DN{
+			RDN{AttributeTypeAndValue{Type: DomainComponent, Value: AttributeValue{IA5String, "com"}}},
+			RDN{AttributeTypeAndValue{Type: DomainComponent, Value: AttributeValue{IA5String, "example"}}},
+			cn("a"),
+		}, false},
+		{"TestCase: email", args{`email=a@example.com`}, DN{RDN{AttributeTypeAndValue{Type: ElectronicMailAddress, Value: AttributeValue{IA5String, "a@example.com"}}}}, false},
+		{"TestCase: numericoid of known AttributeType", args{`2.5.4.3=a`}, DN{cn("a")}, false},
+		{"TestCase: numericoid of unknown AttributeType", args{`1.2.3.4=a`}, DN{RDN{AttributeTypeAndValue{Type: Generic, Oid: "1.2.3.4", Value: AttributeValue{UTF8String, "a"}}}}, false},
+		{"TestCase: hex value", args{`CN=#0c0161`}, DN{cn("a")}, false},
+		{"TestCase: unknown descriptor", args{`XX=a`}, nil, true},
+		{"TestCase: unescaped special character", args{`CN=a"b`}, nil, true},
+		{"TestCase: invalid hex pair", args{`CN=\zz`}, nil, true},
+		{"TestCase: missing =", args{`CN`}, nil, true},
+		{"TestCase: semicolon separator", args{`CN=a;C=JP`}, nil, true},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			gotRdn, err := tt.d.RetrieveRDN(tt.args.index)
+			gotDn, err := ParseRFC4514DN(tt.args.s)
 			if (err != nil) != tt.wantErr {
-				t.Errorf("RetrieveRDN() error = %v, wantErr %v", err, tt.wantErr)
+				t.Errorf("ParseRFC4514DN() error = %v, wantErr %v", err, tt.wantErr)
 				return
 			}
-			if !reflect.DeepEqual(gotRdn, tt.wantRdn) {
-				t.Errorf("RetrieveRDN() gotRdn = %v, want %v", gotRdn, tt.wantRdn)
+			if !reflect.DeepEqual(gotDn, tt.wantDn) {
+				t.Errorf("ParseRFC4514DN() gotDn = %v, want %v", gotDn, tt.wantDn)
 			}
 		})
 	}
 }
 
-func TestDN_RetrieveRDNsByAttributeTypes(t *testing.T) {
-	atv1 := AttributeTypeAndValue{Type: CountryName, Value: AttributeValue{Encoding: PrintableString}}
-	atv2 := AttributeTypeAndValue{Type: CommonName, Value: AttributeValue{Encoding: UTF8String}}
-	atv3 := AttributeTypeAndValue{Type: OrganizationName, Value: AttributeValue{Encoding: UTF8String}}
-	atv4 := AttributeTypeAndValue{Type: OrganizationalUnit, Value: AttributeValue{Encoding: UTF8String}}
-	atv5 := AttributeTypeAndValue{Type: Generic, Oid: "1.2", Value: AttributeValue{Encoding: UTF8String}}
+func TestParseRFC4514DNWithOptions(t *testing.T) {
+	c := RDN{AttributeTypeAndValue{Type: CountryName, Value: AttributeValue{PrintableString, "JP"}}}
+	cn := func(v string) RDN {
+		return RDN{AttributeTypeAndValue{Type: CommonName, Value: AttributeValue{UTF8String, v}}}
+	}
 	type args struct {
-		ats []AttributeType
+		s string
+		o RFC4514ParseOptions
 	}
 	tests := []struct {
-		name     string
-		d        DN
-		args     args
-		wantRdns []RDN
+		name    string
+		args    args
+		wantDn  DN
+		wantErr bool
 	}{
-		{"TestCase: DN has 0 RDN, 0 AttributeType, not matched", DN{}, args{[]AttributeType{}}, []RDN{}},
-		{"TestCase: DN has 0 RDN, 1 AttributeType, not matched", DN{}, args{[]AttributeType{CountryName}}, []RDN{}},
-		{"TestCase: DN has 1 RDN, the RDN has 1 Attribute , 0 AttributeType, not matched", DN{RDN{atv1}}, args{[]AttributeType{}}, []RDN{}},
-		{"TestCase: DN has 1 RDN, the RDN has 1 Attribute , 1 AttributeType, matched", DN{RDN{atv1}}, args{[]AttributeType{CountryName}}, []RDN{RDN{atv1}}},
-		{"TestCase: DN has 1 RDN, the RDN has 1 Attribute , 1 Generic AttributeType, not matched", DN{RDN{atv1}}, args{[]AttributeType{Generic}}, []RDN{}},
-		{"TestCase: DN has 1 RDN, the RDN has 1 Attribute , 1 Generic AttributeType, matched", DN{RDN{atv5}}, args{[]AttributeType{Generic}}, []RDN{RDN{atv5}}},
-		{"TestCase: DN has 1 RDN, the RDN has 1 Attribute , 1 AttributeType, not matched", DN{RDN{atv1}}, args{[]AttributeType{OrganizationName}}, []RDN{}},
-		{"TestCase: DN has 2 RDN, the RDN has 1 Attribute , 1 AttributeType, 1 matched", DN{RDN{atv1}, RDN{atv2}}, args{[]AttributeType{CountryName}}, []RDN{RDN{atv1}}},
-		{"TestCase: DN has 2 RDN, the RDN has 2 Attribute , 2 AttributeType, 2 matched", DN{RDN{atv1, atv2}, RDN{atv1, atv2}}, args{[]AttributeType{CountryName, CommonName}}, []RDN{RDN{atv1, atv2}, RDN{atv1, atv2}}},
-		{"TestCase: DN has 2 RDN, the RDN has 2 Attribute , 2 AttributeType, 1 matched", DN{RDN{atv1, atv2}, RDN{atv3, atv4}}, args{[]AttributeType{CountryName, CommonName}}, []RDN{RDN{atv1, atv2}}},
-		{"TestCase: DN has 2 RDN, the RDN has 2 Attribute , 2 Generic AttributeType, 2 matched", DN{RDN{atv5, atv5}, RDN{atv5, atv5}}, args{[]AttributeType{Generic, Generic}}, []RDN{RDN{atv5, atv5}, RDN{atv5, atv5}}},
-		{"TestCase: DN has 2 RDN, the RDN has 2 Attribute , 2 Generic AttributeType, 1 matched", DN{RDN{atv1, atv2}, RDN{atv5, atv2}}, args{[]AttributeType{Generic, CommonName}}, []RDN{RDN{atv5, atv2}}},
-		{"TestCase: DN has 2 RDN, the RDN has 2 Attribute , 2 AttributeType, not matched", DN{RDN{atv1, atv2}, RDN{atv3, atv4}}, args{[]AttributeType{OrganizationName, CommonName}}, []RDN{}},
-		{"TestCase: DN has 2 RDN, the RDN has 2 Attribute , 3 AttributeType, not matched", DN{RDN{atv1, atv2}, RDN{atv3, atv4}}, args{[]AttributeType{CountryName, OrganizationName, CommonName}}, []RDN{}},
+		{"TestCase: zero options", args{"CN=Mike,C=JP", RFC4514ParseOptions{}}, DN{c, cn("Mike")}, false},
+		{"TestCase: zero options with semicolon separator", args{"CN=Mike;C=JP", RFC4514ParseOptions{}}, nil, true},
+		{"TestCase: SemicolonSeparator", args{"CN=Mike;C=JP", RFC4514ParseOptions{SemicolonSeparator: true}}, DN{c, cn("Mike")}, false},
+		{"TestCase: SemicolonSeparator with comma", args{"CN=Mike,C=JP", RFC4514ParseOptions{SemicolonSeparator: true}}, DN{c, cn("Mike")}, false},
+		{"TestCase: SemicolonSeparator with escaped semicolon", args{`CN=Mi\;ke;C=JP`, RFC4514ParseOptions{SemicolonSeparator: true}}, DN{c, cn("Mi;ke")}, false},
+		{"TestCase: zero options with line continuation", args{"CN=Mike,\\\nC=JP", RFC4514ParseOptions{}}, nil, true},
+		{"TestCase: LineContinuation", args{"CN=Mike,\\\nC=JP", RFC4514ParseOptions{LineContinuation: true}}, DN{c, cn("Mike")}, false},
+		{"TestCase: LineContinuation with CRLF in value", args{"CN=Mi\\\r\nke,C=JP", RFC4514ParseOptions{LineContinuation: true}}, DN{c, cn("Mike")}, false},
+		{"TestCase: LineContinuation with escaped backslash", args{"CN=Mike\\\\\n,C=JP", RFC4514ParseOptions{LineContinuation: true}}, DN{c, cn("Mike\\\n")}, false},
+		{"TestCase: LineContinuation and SemicolonSeparator", args{"CN=Mike;\\\nC=JP", RFC4514ParseOptions{SemicolonSeparator: true, LineContinuation: true}}, DN{c, cn("Mike")}, false},
+		{"TestCase: zero options with trailing separator", args{"CN=Mike,C=JP,", RFC4514ParseOptions{}}, nil, true},
+		{"TestCase: TrailingSeparator", args{"CN=Mike,C=JP,", RFC4514ParseOptions{TrailingSeparator: true}}, DN{c, cn("Mike")}, false},
+		{"TestCase: TrailingSeparator with 2 trailing separators", args{"CN=Mike,C=JP,,", RFC4514ParseOptions{TrailingSeparator: true}}, nil, true},
+		{"TestCase: TrailingSeparator and SemicolonSeparator", args{"CN=Mike;C=JP;", RFC4514ParseOptions{SemicolonSeparator: true, TrailingSeparator: true}}, DN{c, cn("Mike")}, false},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if gotRdns := tt.d.RetrieveRDNsByAttributeTypes(tt.args.ats); !reflect.DeepEqual(gotRdns, tt.wantRdns) {
-				t.Errorf("RetrieveRDNsByAttributeTypes() = %v, want %v", gotRdns, tt.wantRdns)
+			gotDn, err := ParseRFC4514DNWithOptions(tt.args.s, tt.args.o)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseRFC4514DNWithOptions() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(gotDn, tt.wantDn) {
+				t.Errorf("ParseRFC4514DNWithOptions() gotDn = %v, want %v", gotDn, tt.wantDn)
 			}
 		})
 	}
 }
 
-func TestDN_RetrieveRDNsByOids(t *testing.T) {
-	atv1 := AttributeTypeAndValue{Type: CountryName, Value: AttributeValue{Encoding: PrintableString}}
-	atv2 := AttributeTypeAndValue{Type: CommonName, Value: AttributeValue{Encoding: UTF8String}}
-	atv3 := AttributeTypeAndValue{Type: OrganizationName, Value: AttributeValue{Encoding: UTF8String}}
-	atv4 := AttributeTypeAndValue{Type: OrganizationalUnit, Value: AttributeValue{Encoding: UTF8String}}
-	atv5 := AttributeTypeAndValue{Type: Generic, Oid: "1.2", Value: AttributeValue{Encoding: UTF8String}}
-	atv6 := AttributeTypeAndValue{Type: Generic, Oid: "1.2.3", Value: AttributeValue{Encoding: UTF8String}}
+func TestParseRFC4514DN_RoundTrip(t *testing.T) {
+	dn := DN{
+		RDN{AttributeTypeAndValue{Type: CountryName, Value: AttributeValue{PrintableString, "JP"}}},
+		RDN{AttributeTypeAndValue{Type: OrganizationName, Value: AttributeValue{UTF8String, "Example, Inc."}}},
+		RDN{
+			AttributeTypeAndValue{Type: OrganizationalUnit, Value: AttributeValue{UTF8String, " Dev+Ops "}},
+			AttributeTypeAndValue{Type: OrganizationalUnit, Value: AttributeValue{UTF8String, "#1"}},
+		},
+		RDN{AttributeTypeAndValue{Type: Generic, Oid: "1.2.3.4", Value: AttributeValue{UTF8String, `a\b"c<d>e;`}}},
+		RDN{AttributeTypeAndValue{Type: CommonName, Value: AttributeValue{UTF8String, "やまだ"}}},
+	}
+	got, err := ParseRFC4514DN(dn.ToRFC4514FormatString())
+	if err != nil {
+		t.Fatalf("ParseRFC4514DN() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, dn) {
+		t.Errorf("ParseRFC4514DN() got = %v, want %v", got, dn)
+	}
+	want, err := MarshalDN(dn)
+	if err != nil {
+		t.Fatalf("MarshalDN() error = %v", err)
+	}
+	b, err := MarshalDN(got)
+	if err != nil || !reflect.DeepEqual(b, want) {
+		t.Errorf("MarshalDN() = %x, %v, want %x", b, err, want)
+	}
+}
+
+func TestParseRFC1779DN(t *testing.T) {
+	c := RDN{AttributeTypeAndValue{Type: CountryName, Value: AttributeValue{PrintableString, "JP"}}}
+	o := RDN{AttributeTypeAndValue{Type: OrganizationName, Value: AttributeValue{UTF8String, "Example, Inc."}}}
+	cn := RDN{AttributeTypeAndValue{Type: CommonName, Value: AttributeValue{UTF8String, "Mike"}}}
 	type args struct {
-		oids []string
+		s string
 	}
 	tests := []struct {
-		name     string
-		d        DN
-		args     args
-		wantRdns []RDN
+		name    string
+		args    args
+		wantDn  DN
+		wantErr bool
 	}{
-		{"TestCase: DN has 0 RDN, 0 AttributeType, not matched", DN{}, args{[]string{}}, []RDN{}},
-		{"TestCase: DN has 0 RDN, 1 AttributeType, not matched", DN{}, args{[]string{"2.5.4.6"}}, []RDN{}},
-		{"TestCase: DN has 1 RDN, the RDN has 1 Attribute , 0 AttributeType, not matched", DN{RDN{atv1}}, args{[]string{}}, []RDN{}},
-		{"TestCase: DN has 1 RDN, the RDN has 1 Attribute , 1 AttributeType, matched", DN{RDN{atv1}}, args{[]string{"2.5.4.6"}}, []RDN{RDN{atv1}}},
-		{"TestCase: DN has 1 RDN, the RDN has 1 Attribute , 1 AttributeType, not matched", DN{RDN{atv1}}, args{[]string{"2.5.4.11"}}, []RDN{}},
-		{"TestCase: DN has 2 RDN, the RDN has 1 Attribute , 1 AttributeType, 1 matched", DN{RDN{atv1}, RDN{atv2}}, args{[]string{"2.5.4.6"}}, []RDN{RDN{atv1}}},
-		{"TestCase: DN has 2 RDN, the RDN has 2 Attribute , 2 AttributeType, 2 matched", DN{RDN{atv5, atv6}, RDN{atv6, atv5}}, args{[]string{"1.2", "1.2.3"}}, []RDN{RDN{atv5, atv6}, RDN{atv6, atv5}}},
-		{"TestCase: DN has 2 RDN, the RDN has 2 Attribute , 2 AttributeType, 1 matched", DN{RDN{atv5, atv6}, RDN{atv5, atv5}}, args{[]string{"1.2", "1.2.3"}}, []RDN{RDN{atv5, atv6}}},
-		{"TestCase: DN has 2 RDN, the RDN has 2 Attribute , 2 AttributeType, not matched", DN{RDN{atv1, atv2}, RDN{atv3, atv4}}, args{[]string{"2.5.4.10", "2.5.4.6"}}, []RDN{}},
-		{"TestCase: DN has 2 RDN, the RDN has 2 Attribute , 3 AttributeType, not matched", DN{RDN{atv1, atv2}, RDN{atv3, atv4}}, args{[]string{"2.5.4.6", "2.5.4.10", "2.5.4.6"}}, []RDN{}},
+		{"TestCase: empty", args{""}, DN{}, false},
+		{"TestCase: quoted value", args{`CN=Mike, O="Example, Inc.", C=JP`}, DN{c, o, cn}, false},
+		{"TestCase: OID. prefix", args{`OID.2.5.4.3=Mike, O="Example, Inc.", oid.2.5.4.6=JP`}, DN{c, o, cn}, false},
+		{"TestCase: semicolon separator and spaces", args{`CN = Mike ; O = "Example, Inc." ; C = JP`}, DN{c, o, cn}, false},
+		{"TestCase: escaped quote in quoted value", args{`CN="Mike \"M\""`}, DN{RDN{AttributeTypeAndValue{Type: CommonName, Value: AttributeValue{UTF8String, `Mike "M"`}}}}, false},
+		{"TestCase: spaces in quoted value are kept", args{`CN=" Mike "`}, DN{RDN{AttributeTypeAndValue{Type: CommonName, Value: AttributeValue{UTF8String, " Mike "}}}}, false},
+		{"TestCase: escaped comma in unquoted value", args{`O=Example\, Inc.`}, DN{o}, false},
+		{"TestCase: multi value RDN", args{`CN=Mike + E=mike@example.com, C=JP`}, DN{c, RDN{
+			AttributeTypeAndValue{Type: CommonName, Value: AttributeValue{UTF8String, "Mike"}},
+			AttributeTypeAndValue{Type: ElectronicMailAddress, Value: AttributeValue{IA5String, "mike@example.com"}},
+		}}, false},
+		{"TestCase: unknown oid", args{`OID.1.2.3.4=x`}, DN{RDN{AttributeTypeAndValue{Type: Generic, Oid: "1.2.3.4", Value: AttributeValue{UTF8String, "x"}}}}, false},
+		{"TestCase: hex value", args{`CN=#0c044d696b65`}, DN{cn}, false},
+		{"TestCase: unterminated quoted value", args{`CN="Mike`}, nil, true},
+		{"TestCase: characters after quoted value", args{`CN="Mike"x`}, nil, true},
+		{"TestCase: OID. prefix with descriptor", args{`OID.CN=Mike`}, nil, true},
+		{"TestCase: unknown descriptor", args{`XX=Mike`}, nil, true},
+		{"TestCase: missing =", args{`CN Mike`}, nil, true},
+		{"TestCase: invalid encoding for CountryName", args{`C=#0c024a50`}, nil, true},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if gotRdns := tt.d.RetrieveRDNsByOids(tt.args.oids); !reflect.DeepEqual(gotRdns, tt.wantRdns) {
-				t.Errorf("RetrieveRDNsByOids() = %v, want %v", gotRdns, tt.wantRdns)
+			gotDn, err := ParseRFC1779DN(tt.args.s)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseRFC1779DN() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(gotDn, tt.wantDn) {
+				t.Errorf("ParseRFC1779DN() gotDn = %v, want %v", gotDn, tt.wantDn)
 			}
 		})
 	}
 }
 
-func Test_removeAttributeTypeAndValue(t *testing.T) {
-	atv1 := AttributeTypeAndValue{Type: CountryName, Value: AttributeValue{Encoding: PrintableString}}
-	atv2 := AttributeTypeAndValue{Type: CommonName, Value: AttributeValue{Encoding: UTF8String}}
-	atv3 := AttributeTypeAndValue{Type: OrganizationName, Value: AttributeValue{Encoding: UTF8String}}
+func TestParseOpenSSLOnelineDN(t *testing.T) {
+	c := RDN{AttributeTypeAndValue{Type: CountryName, Value: AttributeValue{PrintableString, "JP"}}}
+	o := RDN{AttributeTypeAndValue{Type: OrganizationName, Value: AttributeValue{UTF8String, "Example, Inc."}}}
+	cn := RDN{AttributeTypeAndValue{Type: CommonName, Value: AttributeValue{UTF8String, "Mike"}}}
 	type args struct {
-		index int
-		r     RDN
+		s string
 	}
 	tests := []struct {
-		name     string
-		args     args
-		wantRest RDN
+		name    string
+		args    args
+		wantDn  DN
+		wantErr bool
 	}{
-		{"TestCase: 1 Attributes index 0", args{0, RDN{atv1}}, RDN{}},
-		{"TestCase: 2 Attributes index 0", args{0, RDN{atv1, atv2}}, RDN{atv2}},
-		{"TestCase: 2 Attributes index 1", args{1, RDN{atv1, atv2}}, RDN{atv1}},
-		{"TestCase: 3 Attributes index 0", args{0, RDN{atv1, atv2, atv3}}, RDN{atv2, atv3}},
-		{"TestCase: 3 Attributes index 1", args{1, RDN{atv1, atv2, atv3}}, RDN{atv1, atv3}},
-		{"TestCase: 3 Attributes index 2", args{2, RDN{atv1, atv2, atv3}}, RDN{atv1, atv2}},
+		{"TestCase: empty", args{""}, DN{}, false},
+		{"TestCase: DN order", args{"/C=JP/O=Example, Inc./CN=Mike"}, DN{c, o, cn}, false},
+		{"TestCase: lower case descriptors", args{"/c=JP/o=Example, Inc./cn=Mike"}, DN{c, o, cn}, false},
+		{"TestCase: multi value RDN", args{"/C=JP/CN=Mike+emailAddress=mike@example.com"}, DN{c, RDN{
+			AttributeTypeAndValue{Type: CommonName, Value: AttributeValue{UTF8String, "Mike"}},
+			AttributeTypeAndValue{Type: ElectronicMailAddress, Value: AttributeValue{IA5String, "mike@example.com"}},
+		}}, false},
+		{"TestCase: escaped slash", args{`/CN=a\/b`}, DN{RDN{AttributeTypeAndValue{Type: CommonName, Value: AttributeValue{UTF8String, "a/b"}}}}, false},
+		{"TestCase: literal special characters of RFC 4514", args{`/CN=a;b<c>"d"#e`}, DN{RDN{AttributeTypeAndValue{Type: CommonName, Value: AttributeValue{UTF8String, `a;b<c>"d"#e`}}}}, false},
+		{"TestCase: unknown oid", args{"/1.2.3.4=x"}, DN{RDN{AttributeTypeAndValue{Type: Generic, Oid: "1.2.3.4", Value: AttributeValue{UTF8String, "x"}}}}, false},
+		{"TestCase: missing leading slash", args{"C=JP/CN=Mike"}, nil, true},
+		{"TestCase: RFC 4514 format", args{"CN=Mike,C=JP"}, nil, true},
+		{"TestCase: trailing slash", args{"/C=JP/"}, nil, true},
+		{"TestCase: unterminated escape", args{`/CN=a\`}, nil, true},
+		{"TestCase: unknown descriptor", args{"/XX=Mike"}, nil, true},
+		{"TestCase: missing =", args{"/CN Mike"}, nil, true},
+		{"TestCase: CountryName of 3 characters", args{"/C=JPN"}, DN{RDN{AttributeTypeAndValue{Type: CountryName, Value: AttributeValue{PrintableString, "JPN"}}}}, false},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if gotRest := removeAttributeTypeAndValue(tt.args.index, tt.args.r); !reflect.DeepEqual(gotRest, tt.wantRest) {
-				t.Errorf("removeAttributeTypeAndValue() = %v, want %v", gotRest, tt.wantRest)
+			gotDn, err := ParseOpenSSLOnelineDN(tt.args.s)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseOpenSSLOnelineDN() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(gotDn, tt.wantDn) {
+				t.Errorf("ParseOpenSSLOnelineDN() gotDn = %v, want %v", gotDn, tt.wantDn)
 			}
 		})
 	}
 }
 
-func Test_isMatchedRDN(t *testing.T) {
-	atv1 := AttributeTypeAndValue{Type: CountryName, Value: AttributeValue{Encoding: PrintableString}}
-	atv2 := AttributeTypeAndValue{Type: CommonName, Value: AttributeValue{Encoding: UTF8String}}
-	atv3 := AttributeTypeAndValue{Type: OrganizationName, Value: AttributeValue{Encoding: UTF8String}}
-	atv4 := AttributeTypeAndValue{Type: Generic, Oid: "1.2.3", Value: AttributeValue{Encoding: UTF8String}}
+func TestParseLDIFDN(t *testing.T) {
+	dc1 := RDN{AttributeTypeAndValue{Type: DomainComponent, Value: AttributeValue{IA5String, "com"}}}
+	dc2 := RDN{AttributeTypeAndValue{Type: DomainComponent, Value: AttributeValue{IA5String, "example"}}}
+	cn1 := RDN{AttributeTypeAndValue{Type: CommonName, Value: AttributeValue{UTF8String, "admin"}}}
+	cn2 := RDN{AttributeTypeAndValue{Type: CommonName, Value: AttributeValue{UTF8String, "やまだ"}}}
 	type args struct {
-		r   RDN
-		ats []AttributeType
+		line string
 	}
 	tests := []struct {
-		name          string
-		args          args
-		wantIsMatched bool
+		name    string
+		args    args
+		wantDn  DN
+		wantErr bool
 	}{
-		{"TestCase: 1 Attributes matched", args{RDN{atv1}, []AttributeType{CountryName}}, true},
-		{"TestCase: 1 Attributes matched (Generic)", args{RDN{atv4}, []AttributeType{Generic}}, true},
-		{"TestCase: 2 Attributes matched", args{RDN{atv1, atv2}, []AttributeType{CountryName, CommonName}}, true},
-		{"TestCase: 2 Attributes matched (Generic)", args{RDN{atv4, atv2}, []AttributeType{Generic, CommonName}}, true},
-		{"TestCase: 2 Attributes(Revers order) matched", args{RDN{atv1, atv2}, []AttributeType{CommonName, CountryName}}, true},
-		{"TestCase: 2 Attributes(Generic, Revers order) matched", args{RDN{atv4, atv2}, []AttributeType{CommonName, Generic}}, true},
-		{"TestCase: 3 Attributes not matched", args{RDN{atv1, atv2, atv3}, []AttributeType{CommonName, CountryName, CommonName}}, false},
+		{"TestCase: plain", args{"dn: cn=admin,dc=example,dc=com"}, DN{dc1, dc2, cn1}, false},
+		{"TestCase: plain without space", args{"dn:cn=admin,dc=example,dc=com"}, DN{dc1, dc2, cn1}, false},
+		{"TestCase: plain with line separator", args{"dn: cn=admin,dc=example,dc=com\r\n"}, DN{dc1, dc2, cn1}, false},
+		{"TestCase: base64", args{"dn:: Y24944KE44G+44GgLGRjPWV4YW1wbGUsZGM9Y29t"}, DN{dc1, dc2, cn2}, false},
+		{"TestCase: empty DN", args{"dn: "}, DN{}, false},
+		{"TestCase: not dn line", args{"cn: admin"}, nil, true},
+		{"TestCase: blank", args{""}, nil, true},
+		{"TestCase: invalid base64", args{"dn:: !!!"}, nil, true},
+		{"TestCase: invalid DN", args{"dn: cn"}, nil, true},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if gotIsMatched := isMatchedRDN(tt.args.r, tt.args.ats); gotIsMatched != tt.wantIsMatched {
-				t.Errorf("isMatchedRDN() = %v, want %v", gotIsMatched, tt.wantIsMatched)
+			gotDn, err := ParseLDIFDN(tt.args.line)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseLDIFDN() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(gotDn, tt.wantDn) {
+				t.Errorf("ParseLDIFDN() gotDn = %v, want %v", gotDn, tt.wantDn)
+			}
+			if err == nil {
+				if got, err := ParseLDIFDN(gotDn.ToLDIFDN()); err != nil || !reflect.DeepEqual(got, gotDn) {
+					t.Errorf("ParseLDIFDN(ToLDIFDN()) = %v, %v, want %v", got, err, gotDn)
+				}
 			}
 		})
 	}
 }
 
-func Test_isMatchedRDNByOids(t *testing.T) {
-	atv1 := AttributeTypeAndValue{Type: CountryName, Value: AttributeValue{Encoding: PrintableString}}
-	atv2 := AttributeTypeAndValue{Type: CommonName, Value: AttributeValue{Encoding: UTF8String}}
-	atv3 := AttributeTypeAndValue{Type: OrganizationName, Value: AttributeValue{Encoding: UTF8String}}
-	atv4 := AttributeTypeAndValue{Type: Generic, Oid: "1.2.3", Value: AttributeValue{Encoding: UTF8String}}
+func Test_defaultEncoding(t *testing.T) {
 	type args struct {
-		r    RDN
-		oids []string
+		at AttributeType
 	}
 	tests := []struct {
-		name          string
-		args          args
-		wantIsMatched bool
+		name string
+		args args
+		want Encoding
 	}{
-		{"TestCase: 1 Attributes matched", args{RDN{atv1}, []string{"2.5.4.6"}}, true},
-		{"TestCase: 1 Attributes matched (Generic)", args{RDN{atv4}, []string{"1.2.3"}}, true},
-		{"TestCase: 2 Attributes matched", args{RDN{atv1, atv2}, []string{"2.5.4.6", "2.5.4.3"}}, true},
-		{"TestCase: 2 Attributes matched (Generic)", args{RDN{atv4, atv2}, []string{"1.2.3", "2.5.4.3"}}, true},
-		{"TestCase: 2 Attributes(Revers order) matched", args{RDN{atv1, atv2}, []string{"2.5.4.3", "2.5.4.6"}}, true},
-		{"TestCase: 2 Attributes(Generic, Revers order) matched", args{RDN{atv4, atv2}, []string{"2.5.4.3", "1.2.3"}}, true},
-		{"TestCase: 3 Attributes not matched", args{RDN{atv1, atv2, atv3}, []string{"2.5.4.3", "2.5.4.6", "2.5.4.3"}}, false},
+		{"TestCase: CountryName", args{CountryName}, PrintableString},
+		{"TestCase: DnQualifier", args{DnQualifier}, PrintableString},
+		{"TestCase: SerialNumber", args{SerialNumber}, PrintableString},
+		{"TestCase: ElectronicMailAddress", args{ElectronicMailAddress}, IA5String},
+		{"TestCase: DomainComponent", args{DomainComponent}, IA5String},
+		{"TestCase: CommonName", args{CommonName}, UTF8String},
+		{"TestCase: Generic", args{Generic}, UTF8String},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if gotIsMatched := isMatchedRDNByOids(tt.args.r, tt.args.oids); gotIsMatched != tt.wantIsMatched {
-				t.Errorf("isMatchedRDNByOids() = %v, want %v", gotIsMatched, tt.wantIsMatched)
+			if got := defaultEncoding(tt.args.at); got != tt.want {
+				t.Errorf("defaultEncoding() = %v, want %v", got, tt.want)
 			}
 		})
 	}
 }
 
-func Test_findMatchedAttributeTypeIndex(t *testing.T) {
-	atv1 := AttributeTypeAndValue{Type: CountryName, Value: AttributeValue{Encoding: PrintableString}}
-	atv2 := AttributeTypeAndValue{Type: CommonName, Value: AttributeValue{Encoding: UTF8String}}
-	atv3 := AttributeTypeAndValue{Type: OrganizationName, Value: AttributeValue{Encoding: UTF8String}}
-	atv4 := AttributeTypeAndValue{Type: Generic, Oid: "1.2.3", Value: AttributeValue{Encoding: UTF8String}}
+func Test_parseDNString(t *testing.T) {
+	c := RDN{AttributeTypeAndValue{Type: CountryName, Value: AttributeValue{PrintableString, "JP"}}}
+	o := RDN{AttributeTypeAndValue{Type: OrganizationName, Value: AttributeValue{UTF8String, "Example"}}}
+	cn := RDN{AttributeTypeAndValue{Type: CommonName, Value: AttributeValue{UTF8String, "Mike"}}}
 	type args struct {
-		r  RDN
-		at AttributeType
+		s      string
+		syntax dnStringSyntax
 	}
 	tests := []struct {
-		name      string
-		args      args
-		wantIndex int
+		name    string
+		args    args
+		want    DN
+		wantErr bool
 	}{
-		{"TestCase: 0 Attributes  not matched", args{RDN{}, CountryName}, -1},
-		{"TestCase: 3 Attributes  matched index 0", args{RDN{atv1, atv2, atv3}, CountryName}, 0},
-		{"TestCase: 3 Attributes  matched index 1", args{RDN{atv1, atv2, atv3}, CommonName}, 1},
-		{"TestCase: 3 Attributes  matched index 2", args{RDN{atv1, atv2, atv3}, OrganizationName}, 2},
-		{"TestCase: 3 Attributes  not matched", args{RDN{atv1, atv2, atv3}, OrganizationalUnit}, -1},
-		{"TestCase: 4 Attributes  matched index 1", args{RDN{atv1, atv4, atv2, atv3}, Generic}, 1},
-		{"TestCase: 3 Attributes  not matched", args{RDN{atv1, atv4, atv2, atv3}, OrganizationalUnit}, -1},
+		{"TestCase: RFC4514 empty", args{"", rfc4514Syntax}, DN{}, false},
+		{"TestCase: RFC4514", args{"CN=Mike,O=Example,C=JP", rfc4514Syntax}, DN{c, o, cn}, false},
+		{"TestCase: RFC4514 long descriptors", args{"commonName=Mike,organizationName=Example,countryName=JP", rfc4514Syntax}, DN{c, o, cn}, false},
+		{"TestCase: RFC4514 numericoid", args{"2.5.4.3=Mike,1.2.3.4=x", rfc4514Syntax}, DN{
+			RDN{AttributeTypeAndValue{Type: Generic, Oid: "1.2.3.4", Value: AttributeValue{UTF8String, "x"}}}, cn}, false},
+		{"TestCase: RFC4514 escaped special characters", args{`CN=\ a\,b\+c\"\\\<\>\;\#\=\ `, rfc4514Syntax}, DN{
+			RDN{AttributeTypeAndValue{Type: CommonName, Value: AttributeValue{UTF8String, ` a,b+c"\<>;#= `}}}}, false},
+		{"TestCase: RFC4514 hex escaped UTF-8", args{`CN=\e3\81\82`, rfc4514Syntax}, DN{
+			RDN{AttributeTypeAndValue{Type: CommonName, Value: AttributeValue{UTF8String, "あ"}}}}, false},
+		{"TestCase: RFC4514 hex value", args{"CN=#13044d696b65", rfc4514Syntax}, DN{
+			RDN{AttributeTypeAndValue{Type: CommonName, Value: AttributeValue{PrintableString, "Mike"}}}}, false},
+		{"TestCase: RFC4514 multi value RDN", args{"CN=Mike+CN=Bob", rfc4514Syntax}, DN{RDN{
+			AttributeTypeAndValue{Type: CommonName, Value: AttributeValue{UTF8String, "Mike"}},
+			AttributeTypeAndValue{Type: CommonName, Value: AttributeValue{UTF8String, "Bob"}},
+		}}, false},
+		{"TestCase: RFC4514 spaces are not ignored", args{"CN = Mike", rfc4514Syntax}, nil, true},
+		{"TestCase: RFC4514 semicolon is not a separator", args{"CN=Mike;C=JP", rfc4514Syntax}, nil, true},
+		{"TestCase: RFC4514 quoted value is not allowed", args{`CN="Mike"`, rfc4514Syntax}, nil, true},
+		{"TestCase: RFC4514 OID. prefix is not allowed", args{"OID.2.5.4.3=Mike", rfc4514Syntax}, nil, true},
+		{"TestCase: RFC4514 invalid hex escape", args{`CN=\e`, rfc4514Syntax}, nil, true},
+		{"TestCase: RFC4514 invalid UTF-8", args{`CN=\e3\81`, rfc4514Syntax}, nil, true},
+		{"TestCase: RFC4514 unterminated escape", args{`CN=Mike\`, rfc4514Syntax}, nil, true},
+		{"TestCase: RFC4514 not escapable character", args{`CN=\M`, rfc4514Syntax}, nil, true},
+		{"TestCase: RFC4514 invalid hex value", args{"CN=#1304", rfc4514Syntax}, nil, true},
+		{"TestCase: RFC4514 odd hex value", args{"CN=#130", rfc4514Syntax}, nil, true},
+		{"TestCase: RFC4514 trailing data in hex value", args{"CN=#13014d00", rfc4514Syntax}, nil, true},
+		{"TestCase: RFC4514 empty AttributeType", args{"=Mike", rfc4514Syntax}, nil, true},
+		{"TestCase: RFC4514 trailing separator", args{"CN=Mike,", rfc4514Syntax}, nil, true},
+		{"TestCase: RFC4514 invalid oid", args{"1..2=Mike", rfc4514Syntax}, nil, true},
+		{"TestCase: RFC1779 trailing spaces are removed", args{"CN=Mike  , C=JP  ", rfc1779Syntax}, DN{c, cn}, false},
+		{"TestCase: RFC1779 escaped trailing space is kept", args{`CN=Mike\ `, rfc1779Syntax}, DN{
+			RDN{AttributeTypeAndValue{Type: CommonName, Value: AttributeValue{UTF8String, "Mike "}}}}, false},
+		{"TestCase: RFC1779 hex escape is not allowed", args{`CN=\4d`, rfc1779Syntax}, nil, true},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if gotIndex := findMatchedAttributeTypeIndex(tt.args.r, tt.args.at); gotIndex != tt.wantIndex {
-				t.Errorf("findMatchedAttributeTypeIndex() = %v, want %v", gotIndex, tt.wantIndex)
+			got, err := parseDNString(tt.args.s, tt.args.syntax)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("parseDNString() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseDNString() got = %v, want %v", got, tt.want)
 			}
 		})
 	}
 }
 
-func Test_findMatchedOidIndex(t *testing.T) {
-	atv1 := AttributeTypeAndValue{Type: CountryName, Value: AttributeValue{Encoding: PrintableString}}
-	atv2 := AttributeTypeAndValue{Type: CommonName, Value: AttributeValue{Encoding: UTF8String}}
-	atv3 := AttributeTypeAndValue{Type: OrganizationName, Value: AttributeValue{Encoding: UTF8String}}
-	atv4 := AttributeTypeAndValue{Type: Generic, Oid: "1.2.3", Value: AttributeValue{Encoding: UTF8String}}
+func Test_parseDNStringWithOptions(t *testing.T) {
+	c := RDN{AttributeTypeAndValue{Type: CountryName, Value: AttributeValue{PrintableString, "JP"}}}
+	cn := RDN{AttributeTypeAndValue{Type: CommonName, Value: AttributeValue{UTF8String, "Mike"}}}
 	type args struct {
-		r   RDN
-		oid string
+		s      string
+		syntax dnStringSyntax
+		opts   dnStringParseOptions
 	}
 	tests := []struct {
-		name      string
-		args      args
-		wantIndex int
+		name    string
+		args    args
+		want    DN
+		wantErr bool
 	}{
-		{"TestCase: 0 Attributes  not matched", args{RDN{}, "2.5.4.6"}, -1},
-		{"TestCase: 3 Attributes  matched index 0", args{RDN{atv1, atv2, atv3}, "2.5.4.6"}, 0},
-		{"TestCase: 3 Attributes  matched index 1", args{RDN{atv1, atv2, atv3}, "2.5.4.3"}, 1},
-		{"TestCase: 3 Attributes  matched index 2", args{RDN{atv1, atv2, atv3}, "2.5.4.10"}, 2},
-		{"TestCase: 3 Attributes  not matched", args{RDN{atv1, atv2, atv3}, "2.5.4.11"}, -1},
-		{"TestCase: 4 Attributes  matched index 1", args{RDN{atv1, atv4, atv2, atv3}, "1.2.3"}, 1},
-		{"TestCase: 3 Attributes  not matched", args{RDN{atv1, atv4, atv2, atv3}, "2.5.4.11"}, -1},
+		{"TestCase: RFC4514 semicolon separator off", args{"CN=Mike;C=JP", rfc4514Syntax, dnStringParseOptions{}}, nil, true},
+		{"TestCase: RFC4514 semicolon separator on", args{"CN=Mike;C=JP", rfc4514Syntax, dnStringParseOptions{semicolonSeparator: true}}, DN{c, cn}, false},
+		{"TestCase: RFC4514 semicolon separator on with comma", args{"CN=Mike,C=JP", rfc4514Syntax, dnStringParseOptions{semicolonSeparator: true}}, DN{c, cn}, false},
+		{"TestCase: RFC4514 semicolon separator on with escaped semicolon", args{`CN=Mi\;ke;C=JP`, rfc4514Syntax, dnStringParseOptions{semicolonSeparator: true}}, DN{c,
+			RDN{AttributeTypeAndValue{Type: CommonName, Value: AttributeValue{UTF8String, "Mi;ke"}}}}, false},
+		{"TestCase: RFC1779 semicolon separator off", args{"CN=Mike; C=JP", rfc1779Syntax, dnStringParseOptions{}}, nil, true},
+		{"TestCase: RFC1779 semicolon separator on", args{"CN=Mike; C=JP", rfc1779Syntax, dnStringParseOptions{semicolonSeparator: true}}, DN{c, cn}, false},
+		{"TestCase: RFC4514 line continuation off", args{"CN=Mike,\\\nC=JP", rfc4514Syntax, dnStringParseOptions{}}, nil, true},
+		{"TestCase: RFC4514 line continuation on", args{"CN=Mike,\\\nC=JP", rfc4514Syntax, dnStringParseOptions{lineContinuation: true}}, DN{c, cn}, false},
+		{"TestCase: RFC4514 line continuation on in value", args{"CN=Mi\\\r\nke,C=JP", rfc4514Syntax, dnStringParseOptions{lineContinuation: true}}, DN{c, cn}, false},
+		{"TestCase: RFC4514 trailing separator off", args{"CN=Mike,C=JP,", rfc4514Syntax, dnStringParseOptions{}}, nil, true},
+		{"TestCase: RFC4514 trailing separator on", args{"CN=Mike,C=JP,", rfc4514Syntax, dnStringParseOptions{trailingSeparator: true}}, DN{c, cn}, false},
+		{"TestCase: RFC4514 trailing separator on without trailing separator", args{"CN=Mike,C=JP", rfc4514Syntax, dnStringParseOptions{trailingSeparator: true}}, DN{c, cn}, false},
+		{"TestCase: RFC4514 trailing separator on with 2 trailing separators", args{"CN=Mike,C=JP,,", rfc4514Syntax, dnStringParseOptions{trailingSeparator: true}}, nil, true},
+		{"TestCase: RFC4514 trailing separator on with separator only", args{",", rfc4514Syntax, dnStringParseOptions{trailingSeparator: true}}, nil, true},
+		{"TestCase: RFC4514 trailing separator on with escaped comma", args{`CN=Mike\,`, rfc4514Syntax, dnStringParseOptions{trailingSeparator: true}}, DN{
+			RDN{AttributeTypeAndValue{Type: CommonName, Value: AttributeValue{UTF8String, "Mike,"}}}}, false},
+		{"TestCase: RFC1779 trailing separator on", args{"CN=Mike; C=JP; ", rfc1779Syntax, dnStringParseOptions{semicolonSeparator: true, trailingSeparator: true}}, DN{c, cn}, false},
+		{"TestCase: RFC1779 line continuation on", args{"CN=Mike;\\\n C=JP", rfc1779Syntax, dnStringParseOptions{semicolonSeparator: true, lineContinuation: true}}, DN{c, cn}, false},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if gotIndex := findMatchedOidIndex(tt.args.r, tt.args.oid); gotIndex != tt.wantIndex {
-				t.Errorf("findMatchedOidIndex() = %v, want %v", gotIndex, tt.wantIndex)
+			got, err := parseDNStringWithOptions(tt.args.s, tt.args.syntax, tt.args.opts)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("parseDNStringWithOptions() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseDNStringWithOptions() got = %v, want %v", got, tt.want)
 			}
 		})
 	}
 }
 
-func Test_needEscaping(t *testing.T) {
-	type args struct {
-		r rune
+func TestParseRFC4514DNWithOptions_TrailingSeparator(t *testing.T) {
+	o := RFC4514ParseOptions{TrailingSeparator: true}
+	want, err := ParseRFC4514DNWithOptions("CN=foo", o)
+	if err != nil {
+		t.Fatalf("ParseRFC4514DNWithOptions() error = %v", err)
+	}
+	got, err := ParseRFC4514DNWithOptions("CN=foo,", o)
+	if err != nil {
+		t.Fatalf("ParseRFC4514DNWithOptions() error = %v", err)
 	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseRFC4514DNWithOptions() got = %v, want %v", got, want)
+	}
+}
+
+func Test_joinContinuedLines(t *testing.T) {
 	tests := []struct {
 		name string
-		args args
-		want bool
+		s    string
+		want string
 	}{
-		{"TestCase: U+0022", args{rune('\U00000022')}, true},
-		{"TestCase: U+002B", args{rune('\U0000002B')}, true},
-		{"TestCase: U+002C", args{rune('\U0000002C')}, true},
-		{"TestCase: U+003B", args{rune('\U0000003B')}, true},
-		{"TestCase: U+003C", args{rune('\U0000003C')}, true},
-		{"TestCase: U+003E", args{rune('\U0000003E')}, true},
-		{"TestCase: U+005C", args{rune('\U0000005C')}, true},
-		{"TestCase: U+0000", args{rune('\U00000000')}, true},
-		{"TestCase: A ", args{rune('\U00000041')}, false},
+		{"TestCase: no continuation", "CN=Mike,C=JP", "CN=Mike,C=JP"},
+		{"TestCase: LF", "CN=Mike,\\\nO=Example,\\\nC=JP", "CN=Mike,O=Example,C=JP"},
+		{"TestCase: CRLF", "CN=Mike,\\\r\nC=JP", "CN=Mike,C=JP"},
+		{"TestCase: escaped characters are kept", `CN=Mi\,ke\\,C=JP`, `CN=Mi\,ke\\,C=JP`},
+		{"TestCase: escaped backslash before newline", "CN=Mike\\\\\n", "CN=Mike\\\\\n"},
+		{"TestCase: backslash before CR only", "CN=Mike\\\r", "CN=Mike\\\r"},
+		{"TestCase: trailing backslash", `CN=Mike\`, `CN=Mike\`},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if got := needEscaping(tt.args.r); got != tt.want {
-				t.Errorf("needEscaping() = %v, want %v", got, tt.want)
+			if got := joinContinuedLines(tt.s); got != tt.want {
+				t.Errorf("joinContinuedLines() = %q, want %q", got, tt.want)
 			}
 		})
 	}
 }
 
-func Test_escapeAttributeValue(t *testing.T) {
-	type args struct {
-		s string
-	}
+func Test_defaultDNStringParseOptions(t *testing.T) {
 	tests := []struct {
-		name string
-		args args
-		want string
+		name   string
+		syntax dnStringSyntax
+		want   dnStringParseOptions
 	}{
-		{"TestCase: AAA", args{"AAA"}, "AAA"},
-		{"TestCase:  AAA", args{" AAA"}, "\\ AAA"},
-		{"TestCase: #AAA", args{"#AAA"}, "\\#AAA"},
-		{"TestCase:  AAA#", args{" AAA"}, "\\ AAA"},
-		{"TestCase: #AAA ", args{"#AAA "}, "\\#AAA\\ "},
-		{"TestCase:  AAA ", args{" AAA "}, "\\ AAA\\ "},
-		{"TestCase:  A A A ", args{" A A A "}, "\\ A A A\\ "},
-		{"TestCase: あ い う", args{" あ い う "}, "\\ あ い う\\ "},
-		{"TestCase: あ(U+0022)い+う,え;お ", args{" あ\"い+う,え;お "}, "\\ あ\\\"い\\+う\\,え\\;お\\ "},
-		{"TestCase: あ(U+003C)い(U+003E)う(U+005C)え ", args{" あ<い>う\\え "}, "\\ あ\\<い\\>う\\\\え\\ "},
-		{"TestCase: James (U+0022)Jim(U+0022), III", args{"James \"Jim\" Smith, III"}, "James \\\"Jim\\\" Smith\\, III"},
+		{"TestCase: RFC4514", rfc4514Syntax, dnStringParseOptions{}},
+		{"TestCase: RFC1779", rfc1779Syntax, dnStringParseOptions{semicolonSeparator: true}},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if got := escapeAttributeValue(tt.args.s); got != tt.want {
-				t.Errorf("escapeAttributeValue() = %v, want %v", got, tt.want)
+			if got := defaultDNStringParseOptions(tt.syntax); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("defaultDNStringParseOptions() = %v, want %v", got, tt.want)
 			}
 		})
 	}
 }
 
-func TestAttributeValue_ToRFC4514FormatString(t *testing.T) {
-	type fields struct {
-		Encoding Encoding
-		Value    string
-	}
+func Test_parseDNString_RoundTrip(t *testing.T) {
 	tests := []struct {
-		name   string
-		fields fields
-		want   string
+		name string
+		d    DN
 	}{
-		{"TestCase: AAA", fields{2, "AAA"}, "AAA"},
-		{"TestCase:  AAA", fields{2, " AAA"}, "\\ AAA"},
-		{"TestCase: #AAA", fields{2, "#AAA"}, "\\#AAA"},
-		{"TestCase:  AAA#", fields{2, " AAA"}, "\\ AAA"},
-		{"TestCase: #AAA ", fields{2, "#AAA "}, "\\#AAA\\ "},
-		{"TestCase:  AAA ", fields{2, " AAA "}, "\\ AAA\\ "},
-		{"TestCase:  A A A ", fields{2, " A A A "}, "\\ A A A\\ "},
-		{"TestCase: あ い う", fields{2, " あ い う "}, "\\ あ い う\\ "},
-		{"TestCase: あ(U+0022)い+う,え;お ", fields{2, " あ\"い+う,え;お "}, "\\ あ\\\"い\\+う\\,え\\;お\\ "},
-		{"TestCase: あ(U+003C)い(U+003E)う(U+005C)え ", fields{2, " あ<い>う\\え "}, "\\ あ\\<い\\>う\\\\え\\ "},
-		{"TestCase: James (U+0022)Jim(U+0022) Smith, III", fields{2, "James \"Jim\" Smith, III"}, "James \\\"Jim\\\" Smith\\, III"},
+		{"TestCase: simple", DN{
+			RDN{AttributeTypeAndValue{Type: CountryName, Value: AttributeValue{PrintableString, "JP"}}},
+			RDN{AttributeTypeAndValue{Type: CommonName, Value: AttributeValue{UTF8String, "Mike"}}},
+		}},
+		{"TestCase: special characters", DN{
+			RDN{AttributeTypeAndValue{Type: OrganizationName, Value: AttributeValue{UTF8String, ` #a,b+c"d\e<f>g;h=i# `}}},
+		}},
+		{"TestCase: 1 space", DN{
+			RDN{AttributeTypeAndValue{Type: CommonName, Value: AttributeValue{UTF8String, " "}}},
+		}},
+		{"TestCase: 3 spaces", DN{
+			RDN{AttributeTypeAndValue{Type: CommonName, Value: AttributeValue{UTF8String, "   "}}},
+			RDN{AttributeTypeAndValue{Type: OrganizationalUnit, Value: AttributeValue{UTF8String, "   "}}},
+		}},
+		{"TestCase: multi value RDN and Generic", DN{
+			RDN{
+				AttributeTypeAndValue{Type: CommonName, Value: AttributeValue{UTF8String, "Mike"}},
+				AttributeTypeAndValue{Type: Generic, Oid: "1.2.3.4", Value: AttributeValue{UTF8String, "x"}},
+			},
+		}},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			av := AttributeValue{
-				Encoding: tt.fields.Encoding,
-				Value:    tt.fields.Value,
+			s := tt.d.ToRFC4514FormatString()
+			got, err := parseDNString(s, rfc4514Syntax)
+			if err != nil {
+				t.Fatalf("parseDNString(%q) error = %v", s, err)
 			}
-			if got := av.ToRFC4514FormatString(); got != tt.want {
-				t.Errorf("ToRFC4514FormatString() = %v, want %v", got, tt.want)
+			if !reflect.DeepEqual(got, tt.d) {
+				t.Errorf("parseDNString(%q) = %v, want %v", s, got, tt.d)
 			}
 		})
 	}
 }
 
-func TestAttributeValue_String(t *testing.T) {
-	type fields struct {
-		Encoding Encoding
-		Value    string
+func TestToProtoStruct(t *testing.T) {
+	dn := DN{
+		RDN{AttributeTypeAndValue{Type: CountryName, Value: AttributeValue{PrintableString, "JP"}}},
+		RDN{
+			AttributeTypeAndValue{Type: CommonName, Value: AttributeValue{UTF8String, "Mike"}},
+			AttributeTypeAndValue{Type: Generic, Oid: "1.2.3.4", Value: AttributeValue{OIDValue, "1.2.3.5"}},
+		},
 	}
+	want := DNProto{RDNs: []RDNProto{
+		{Attributes: []AttributeTypeAndValueProto{{"2.5.4.6", "PrintableString", "JP"}}},
+		{Attributes: []AttributeTypeAndValueProto{{"2.5.4.3", "UTF8String", "Mike"}, {"1.2.3.4", "OIDValue", "1.2.3.5"}}},
+	}}
 	tests := []struct {
-		name   string
-		fields fields
-		want   string
+		name string
+		dn   DN
+		want DNProto
 	}{
-		{"TestCase: AAA", fields{2, "AAA"}, "AAA"},
-		{"TestCase:  AAA", fields{2, " AAA"}, " AAA"},
-		{"TestCase: #AAA", fields{2, "#AAA"}, "#AAA"},
-		{"TestCase:  AAA#", fields{2, " AAA#"}, " AAA#"},
-		{"TestCase: #AAA ", fields{2, "#AAA "}, "#AAA "},
-		{"TestCase:  AAA ", fields{2, " AAA "}, " AAA "},
-		{"TestCase:  A A A ", fields{2, " A A A "}, " A A A "},
-		{"TestCase: あ い う", fields{2, " あ い う "}, " あ い う "},
-		{"TestCase: あ(U+0022)い+う,え;お ", fields{2, " あ\"い+う,え;お "}, " あ\"い+う,え;お "},
-		{"TestCase: あ(U+003C)い(U+003E)う(U+005C)え ", fields{2, " あ<い>う\\え "}, " あ<い>う\\え "},
-		{"TestCase: James (U+0022)Jim(U+0022) Smith, III", fields{2, "James \"Jim\" Smith, III"}, "James \"Jim\" Smith, III"},
+		{"TestCase: 0 RDN", DN{}, DNProto{RDNs: []RDNProto{}}},
+		{"TestCase: multi value RDN and Generic", dn, want},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			av := AttributeValue{
-				Encoding: tt.fields.Encoding,
-				Value:    tt.fields.Value,
+			got := ToProtoStruct(tt.dn)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ToProtoStruct() = %v, want %v", got, tt.want)
 			}
-			if got := av.String(); got != tt.want {
-				t.Errorf("String() = %v, want %v", got, tt.want)
+			back, err := FromProtoStruct(got)
+			if err != nil || !reflect.DeepEqual(back, tt.dn) {
+				t.Errorf("FromProtoStruct(ToProtoStruct()) = %v, %v, want %v", back, err, tt.dn)
 			}
 		})
 	}
 }
 
-func TestAttributeTypeAndValue_ToRFC4514FormatString(t *testing.T) {
-	type fields struct {
-		Type  AttributeType
-		Value AttributeValue
-		Oid   string
+func TestFromProtoStruct(t *testing.T) {
+	type args struct {
+		p DNProto
 	}
 	tests := []struct {
-		name   string
-		fields fields
-		want   string
+		name    string
+		args    args
+		wantDn  DN
+		wantErr bool
 	}{
-		{"TestCase: OrganizationName AAA", fields{Type: OrganizationName, Value: AttributeValue{UTF8String, "AAA"}}, "O=AAA"},
-		{"TestCase: Generic OID=1.2.3.4 AAA", fields{Type: Generic, Oid: "1.2.3.4", Value: AttributeValue{UTF8String, "AAA"}}, "1.2.3.4=AAA"},
-		{"TestCase: Generic OID=2.5.4.10(OrganizationName) AAA", fields{Type: Generic, Oid: "2.5.4.10", Value: AttributeValue{UTF8String, "AAA"}}, "O=AAA"},
-		{"TestCase: DnQualifier AAA", fields{Type: DnQualifier, Value: AttributeValue{UTF8String, "AAA"}}, "DNQUALIFIER=AAA"},
-		{"TestCase: LocalityName  AAA", fields{Type: LocalityName, Value: AttributeValue{UTF8String, " AAA"}}, "L=\\ AAA"},
-		{"TestCase: CommonName James (U+0022)Jim(U+0022) Smith, III", fields{Type: CommonName, Value: AttributeValue{UTF8String, "James \"Jim\" Smith, III"}}, "CN=James \\\"Jim\\\" Smith\\, III"},
+		{"TestCase: empty", args{DNProto{}}, DN{}, false},
+		{"TestCase: known oid", args{DNProto{RDNs: []RDNProto{{Attributes: []AttributeTypeAndValueProto{{"2.5.4.3", "UTF8String", "Mike"}}}}}},
+			DN{RDN{AttributeTypeAndValue{Type: CommonName, Value: AttributeValue{UTF8String, "Mike"}}}}, false},
+		{"TestCase: unknown oid", args{DNProto{RDNs: []RDNProto{{Attributes: []AttributeTypeAndValueProto{{"1.2.3.4", "IA5String", "x"}}}}}},
+			DN{RDN{AttributeTypeAndValue{Type: Generic, Oid: "1.2.3.4", Value: AttributeValue{IA5String, "x"}}}}, false},
+		{"TestCase: invalid oid", args{DNProto{RDNs: []RDNProto{{Attributes: []AttributeTypeAndValueProto{{"1.a", "UTF8String", "x"}}}}}}, nil, true},
+		{"TestCase: invalid encoding name", args{DNProto{RDNs: []RDNProto{{Attributes: []AttributeTypeAndValueProto{{"2.5.4.3", "utf8", "x"}}}}}}, nil, true},
+		{"TestCase: invalid combination", args{DNProto{RDNs: []RDNProto{{Attributes: []AttributeTypeAndValueProto{{"2.5.4.6", "UTF8String", "JP"}}}}}}, nil, true},
+		{"TestCase: empty RDN", args{DNProto{RDNs: []RDNProto{{}}}}, nil, true},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			atv := AttributeTypeAndValue{
-				Type:  tt.fields.Type,
-				Value: tt.fields.Value,
-				Oid:   tt.fields.Oid,
+			gotDn, err := FromProtoStruct(tt.args.p)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("FromProtoStruct() error = %v, wantErr %v", err, tt.wantErr)
+				return
 			}
-			if got := atv.ToRFC4514FormatString(); got != tt.want {
-				t.Errorf("ToRFC4514FormatString() = %v, want %v", got, tt.want)
+			if !reflect.DeepEqual(gotDn, tt.wantDn) {
+				t.Errorf("FromProtoStruct() gotDn = %v, want %v", gotDn, tt.wantDn)
 			}
 		})
 	}
 }
 
-func TestAttributeTypeAndValue_String(t *testing.T) {
-	type fields struct {
-		Type  AttributeType
-		Value AttributeValue
-		Oid   string
+func Test_encodingFromString(t *testing.T) {
+	type args struct {
+		name string
 	}
 	tests := []struct {
-		name   string
-		fields fields
-		want   string
+		name    string
+		args    args
+		want    Encoding
+		wantErr bool
 	}{
-		{"TestCase: OrganizationName AAA", fields{Type: OrganizationName, Value: AttributeValue{UTF8String, "AAA"}}, "O=AAA"},
-		{"TestCase: DnQualifier AAA", fields{Type: DnQualifier, Value: AttributeValue{UTF8String, "AAA"}}, "DNQUALIFIER=AAA"},
-		{"TestCase: Generic Oid=1.2.3 AAA", fields{Type: Generic, Value: AttributeValue{UTF8String, "AAA"}, Oid: "1.2.3"}, "1.2.3=AAA"},
-		{"TestCase: LocalityName  AAA", fields{Type: LocalityName, Value: AttributeValue{UTF8String, " AAA"}}, "L= AAA"},
-		{"TestCase: CommonName James (U+0022)Jim(U+0022) Smith, III", fields{Type: CommonName, Value: AttributeValue{UTF8String, "James \"Jim\" Smith, III"}}, "CN=James \"Jim\" Smith, III"},
+		{"TestCase: PrintableString", args{"PrintableString"}, PrintableString, false},
+		{"TestCase: UTF8String", args{"UTF8String"}, UTF8String, false},
+		{"TestCase: IA5String", args{"IA5String"}, IA5String, false},
+		{"TestCase: OIDValue", args{"OIDValue"}, OIDValue, false},
+		{"TestCase: BMPString", args{"BMPString"}, BMPString, false},
+		{"TestCase: TeletexString", args{"TeletexString"}, TeletexString, false},
+		{"TestCase: the other", args{"Not Supported Encoding"}, 0, true},
 	}
 	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			atv := AttributeTypeAndValue{
-				Type:  tt.fields.Type,
-				Value: tt.fields.Value,
-				Oid:   tt.fields.Oid,
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := encodingFromString(tt.args.name)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("encodingFromString() error = %v, wantErr %v", err, tt.wantErr)
+				return
 			}
-			if got := atv.String(); got != tt.want {
-				t.Errorf("String() = %v, want %v", got, tt.want)
+			if got != tt.want {
+				t.Errorf("encodingFromString() got = %v, want %v", got, tt.want)
 			}
 		})
 	}
 }
 
-func TestRDN_ToRFC4514FormatString(t *testing.T) {
+func Test_attributeTypeFromString(t *testing.T) {
 	tests := []struct {
-		name string
-		r    RDN
-		want string
+		name    string
+		s       string
+		want    AttributeType
+		wantErr bool
 	}{
-		{"TestCase: 0 RDN", RDN{}, ""},
-		{"TestCase: single RDN with leading SPACE",
-			RDN{AttributeTypeAndValue{Type: OrganizationName, Value: AttributeValue{UTF8String, " AAA"}}}, "O=\\ AAA"},
-		{"TestCase: single RDN with leading #",
-			RDN{AttributeTypeAndValue{Type: OrganizationName, Value: AttributeValue{UTF8String, "#AAA"}}}, "O=\\#AAA"},
-		{"TestCase: 2 RDN",
-			RDN{
-				AttributeTypeAndValue{Type: OrganizationName, Value: AttributeValue{UTF8String, "AAA"}},
-				AttributeTypeAndValue{Type: OrganizationName, Value: AttributeValue{UTF8String, "BBB"}},
-			},
-			"O=AAA+O=BBB"},
-		{"TestCase: 2 RDN with leading SPACE",
-			RDN{
-				AttributeTypeAndValue{Type: OrganizationName, Value: AttributeValue{UTF8String, " AAA"}},
-				AttributeTypeAndValue{Type: OrganizationName, Value: AttributeValue{UTF8String, " BBB"}},
-			},
-			"O=\\ AAA+O=\\ BBB"},
+		{"TestCase: CommonName", "CommonName", CommonName, false},
+		{"TestCase: OrganizationalUnit", "OrganizationUnit", OrganizationalUnit, false},
+		{"TestCase: UserID", "UserID", UserID, false},
+		{"TestCase: Generic", "Generic", Generic, false},
+		{"TestCase: short name", "cn", 0, true},
+		{"TestCase: UnKnown", "UnKnown", 0, true},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if got := tt.r.ToRFC4514FormatString(); got != tt.want {
-				t.Errorf("ToRFC4514FormatString() = %v, want %v", got, tt.want)
+			got, err := attributeTypeFromString(tt.s)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("attributeTypeFromString() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("attributeTypeFromString() got = %v, want %v", got, tt.want)
 			}
 		})
 	}
+	for at := CountryName; at <= UserID; at++ {
+		if got, err := attributeTypeFromString(at.String()); err != nil || got != at {
+			t.Errorf("attributeTypeFromString(%q) = %v, %v, want %v", at.String(), got, err, at)
+		}
+	}
 }
 
-func TestRDN_String(t *testing.T) {
+func TestAttributeType_MarshalJSON(t *testing.T) {
 	tests := []struct {
-		name string
-		rdn  RDN
-		want string
+		name    string
+		a       AttributeType
+		want    string
+		wantErr bool
 	}{
-		{"TestCase: 0 RDN", RDN{}, ""},
-		{"TestCase: single RDN",
-			RDN{AttributeTypeAndValue{Type: OrganizationName, Value: AttributeValue{UTF8String, "AAA"}}},
-			"O=AAA"},
-		{"TestCase: single RDN with leading SPACE",
-			RDN{AttributeTypeAndValue{Type: OrganizationName, Value: AttributeValue{UTF8String, " AAA"}}}, "O= AAA"},
-		{"TestCase: single RDN with leading #",
-			RDN{AttributeTypeAndValue{Type: OrganizationName, Value: AttributeValue{UTF8String, "#AAA"}}}, "O=#AAA"},
-
-		{"TestCase: 2 RDN",
-			RDN{
-				AttributeTypeAndValue{Type: OrganizationName, Value: AttributeValue{UTF8String, "AAA"}},
-				AttributeTypeAndValue{Type: OrganizationName, Value: AttributeValue{UTF8String, "BBB"}},
-			},
-			"O=AAA+O=BBB"},
+		{"TestCase: CommonName", CommonName, `"CommonName"`, false},
+		{"TestCase: OrganizationalUnit", OrganizationalUnit, `"OrganizationUnit"`, false},
+		{"TestCase: Generic", Generic, `"Generic"`, false},
+		{"TestCase: not supported AttributeType", AttributeType(0), "", true},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if got := tt.rdn.String(); got != tt.want {
-				t.Errorf("String() = %v, want %v", got, tt.want)
+			got, err := json.Marshal(tt.a)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("MarshalJSON() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if string(got) != tt.want {
+				t.Errorf("MarshalJSON() = %s, want %s", got, tt.want)
 			}
 		})
 	}
 }
 
-func TestDN_ReverseDnOrder(t *testing.T) {
-	rdn1 := RDN{AttributeTypeAndValue{Type: OrganizationName, Value: AttributeValue{UTF8String, "AAA"}}}
-	rdn2 := RDN{AttributeTypeAndValue{Type: CountryName, Value: AttributeValue{UTF8String, "JP"}}}
-	atv1 := AttributeTypeAndValue{Type: CommonName, Value: AttributeValue{UTF8String, "Mike"}}
-	atv2 := AttributeTypeAndValue{Type: ElectronicMailAddress, Value: AttributeValue{IA5String, "Mike@example.org"}}
-	rdn3 := RDN{atv1, atv2}
-	rdn4 := RDN{AttributeTypeAndValue{Type: OrganizationName, Value: AttributeValue{UTF8String, "#株式会社Example"}}}
-
+func TestAttributeType_UnmarshalJSON(t *testing.T) {
 	tests := []struct {
-		name string
-		d    DN
-		want DN
+		name    string
+		b       string
+		want    AttributeType
+		wantErr bool
 	}{
-		{"TestCase: 0 RDN", DN{}, DN{}},
-		{"TestCase: o,c", DN{rdn1}, DN{rdn1}},
-		{"TestCase: o,c", DN{rdn1, rdn2}, DN{rdn2, rdn1}},
-		{"TestCase: cn+email,c", DN{rdn3, rdn2}, DN{rdn2, rdn3}},
-		{"TestCase: escaped o,c", DN{rdn4, rdn2}, DN{rdn2, rdn4}},
-		{"TestCase: cn+email,o,c", DN{rdn3, rdn1, rdn2}, DN{rdn2, rdn1, rdn3}},
+		{"TestCase: CommonName", `"CommonName"`, CommonName, false},
+		{"TestCase: UserID", `"UserID"`, UserID, false},
+		{"TestCase: Generic", `"Generic"`, Generic, false},
+		{"TestCase: unknown name", `"commonName"`, 0, true},
+		{"TestCase: integer", `6`, 0, true},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if got := tt.d.ReverseDnOrder(); !reflect.DeepEqual(got, tt.want) {
-				t.Errorf("ReverseDnOrder() = %v, want %v", got, tt.want)
+			var got AttributeType
+			err := json.Unmarshal([]byte(tt.b), &got)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("UnmarshalJSON() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("UnmarshalJSON() = %v, want %v", got, tt.want)
 			}
 		})
 	}
+	m := map[string]AttributeType{"a": CountryName, "b": Generic}
+	b, err := json.Marshal(m)
+	if err != nil || string(b) != `{"a":"CountryName","b":"Generic"}` {
+		t.Errorf("json.Marshal() = %s, %v", b, err)
+	}
 }
 
-func TestDN_ToRFC4514FormatString(t *testing.T) {
-	rdn1 := RDN{AttributeTypeAndValue{Type: OrganizationName, Value: AttributeValue{UTF8String, "AAA"}}}
-	rdn2 := RDN{AttributeTypeAndValue{Type: CountryName, Value: AttributeValue{UTF8String, "JP"}}}
-	atv1 := AttributeTypeAndValue{Type: CommonName, Value: AttributeValue{UTF8String, "Mike"}}
-	atv2 := AttributeTypeAndValue{Type: ElectronicMailAddress, Value: AttributeValue{IA5String, "Mike@example.org"}}
-	rdn3 := RDN{atv1, atv2}
-	rdn4 := RDN{AttributeTypeAndValue{Type: OrganizationName, Value: AttributeValue{UTF8String, "#株式会社Example"}}}
-	rdn5 := RDN{AttributeTypeAndValue{Type: Generic, Value: AttributeValue{UTF8String, "AAA"}, Oid: "1.2.3.4"}}
+func TestAttributeValue_MarshalJSON(t *testing.T) {
 	tests := []struct {
-		name string
-		d    DN
-		want string
+		name    string
+		av      AttributeValue
+		want    string
+		wantErr bool
 	}{
-		{"TestCase: 0 RDN", DN{}, ""},
-		{"TestCase: o,c", DN{rdn1, rdn2}, "C=JP,O=AAA"},
-		{"TestCase: 1.2.3.4,o,c", DN{rdn5, rdn1, rdn2}, "C=JP,O=AAA,1.2.3.4=AAA"},
-		{"TestCase: cn+email,c", DN{rdn3, rdn2}, "C=JP,CN=Mike+EMAIL=Mike@example.org"},
-		{"TestCase: escaped o,c", DN{rdn4, rdn2}, "C=JP,O=\\#株式会社Example"},
-		{"TestCase: cn+email,o,c", DN{rdn3, rdn1, rdn2}, "C=JP,O=AAA,CN=Mike+EMAIL=Mike@example.org"},
+		{"TestCase: UTF8String", AttributeValue{UTF8String, "abc"}, `{"Encoding":"UTF8String","Value":"abc"}`, false},
+		{"TestCase: OIDValue", AttributeValue{OIDValue, "1.2.3"}, `{"Encoding":"OIDValue","Value":"1.2.3"}`, false},
+		{"TestCase: not supported Encoding", AttributeValue{Encoding(999), "abc"}, `{"Encoding":"Not Supported Encoding","Value":"abc"}`, false},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if got := tt.d.ToRFC4514FormatString(); got != tt.want {
-				t.Errorf("ToRFC4514FormatString() = %v, want %v", got, tt.want)
+			got, err := json.Marshal(tt.av)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("MarshalJSON() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if string(got) != tt.want {
+				t.Errorf("MarshalJSON() = %s, want %s", got, tt.want)
 			}
 		})
 	}
 }
 
-func TestDN_String(t *testing.T) {
-	rdn1 := RDN{AttributeTypeAndValue{Type: OrganizationName, Value: AttributeValue{UTF8String, "AAA"}}}
-	rdn2 := RDN{AttributeTypeAndValue{Type: CountryName, Value: AttributeValue{UTF8String, "JP"}}}
-	atv1 := AttributeTypeAndValue{Type: CommonName, Value: AttributeValue{UTF8String, "Mike"}}
-	atv2 := AttributeTypeAndValue{Type: ElectronicMailAddress, Value: AttributeValue{IA5String, "Mike@example.org"}}
-	rdn3 := RDN{atv1, atv2}
-	rdn4 := RDN{AttributeTypeAndValue{Type: OrganizationName, Value: AttributeValue{UTF8String, "#株式会社Example"}}}
-
+func TestAttributeValue_UnmarshalJSON(t *testing.T) {
 	tests := []struct {
-		name string
-		d    DN
-		want string
+		name    string
+		b       string
+		want    AttributeValue
+		wantErr bool
 	}{
-		{"TestCase: 0 RDN", DN{}, ""},
-		{"TestCase: o,c", DN{rdn1, rdn2}, "O=AAA,C=JP"},
-		{"TestCase: cn+email,c", DN{rdn3, rdn2}, "CN=Mike+EMAIL=Mike@example.org,C=JP"},
-		{"TestCase: escaped o,c", DN{rdn4, rdn2}, "O=#株式会社Example,C=JP"},
-		{"TestCase: cn+email,o,c", DN{rdn3, rdn1, rdn2}, "CN=Mike+EMAIL=Mike@example.org,O=AAA,C=JP"},
+		{"TestCase: PrintableString", `{"Encoding":"PrintableString","Value":"JP"}`, AttributeValue{PrintableString, "JP"}, false},
+		{"TestCase: unknown Encoding", `{"Encoding":"UTF16String","Value":"JP"}`, AttributeValue{}, true},
+		{"TestCase: integer Encoding", `{"Encoding":1,"Value":"JP"}`, AttributeValue{}, true},
+		{"TestCase: not object", `"JP"`, AttributeValue{}, true},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if got := tt.d.String(); got != tt.want {
-				t.Errorf("String() = %v, want %v", got, tt.want)
+			var got AttributeValue
+			err := json.Unmarshal([]byte(tt.b), &got)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("UnmarshalJSON() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("UnmarshalJSON() = %v, want %v", got, tt.want)
 			}
 		})
 	}
 }
 
-func Test_convertToObjectIdentifier(t *testing.T) {
-	type args struct {
-		o string
-	}
+func TestAttributeTypeAndValue_MarshalJSON(t *testing.T) {
 	tests := []struct {
 		name    string
-		args    args
-		wantOid asn1.ObjectIdentifier
+		atv     AttributeTypeAndValue
+		want    string
 		wantErr bool
 	}{
-		{"TestCase: 1.2.3.4", args{"1.2.3.4"}, asn1.ObjectIdentifier{1, 2, 3, 4}, false},
-		{"TestCase: 1", args{"1"}, asn1.ObjectIdentifier{1}, false},
-		{"TestCase: 1.-3", args{"1.-3"}, nil, true},
-		{"TestCase: .", args{"."}, nil, true},
-		{"TestCase: 1.2.", args{"1.2."}, nil, true},
-		{"TestCase: 1.", args{"1."}, nil, true},
-		{"TestCase: ..", args{".."}, nil, true},
-		{"TestCase: 1.X.3", args{"1.X.3"}, nil, true},
-		{"TestCase: X.Y.Z", args{"X.Y.Z"}, nil, true},
-		{"TestCase: 1,2,3,4", args{"1,2,3,4"}, nil, true},
-		{"TestCase: blank", args{""}, nil, true},
+		{"TestCase: CommonName", AttributeTypeAndValue{Type: CommonName, Value: AttributeValue{UTF8String, "abc"}},
+			`{"Type":"CommonName","Value":{"Encoding":"UTF8String","Value":"abc"}}`, false},
+		{"TestCase: Generic", AttributeTypeAndValue{Type: Generic, Oid: "1.2.3.4", Value: AttributeValue{IA5String, "abc"}},
+			`{"Type":"Generic","Value":{"Encoding":"IA5String","Value":"abc"},"Oid":"1.2.3.4"}`, false},
+		{"TestCase: not supported AttributeType", AttributeTypeAndValue{Type: AttributeType(999), Value: AttributeValue{UTF8String, "abc"}}, "", true},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			gotOid, err := convertToObjectIdentifier(tt.args.o)
+			got, err := json.Marshal(tt.atv)
 			if (err != nil) != tt.wantErr {
-				t.Errorf("convertToObjectIdentifier() error = %v, wantErr %v", err, tt.wantErr)
+				t.Errorf("MarshalJSON() error = %v, wantErr %v", err, tt.wantErr)
 				return
 			}
-			if !reflect.DeepEqual(gotOid, tt.wantOid) {
-				t.Errorf("convertToObjectIdentifier() gotOid = %v, want %v", gotOid, tt.wantOid)
+			if string(got) != tt.want {
+				t.Errorf("MarshalJSON() = %s, want %s", got, tt.want)
 			}
 		})
 	}
 }
 
-func TestAttributeTypeAndValue_toShortName(t *testing.T) {
-	type fields struct {
-		Type  AttributeType
-		Value AttributeValue
-		Oid   string
-	}
+func TestAttributeTypeAndValue_UnmarshalJSON(t *testing.T) {
 	tests := []struct {
-		name   string
-		fields fields
-		want   string
+		name    string
+		b       string
+		want    AttributeTypeAndValue
+		wantErr bool
 	}{
-		{"TestCase:CountryName", fields{Type: CountryName, Value: AttributeValue{}}, "c"},
-		{"TestCase:OrganizationName", fields{Type: OrganizationName, Value: AttributeValue{}}, "o"},
-		{"TestCase:OrganizationalUnit", fields{Type: OrganizationalUnit, Value: AttributeValue{}}, "ou"},
-		{"TestCase:DnQualifier", fields{Type: DnQualifier, Value: AttributeValue{}}, "dnQualifier"},
-		{"TestCase:StateOrProvinceName", fields{Type: StateOrProvinceName, Value: AttributeValue{}}, "st"},
-		{"TestCase:CommonName", fields{Type: CommonName, Value: AttributeValue{}}, "cn"},
-		{"TestCase:SerialNumber", fields{Type: SerialNumber, Value: AttributeValue{}}, "serialNumber"},
-		{"TestCase:LocalityName", fields{Type: LocalityName, Value: AttributeValue{}}, "L"},
-		{"TestCase:Title", fields{Type: Title, Value: AttributeValue{}}, "title"},
-		{"TestCase:Surname", fields{Type: Surname, Value: AttributeValue{}}, "sn"},
-		{"TestCase:GivenName", fields{Type: GivenName, Value: AttributeValue{}}, "givenName"},
-		{"TestCase:Initials", fields{Type: Initials, Value: AttributeValue{}}, "initials"},
-		{"TestCase:Pseudonym", fields{Type: Pseudonym, Value: AttributeValue{}}, "pseudonym"},
-		{"TestCase:GenerationQualifier", fields{Type: GenerationQualifier, Value: AttributeValue{}}, "generationQualifier"},
-		{"TestCase:ElectronicMailAddress", fields{Type: ElectronicMailAddress, Value: AttributeValue{}}, "email"},
-		{"TestCase:DomainComponent", fields{Type: DomainComponent, Value: AttributeValue{}}, "DC"},
-		{"TestCase:Generic", fields{Type: Generic, Oid: "1.2.3", Value: AttributeValue{}}, "1.2.3"},
-		{"TestCase:Generic(OrganizationName)", fields{Type: Generic, Oid: "2.5.4.10", Value: AttributeValue{}}, "o"},
-		{"TestCase:Generic(broken oid)", fields{Type: Generic, Oid: "broken oid", Value: AttributeValue{}}, "UnKnown"},
-		{"TestCase:UnKnownAttributeType", fields{Type: AttributeType(9999)}, "UnKnown"},
+		{"TestCase: CommonName", `{"Type":"CommonName","Value":{"Encoding":"UTF8String","Value":"abc"}}`,
+			AttributeTypeAndValue{Type: CommonName, Value: AttributeValue{UTF8String, "abc"}}, false},
+		{"TestCase: Generic", `{"Type":"Generic","Value":{"Encoding":"IA5String","Value":"abc"},"Oid":"1.2.3.4"}`,
+			AttributeTypeAndValue{Type: Generic, Oid: "1.2.3.4", Value: AttributeValue{IA5String, "abc"}}, false},
+		{"TestCase: unknown AttributeType", `{"Type":"Street","Value":{"Encoding":"UTF8String","Value":"abc"}}`, AttributeTypeAndValue{}, true},
+		{"TestCase: integer AttributeType", `{"Type":6,"Value":{"Encoding":"UTF8String","Value":"abc"}}`, AttributeTypeAndValue{}, true},
+		{"TestCase: unknown Encoding", `{"Type":"CommonName","Value":{"Encoding":"UTF16String","Value":"abc"}}`, AttributeTypeAndValue{}, true},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			a := AttributeTypeAndValue{
-				Type:  tt.fields.Type,
-				Value: tt.fields.Value,
-				Oid:   tt.fields.Oid,
+			var got AttributeTypeAndValue
+			err := json.Unmarshal([]byte(tt.b), &got)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("UnmarshalJSON() error = %v, wantErr %v", err, tt.wantErr)
+				return
 			}
-			if got := a.toShortName(); got != tt.want {
-				t.Errorf("toShortName() = %v, want %v", got, tt.want)
+			if got != tt.want {
+				t.Errorf("UnmarshalJSON() = %v, want %v", got, tt.want)
 			}
 		})
 	}
 }
 
-func Test_toDefinedShortName(t *testing.T) {
+func TestDN_JSON(t *testing.T) {
+	dn := DN{
+		RDN{AttributeTypeAndValue{Type: CountryName, Value: AttributeValue{PrintableString, "JP"}}},
+		RDN{
+			AttributeTypeAndValue{Type: CommonName, Value: AttributeValue{UTF8String, "Mike"}},
+			AttributeTypeAndValue{Type: Generic, Oid: "1.2.3.4", Value: AttributeValue{UTF8String, "x"}},
+		},
+	}
+	want := `[[{"Type":"CountryName","Value":{"Encoding":"PrintableString","Value":"JP"}}],` +
+		`[{"Type":"CommonName","Value":{"Encoding":"UTF8String","Value":"Mike"}},` +
+		`{"Type":"Generic","Value":{"Encoding":"UTF8String","Value":"x"},"Oid":"1.2.3.4"}]]`
+	b, err := json.Marshal(dn)
+	if err != nil {
+		t.Fatalf("json.Marshal() error = %v", err)
+	}
+	if string(b) != want {
+		t.Errorf("json.Marshal() = %s, want %s", b, want)
+	}
+	var got DN
+	if err := json.Unmarshal(b, &got); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, dn) {
+		t.Errorf("json.Unmarshal() = %v, want %v", got, dn)
+	}
+}
+
+func Test_attributeTypeFromName(t *testing.T) {
 	type args struct {
-		a AttributeType
+		name string
 	}
 	tests := []struct {
-		name string
-		args args
-		want string
+		name    string
+		args    args
+		want    AttributeTypeAndValue
+		wantErr bool
 	}{
-		{"TestCase:CountryName", args{CountryName}, "c"},
-		{"TestCase:OrganizationName", args{OrganizationName}, "o"},
-		{"TestCase:OrganizationalUnit", args{OrganizationalUnit}, "ou"},
-		{"TestCase:DnQualifier", args{DnQualifier}, "dnQualifier"},
-		{"TestCase:StateOrProvinceName", args{StateOrProvinceName}, "st"},
-		{"TestCase:CommonName", args{CommonName}, "cn"},
-		{"TestCase:SerialNumber", args{SerialNumber}, "serialNumber"},
-		{"TestCase:LocalityName", args{LocalityName}, "L"},
-		{"TestCase:Title", args{Title}, "title"},
-		{"TestCase:Surname", args{Surname}, "sn"},
-		{"TestCase:GivenName", args{GivenName}, "givenName"},
-		{"TestCase:Initials", args{Initials}, "initials"},
-		{"TestCase:Pseudonym", args{Pseudonym}, "pseudonym"},
-		{"TestCase:GenerationQualifier", args{GenerationQualifier}, "generationQualifier"},
-		{"TestCase:ElectronicMailAddress", args{ElectronicMailAddress}, "email"},
-		{"TestCase:DomainComponent", args{DomainComponent}, "DC"},
-		{"TestCase:Generic", args{Generic}, "Generic"},
-		{"TestCase:UnKnownAttributeType", args{AttributeType(9999)}, "UnKnown"},
+		{"TestCase: short name", args{"CN"}, AttributeTypeAndValue{Type: CommonName}, false},
+		{"TestCase: long name", args{"organizationName"}, AttributeTypeAndValue{Type: OrganizationName}, false},
+		{"TestCase: known oid", args{"2.5.4.6"}, AttributeTypeAndValue{Type: CountryName}, false},
+		{"TestCase: unknown oid", args{"1.2.3.4"}, AttributeTypeAndValue{Type: Generic, Oid: "1.2.3.4"}, false},
+		{"TestCase: unknown name", args{"foo"}, AttributeTypeAndValue{}, true},
+		{"TestCase: invalid oid", args{"1..2"}, AttributeTypeAndValue{}, true},
+		{"TestCase: blank", args{""}, AttributeTypeAndValue{}, true},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if got := toDefinedShortName(tt.args.a); got != tt.want {
-				t.Errorf("toDefinedShortName() = %v, want %v", got, tt.want)
+			got, err := attributeTypeFromName(tt.args.name)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("attributeTypeFromName() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("attributeTypeFromName() got = %v, want %v", got, tt.want)
 			}
 		})
 	}
 }
 
-func TestValidateCountryCode(t *testing.T) {
+func TestFromFlexibleJSON(t *testing.T) {
+	dc := RDN{AttributeTypeAndValue{Type: DomainComponent, Value: AttributeValue{IA5String, "example"}}}
+	c := RDN{AttributeTypeAndValue{Type: CountryName, Value: AttributeValue{PrintableString, "JP"}}}
+	o := RDN{AttributeTypeAndValue{Type: OrganizationName, Value: AttributeValue{UTF8String, "bar"}}}
+	ou := RDN{AttributeTypeAndValue{Type: OrganizationalUnit, Value: AttributeValue{UTF8String, "Dev"}}}
+	title := RDN{AttributeTypeAndValue{Type: Title, Value: AttributeValue{UTF8String, "Engineer"}}}
+	g := RDN{AttributeTypeAndValue{Type: Generic, Oid: "1.2.3.4", Value: AttributeValue{UTF8String, "x"}}}
+	cn := RDN{AttributeTypeAndValue{Type: CommonName, Value: AttributeValue{UTF8String, "foo"}}}
+	e := RDN{AttributeTypeAndValue{Type: ElectronicMailAddress, Value: AttributeValue{IA5String, "foo@example.com"}}}
 	type args struct {
-		c string
+		b []byte
 	}
 	tests := []struct {
 		name    string
 		args    args
-		want    bool
+		wantDn  DN
 		wantErr bool
 	}{
-		{"TestCase:valid country code", args{c: "JP"}, true, false},
-		{"TestCase:valid country code", args{c: "US"}, true, false},
-		{"TestCase:invalid country code JPN", args{c: "JPN"}, false, true},
-		{"TestCase:invalid country code XYZ", args{c: "XYZ"}, false, true},
-		{"TestCase:empty country code", args{c: ""}, false, true},
+		{"TestCase: empty object", args{[]byte("{}")}, DN{}, false},
+		{"TestCase: object", args{[]byte(`{"CN":"foo","O":"bar","C":"JP"}`)}, DN{c, o, cn}, false},
+		{"TestCase: object ordered by rank", args{[]byte(`{"email":"foo@example.com","title":"Engineer","1.2.3.4":"x","CN":"foo","OU":"Dev","dc":"example","O":"bar","C":"JP"}`)}, DN{dc, c, o, ou, g, title, cn, e}, false},
+		{"TestCase: object unknown name", args{[]byte(`{"foo":"bar"}`)}, nil, true},
+		{"TestCase: object not string value", args{[]byte(`{"CN":1}`)}, nil, true},
+		{"TestCase: empty array", args{[]byte(" []")}, DN{}, false},
+		{"TestCase: array", args{[]byte(`[{"type":"C","value":"JP"},{"type":"O","value":"bar"},{"type":"CN","value":"foo"}]`)}, DN{c, o, cn}, false},
+		{"TestCase: array keeps order", args{[]byte(`[{"type":"CN","value":"foo"},{"type":"C","value":"JP"}]`)}, DN{cn, c}, false},
+		{"TestCase: array unknown type", args{[]byte(`[{"type":"foo","value":"bar"}]`)}, nil, true},
+		{"TestCase: array of strings", args{[]byte(`["CN=foo"]`)}, nil, true},
+		{"TestCase: blank", args{[]byte("  ")}, nil, true},
+		{"TestCase: string", args{[]byte(`"CN=foo"`)}, nil, true},
+		{"TestCase: broken", args{[]byte(`{"CN":`)}, nil, true},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got, err := ValidateCountryCode(tt.args.c)
+			gotDn, err := FromFlexibleJSON(tt.args.b)
 			if (err != nil) != tt.wantErr {
-				t.Errorf("ValidateCountryCode() error = %v, wantErr %v", err, tt.wantErr)
+				t.Errorf("FromFlexibleJSON() error = %v, wantErr %v", err, tt.wantErr)
 				return
 			}
-			if got != tt.want {
-				t.Errorf("ValidateCountryCode() got = %v, want %v", got, tt.want)
+			if !reflect.DeepEqual(gotDn, tt.wantDn) {
+				t.Errorf("FromFlexibleJSON() gotDn = %v, want %v", gotDn, tt.wantDn)
 			}
 		})
 	}
 }
+
+func Test_flexibleJSONRank(t *testing.T) {
+	ats := []AttributeType{DomainComponent, CountryName, StateOrProvinceName, LocalityName, OrganizationName, OrganizationalUnit, Title, CommonName, ElectronicMailAddress}
+	for i := 1; i < len(ats); i++ {
+		if flexibleJSONRank(ats[i-1]) >= flexibleJSONRank(ats[i]) {
+			t.Errorf("flexibleJSONRank(%v) >= flexibleJSONRank(%v)", ats[i-1], ats[i])
+		}
+	}
+	if flexibleJSONRank(Generic) != flexibleJSONRank(Title) {
+		t.Errorf("flexibleJSONRank(Generic) != flexibleJSONRank(Title)")
+	}
+}